@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import "context"
+
+const (
+	// AnonymousPrincipal is the principal reported by AnonymousToken.  Since a real
+	// principal could have this same name, use IsAnonymous rather than comparing
+	// principals to detect anonymous callers.
+	AnonymousPrincipal = "anonymous"
+)
+
+// AnonymousToken is the well-known Token used for a caller that presented no
+// credentials.  Integrations that support optional authentication place this token
+// in the context in place of an authenticated token.
+type AnonymousToken struct{}
+
+// Principal always returns AnonymousPrincipal.
+func (AnonymousToken) Principal() string { return AnonymousPrincipal }
+
+// IsAnonymous tests if the given token represents a caller that did not authenticate.
+// This function returns true if t is nil or if t's token tree contains an AnonymousToken.
+func IsAnonymous(t Token) bool {
+	if t == nil {
+		return true
+	}
+
+	var at AnonymousToken
+	return TokenAs(t, &at)
+}
+
+// RequireAuthenticated returns an Approver that denies access to anonymous callers,
+// as determined by IsAnonymous.  The returned error is ErrMissingCredentials, so that
+// integrations such as basculehttp respond as though no credentials were presented,
+// e.g. with a 401 and any configured challenges.
+func RequireAuthenticated[R any]() Approver[R] {
+	return ApproverFunc[R](func(_ context.Context, _ R, token Token) error {
+		if IsAnonymous(token) {
+			return ErrMissingCredentials
+		}
+
+		return nil
+	})
+}
+
+// IfAuthenticated returns an Approver that applies the given approvers only to
+// authenticated callers.  Anonymous callers, as determined by IsAnonymous, are
+// approved without consulting any of the approvers.  For authenticated callers,
+// all approvers must allow access, with the same semantics as Approvers.Approve.
+//
+// This is useful for public resources that are further restricted once a caller
+// authenticates, e.g. to deny access to a token that has been downgraded.
+func IfAuthenticated[R any](approvers ...Approver[R]) Approver[R] {
+	as := copyApprovers(approvers)
+	return ApproverFunc[R](func(ctx context.Context, resource R, token Token) error {
+		if IsAnonymous(token) {
+			return nil
+		}
+
+		return as.Approve(ctx, resource, token)
+	})
+}
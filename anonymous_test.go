@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AnonymousTestSuite struct {
+	TestSuite
+}
+
+func (suite *AnonymousTestSuite) TestAnonymousToken() {
+	suite.Equal(AnonymousPrincipal, AnonymousToken{}.Principal())
+}
+
+func (suite *AnonymousTestSuite) TestIsAnonymous() {
+	suite.True(IsAnonymous(nil))
+	suite.True(IsAnonymous(AnonymousToken{}))
+	suite.True(IsAnonymous(JoinTokens(AnonymousToken{}, StubToken("other"))))
+
+	suite.False(IsAnonymous(suite.testToken()))
+	suite.False(IsAnonymous(StubToken(AnonymousPrincipal)))
+}
+
+func (suite *AnonymousTestSuite) TestRequireAuthenticated() {
+	a := RequireAuthenticated[string]()
+
+	suite.NoError(a.Approve(suite.testContext(), "resource", suite.testToken()))
+	suite.ErrorIs(a.Approve(suite.testContext(), "resource", AnonymousToken{}), ErrMissingCredentials)
+	suite.ErrorIs(a.Approve(suite.testContext(), "resource", nil), ErrMissingCredentials)
+}
+
+func (suite *AnonymousTestSuite) TestIfAuthenticated() {
+	suite.Run("Anonymous", func() {
+		approver := new(mockApprover[string])
+		a := IfAuthenticated[string](approver)
+
+		suite.NoError(a.Approve(suite.testContext(), "resource", AnonymousToken{}))
+		approver.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Approved", func() {
+		approver := new(mockApprover[string])
+		approver.ExpectApprove(suite.testContext(), "resource", suite.testToken()).Return(nil).Once()
+		a := IfAuthenticated[string](approver)
+
+		suite.NoError(a.Approve(suite.testContext(), "resource", suite.testToken()))
+		approver.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Denied", func() {
+		var (
+			approver1 = new(mockApprover[string])
+			approver2 = new(mockApprover[string])
+		)
+
+		approver1.ExpectApprove(suite.testContext(), "resource", suite.testToken()).Return(ErrUnauthorized).Once()
+		a := IfAuthenticated[string](approver1, approver2)
+
+		suite.ErrorIs(a.Approve(suite.testContext(), "resource", suite.testToken()), ErrUnauthorized)
+		approver1.AssertExpectations(suite.T())
+		approver2.AssertExpectations(suite.T())
+	})
+
+	suite.Run("NoApprovers", func() {
+		a := IfAuthenticated[string]()
+		suite.NoError(a.Approve(suite.testContext(), "resource", suite.testToken()))
+	})
+}
+
+func TestAnonymous(t *testing.T) {
+	suite.Run(t, new(AnonymousTestSuite))
+}
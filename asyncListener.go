@@ -0,0 +1,293 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultAsyncQueueSize is the default capacity of an AsyncListener's queue.
+	DefaultAsyncQueueSize = 1000
+
+	// DefaultAsyncWorkers is the default number of goroutines that dispatch
+	// events for an AsyncListener.
+	DefaultAsyncWorkers = 1
+)
+
+var (
+	// ErrInvalidAsyncConfig indicates that an AsyncListener option was given
+	// an invalid value, e.g. a nonpositive queue size.
+	ErrInvalidAsyncConfig = errors.New("invalid async listener configuration")
+)
+
+// OverflowPolicy describes what an AsyncListener does with an event when its
+// queue is full.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the incoming event when the queue is full.  This is
+	// the default policy.
+	DropNewest OverflowPolicy = iota
+
+	// DropOldest discards the oldest queued event to make room for the
+	// incoming event.
+	DropOldest
+
+	// Block waits for room in the queue.  This policy can block callers of
+	// OnEvent, and should only be used when no event can be lost.  Callers
+	// blocked when Shutdown is called are released, and their events dropped.
+	Block
+)
+
+// AsyncListenerOption is a configurable option for an AsyncListener.
+type AsyncListenerOption[E any] interface {
+	apply(*AsyncListener[E]) error
+}
+
+type asyncListenerOptionFunc[E any] func(*AsyncListener[E]) error
+
+//nolint:unused
+func (alof asyncListenerOptionFunc[E]) apply(al *AsyncListener[E]) error { return alof(al) }
+
+// WithQueueSize sets the maximum number of events that may be waiting for
+// dispatch.  If this option is not supplied, DefaultAsyncQueueSize is used.
+func WithQueueSize[E any](size int) AsyncListenerOption[E] {
+	return asyncListenerOptionFunc[E](func(al *AsyncListener[E]) error {
+		if size < 1 {
+			return ErrInvalidAsyncConfig
+		}
+
+		al.queueSize = size
+		return nil
+	})
+}
+
+// WithWorkers sets the number of goroutines that dispatch events.  With more than
+// one worker, events may be delivered out of order.  If this option is not
+// supplied, DefaultAsyncWorkers is used.
+func WithWorkers[E any](workers int) AsyncListenerOption[E] {
+	return asyncListenerOptionFunc[E](func(al *AsyncListener[E]) error {
+		if workers < 1 {
+			return ErrInvalidAsyncConfig
+		}
+
+		al.workers = workers
+		return nil
+	})
+}
+
+// WithOverflowPolicy sets what happens to events when the queue is full.
+// If this option is not supplied, DropNewest is used.
+func WithOverflowPolicy[E any](p OverflowPolicy) AsyncListenerOption[E] {
+	return asyncListenerOptionFunc[E](func(al *AsyncListener[E]) error {
+		switch p {
+		case DropNewest, DropOldest, Block:
+			al.policy = p
+			return nil
+
+		default:
+			return ErrInvalidAsyncConfig
+		}
+	})
+}
+
+// WithPanicHandler sets a closure that is invoked whenever the wrapped listener
+// panics.  The closure receives the event and the recovered value.  Panics are
+// always recovered and counted, whether or not a handler is set.  A panic from the
+// handler itself is also recovered and counted, but is otherwise discarded.
+func WithPanicHandler[E any](f func(E, any)) AsyncListenerOption[E] {
+	return asyncListenerOptionFunc[E](func(al *AsyncListener[E]) error {
+		al.onPanic = f
+		return nil
+	})
+}
+
+// AsyncListener is a Listener that decouples event dispatch from the caller.  Events
+// are placed onto a bounded queue and delivered to a wrapped Listener by one or more
+// worker goroutines.  Panics from the wrapped Listener are recovered.
+//
+// An AsyncListener must be shut down with Shutdown when no longer needed.  Events
+// received after shutdown are dropped.
+type AsyncListener[E any] struct {
+	next      Listener[E]
+	queueSize int
+	workers   int
+	policy    OverflowPolicy
+	onPanic   func(E, any)
+
+	lock      sync.RWMutex
+	closed    bool
+	queue     chan E
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+	dropped   atomic.Uint64
+	panics    atomic.Uint64
+}
+
+// NewAsyncListener wraps the given Listener so that events are dispatched
+// asynchronously.  The worker goroutines are started before this function returns.
+func NewAsyncListener[E any](next Listener[E], opts ...AsyncListenerOption[E]) (*AsyncListener[E], error) {
+	if next == nil {
+		return nil, ErrInvalidAsyncConfig
+	}
+
+	al := &AsyncListener[E]{
+		next:      next,
+		queueSize: DefaultAsyncQueueSize,
+		workers:   DefaultAsyncWorkers,
+		policy:    DropNewest,
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	for _, o := range opts {
+		if err := o.apply(al); err != nil {
+			return nil, err
+		}
+	}
+
+	al.queue = make(chan E, al.queueSize)
+
+	var wg sync.WaitGroup
+	wg.Add(al.workers)
+	for range al.workers {
+		go func() {
+			defer wg.Done()
+			for e := range al.queue {
+				al.dispatch(e)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(al.done)
+	}()
+
+	return al, nil
+}
+
+// dispatch sends a single event to the wrapped listener, recovering any panic.
+func (al *AsyncListener[E]) dispatch(e E) {
+	defer func() {
+		if r := recover(); r != nil {
+			al.panics.Add(1)
+			al.handlePanic(e, r)
+		}
+	}()
+
+	al.next.OnEvent(e)
+}
+
+// handlePanic invokes the panic handler, if any.  A panic from the handler itself
+// is counted and discarded so that it cannot crash a worker goroutine.
+func (al *AsyncListener[E]) handlePanic(e E, r any) {
+	if al.onPanic == nil {
+		return
+	}
+
+	defer func() {
+		if recover() != nil {
+			al.panics.Add(1)
+		}
+	}()
+
+	al.onPanic(e, r)
+}
+
+// OnEvent enqueues the event for dispatch, applying the overflow policy if the
+// queue is full.
+func (al *AsyncListener[E]) OnEvent(e E) {
+	al.lock.RLock()
+	defer al.lock.RUnlock()
+
+	if al.closed {
+		al.dropped.Add(1)
+		return
+	}
+
+	switch al.policy {
+	case Block:
+		// the read lock is held while blocked, so this send must be
+		// interruptible in order for Shutdown to acquire the write lock
+		select {
+		case al.queue <- e:
+		case <-al.closing:
+			al.dropped.Add(1)
+		}
+
+	case DropOldest:
+		for {
+			select {
+			case al.queue <- e:
+				return
+
+			default:
+				select {
+				case <-al.queue:
+					al.dropped.Add(1)
+
+				default:
+					// a worker took the oldest event first
+				}
+			}
+		}
+
+	default:
+		select {
+		case al.queue <- e:
+		default:
+			al.dropped.Add(1)
+		}
+	}
+}
+
+// Pending returns the number of events waiting to be dispatched.
+func (al *AsyncListener[E]) Pending() int {
+	return len(al.queue)
+}
+
+// Dropped returns the total number of events that were discarded, either due
+// to the overflow policy or because they arrived after shutdown.
+func (al *AsyncListener[E]) Dropped() uint64 {
+	return al.dropped.Load()
+}
+
+// Panics returns the total number of panics recovered from the wrapped listener.
+func (al *AsyncListener[E]) Panics() uint64 {
+	return al.panics.Load()
+}
+
+// Shutdown stops accepting events and waits for all pending events to be
+// dispatched.  If the context is canceled first, this method returns the
+// context's error and the remaining events continue to drain in the background.
+// Any callers of OnEvent blocked by the Block policy return immediately, and
+// their events are dropped.
+//
+// Shutdown is idempotent and safe for concurrent use.
+func (al *AsyncListener[E]) Shutdown(ctx context.Context) error {
+	// release any blocked senders first, as they hold the read lock
+	al.closeOnce.Do(func() { close(al.closing) })
+
+	al.lock.Lock()
+	if !al.closed {
+		al.closed = true
+		close(al.queue)
+	}
+
+	al.lock.Unlock()
+
+	select {
+	case <-al.done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// gatedListener records events, optionally waiting on a gate before
+// recording each one.
+type gatedListener struct {
+	gate chan struct{}
+
+	lock   sync.Mutex
+	events []int
+}
+
+func (gl *gatedListener) OnEvent(e int) {
+	if gl.gate != nil {
+		<-gl.gate
+	}
+
+	gl.lock.Lock()
+	gl.events = append(gl.events, e)
+	gl.lock.Unlock()
+}
+
+func (gl *gatedListener) recorded() []int {
+	gl.lock.Lock()
+	defer gl.lock.Unlock()
+	return append([]int(nil), gl.events...)
+}
+
+type AsyncListenerTestSuite struct {
+	TestSuite
+}
+
+func (suite *AsyncListenerTestSuite) newAsyncListener(next Listener[int], opts ...AsyncListenerOption[int]) *AsyncListener[int] {
+	al, err := NewAsyncListener(next, opts...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(al)
+	return al
+}
+
+func (suite *AsyncListenerTestSuite) shutdown(al *AsyncListener[int]) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	suite.NoError(al.Shutdown(ctx))
+}
+
+// waitForPending blocks until the worker has dequeued an event and the
+// queue holds exactly n events.
+func (suite *AsyncListenerTestSuite) waitForPending(al *AsyncListener[int], n int) {
+	suite.Eventually(
+		func() bool { return al.Pending() == n },
+		time.Second,
+		time.Millisecond,
+	)
+}
+
+func (suite *AsyncListenerTestSuite) TestInvalidConfig() {
+	testCases := []struct {
+		name string
+		next Listener[int]
+		opts []AsyncListenerOption[int]
+	}{
+		{
+			name: "NilListener",
+		},
+		{
+			name: "QueueSize",
+			next: new(gatedListener),
+			opts: []AsyncListenerOption[int]{WithQueueSize[int](0)},
+		},
+		{
+			name: "Workers",
+			next: new(gatedListener),
+			opts: []AsyncListenerOption[int]{WithWorkers[int](0)},
+		},
+		{
+			name: "OverflowPolicy",
+			next: new(gatedListener),
+			opts: []AsyncListenerOption[int]{WithOverflowPolicy[int](OverflowPolicy(-1))},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			al, err := NewAsyncListener(testCase.next, testCase.opts...)
+			suite.Nil(al)
+			suite.ErrorIs(err, ErrInvalidAsyncConfig)
+		})
+	}
+}
+
+func (suite *AsyncListenerTestSuite) TestDispatch() {
+	var (
+		next = new(gatedListener)
+		al   = suite.newAsyncListener(next, WithWorkers[int](1))
+	)
+
+	for i := range 10 {
+		al.OnEvent(i)
+	}
+
+	suite.shutdown(al)
+	suite.Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, next.recorded())
+	suite.Zero(al.Dropped())
+	suite.Zero(al.Pending())
+}
+
+func (suite *AsyncListenerTestSuite) TestMultipleWorkers() {
+	var (
+		next = new(gatedListener)
+		al   = suite.newAsyncListener(next, WithWorkers[int](4))
+	)
+
+	for i := range 100 {
+		al.OnEvent(i)
+	}
+
+	suite.shutdown(al)
+	suite.ElementsMatch(
+		func() (v []int) {
+			for i := range 100 {
+				v = append(v, i)
+			}
+
+			return
+		}(),
+		next.recorded(),
+	)
+}
+
+func (suite *AsyncListenerTestSuite) TestDropNewest() {
+	var (
+		next = &gatedListener{gate: make(chan struct{})}
+		al   = suite.newAsyncListener(next, WithQueueSize[int](2))
+	)
+
+	al.OnEvent(0)
+	suite.waitForPending(al, 0) // the worker is now blocked on event 0
+
+	al.OnEvent(1)
+	al.OnEvent(2)
+	al.OnEvent(3)
+	suite.Equal(uint64(1), al.Dropped())
+
+	close(next.gate)
+	suite.shutdown(al)
+	suite.Equal([]int{0, 1, 2}, next.recorded())
+}
+
+func (suite *AsyncListenerTestSuite) TestDropOldest() {
+	var (
+		next = &gatedListener{gate: make(chan struct{})}
+		al   = suite.newAsyncListener(
+			next,
+			WithQueueSize[int](2),
+			WithOverflowPolicy[int](DropOldest),
+		)
+	)
+
+	al.OnEvent(0)
+	suite.waitForPending(al, 0)
+
+	al.OnEvent(1)
+	al.OnEvent(2)
+	al.OnEvent(3)
+	suite.Equal(uint64(1), al.Dropped())
+
+	close(next.gate)
+	suite.shutdown(al)
+	suite.Equal([]int{0, 2, 3}, next.recorded())
+}
+
+func (suite *AsyncListenerTestSuite) TestBlock() {
+	var (
+		next = &gatedListener{gate: make(chan struct{})}
+		al   = suite.newAsyncListener(
+			next,
+			WithQueueSize[int](1),
+			WithOverflowPolicy[int](Block),
+		)
+
+		sent = make(chan struct{})
+	)
+
+	al.OnEvent(0)
+	suite.waitForPending(al, 0)
+	al.OnEvent(1)
+
+	go func() {
+		defer close(sent)
+		al.OnEvent(2)
+	}()
+
+	select {
+	case <-sent:
+		suite.Fail("OnEvent should have blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(next.gate)
+	<-sent
+	suite.shutdown(al)
+	suite.Equal([]int{0, 1, 2}, next.recorded())
+	suite.Zero(al.Dropped())
+}
+
+func (suite *AsyncListenerTestSuite) TestBlockShutdown() {
+	var (
+		next = &gatedListener{gate: make(chan struct{})}
+		al   = suite.newAsyncListener(
+			next,
+			WithQueueSize[int](1),
+			WithOverflowPolicy[int](Block),
+		)
+
+		sent = make(chan struct{})
+	)
+
+	al.OnEvent(0)
+	suite.waitForPending(al, 0)
+	al.OnEvent(1)
+
+	go func() {
+		defer close(sent)
+		al.OnEvent(2)
+	}()
+
+	// give the goroutine a chance to block
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	suite.ErrorIs(al.Shutdown(ctx), context.DeadlineExceeded)
+	suite.Less(time.Since(start), time.Second)
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		suite.Fail("OnEvent should have been released by Shutdown")
+	}
+
+	// new events are dropped without blocking
+	al.OnEvent(3)
+	suite.Equal(uint64(2), al.Dropped())
+
+	close(next.gate)
+	suite.shutdown(al)
+	suite.Equal([]int{0, 1}, next.recorded())
+}
+
+func (suite *AsyncListenerTestSuite) TestPanic() {
+	var (
+		handled []any
+		al      = suite.newAsyncListener(
+			ListenerFunc[int](func(e int) {
+				if e%2 == 1 {
+					panic(e)
+				}
+			}),
+			WithPanicHandler(func(e int, r any) {
+				handled = append(handled, r)
+			}),
+		)
+	)
+
+	for i := range 4 {
+		al.OnEvent(i)
+	}
+
+	suite.shutdown(al)
+	suite.Equal(uint64(2), al.Panics())
+	suite.Equal([]any{1, 3}, handled)
+}
+
+func (suite *AsyncListenerTestSuite) TestPanicHandlerPanics() {
+	var (
+		delivered []int
+		al        = suite.newAsyncListener(
+			ListenerFunc[int](func(e int) {
+				if e == 1 {
+					panic(e)
+				}
+
+				delivered = append(delivered, e)
+			}),
+			WithPanicHandler(func(int, any) {
+				panic("handler")
+			}),
+		)
+	)
+
+	for i := range 3 {
+		al.OnEvent(i)
+	}
+
+	suite.shutdown(al)
+	suite.Equal(uint64(2), al.Panics())
+	suite.Equal([]int{0, 2}, delivered)
+}
+
+func (suite *AsyncListenerTestSuite) TestShutdown() {
+	suite.Run("AfterShutdown", func() {
+		next := new(gatedListener)
+		al := suite.newAsyncListener(next)
+		suite.shutdown(al)
+		suite.shutdown(al) // idempotent
+
+		al.OnEvent(1)
+		suite.Equal(uint64(1), al.Dropped())
+		suite.Empty(next.recorded())
+	})
+
+	suite.Run("ContextCanceled", func() {
+		next := &gatedListener{gate: make(chan struct{})}
+		al := suite.newAsyncListener(next)
+		al.OnEvent(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		suite.ErrorIs(al.Shutdown(ctx), context.Canceled)
+
+		close(next.gate)
+		suite.shutdown(al)
+		suite.Equal([]int{1}, next.recorded())
+	})
+}
+
+func (suite *AsyncListenerTestSuite) TestAuthenticateEvents() {
+	var (
+		events = make(chan AuthenticateEvent[string], 1)
+		al, _  = NewAsyncListener[AuthenticateEvent[string]](
+			ListenerFunc[AuthenticateEvent[string]](func(e AuthenticateEvent[string]) {
+				events <- e
+			}),
+		)
+
+		parser = new(mockTokenParser[string])
+	)
+
+	suite.Require().NotNil(al)
+	a, err := NewAuthenticator(
+		WithTokenParsers[string](parser),
+		WithAuthenticateListeners[string](al),
+	)
+
+	suite.Require().NoError(err)
+	parser.ExpectParse(suite.testContext(), "source").
+		Return(suite.testToken(), error(nil)).Once()
+
+	_, err = a.Authenticate(suite.testContext(), "source")
+	suite.NoError(err)
+	suite.NoError(al.Shutdown(context.Background()))
+
+	e := <-events
+	suite.Equal("source", e.Source)
+	suite.Equal(suite.testToken(), e.Token)
+	parser.AssertExpectations(suite.T())
+}
+
+func TestAsyncListener(t *testing.T) {
+	suite.Run(t, new(AsyncListenerTestSuite))
+}
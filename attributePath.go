@@ -0,0 +1,609 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AttributePathError indicates that an attribute path expression could not be compiled.
+type AttributePathError struct {
+	// Expr is the expression that failed to compile.
+	Expr string
+
+	// Offset is the byte offset within Expr where the problem was detected.
+	Offset int
+
+	// Msg describes the problem.
+	Msg string
+}
+
+func (ape *AttributePathError) Error() string {
+	var o strings.Builder
+	o.WriteString("invalid attribute path ")
+	o.WriteString(strconv.Quote(ape.Expr))
+	o.WriteString(" at offset ")
+	o.WriteString(strconv.Itoa(ape.Offset))
+	o.WriteString(": ")
+	o.WriteString(ape.Msg)
+	return o.String()
+}
+
+// pathStep transforms the current set of values into the next set.
+type pathStep func(values []any) []any
+
+// AttributePath is a compiled expression that selects values from a set of attributes.
+// An AttributePath is immutable and safe for concurrent use, so it may be compiled once
+// and reused by any number of approvers or validators.
+//
+// The syntax of a path is a sequence of steps, applied from left to right:
+//
+//	name          the value of a key, e.g. realm_access
+//	.name         the value of a key within the current value(s)
+//	["key"]       the value of a key that isn't a simple name, e.g. ["https://example.com/roles"]
+//	[n]           the nth element of an array.  Negative indices count from the end.
+//	[*]           every element of an array
+//	.*            every value of a map, in key order
+//	[?filter]     every element of an array that matches the filter
+//
+// A simple name consists of letters, digits, underscores, hyphens, and dollar signs.
+//
+// A filter is either a relative key, e.g. [?active], which matches elements where that
+// key exists and is neither false nor nil, or a comparison between a relative key and a
+// literal, e.g. [?id=="x"] or [?level>=3].  Relative keys may be dotted, e.g. [?owner.id=="x"].
+// The supported operators are ==, !=, <, <=, >, and >=.  Literals are strings in single or
+// double quotes, numbers, true, false, or null.
+//
+// Each step is applied to every value produced by the previous step, so that after a
+// wildcard or a filter, subsequent steps apply to each selected element.  For example,
+// tenants[?id=="x"].scopes[*] selects every scope of every tenant whose id is "x".
+type AttributePath struct {
+	expr  string
+	steps []pathStep
+}
+
+// CompileAttributePath compiles an attribute path expression.  Any syntax error is
+// returned as an *AttributePathError.
+func CompileAttributePath(expr string) (*AttributePath, error) {
+	pc := pathCompiler{
+		expr: expr,
+	}
+
+	steps, err := pc.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttributePath{
+		expr:  expr,
+		steps: steps,
+	}, nil
+}
+
+// MustCompileAttributePath is like CompileAttributePath, but panics on any error.
+func MustCompileAttributePath(expr string) *AttributePath {
+	ap, err := CompileAttributePath(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return ap
+}
+
+// String returns the source expression for this path.
+func (ap *AttributePath) String() string {
+	return ap.expr
+}
+
+// Select returns all values from the given attributes that match this path.  If no
+// values match, the returned slice is empty.
+func (ap *AttributePath) Select(a AttributesAccessor) []any {
+	if a == nil {
+		return nil
+	}
+
+	values := []any{a}
+	for i := 0; len(values) > 0 && i < len(ap.steps); i++ {
+		values = ap.steps[i](values)
+	}
+
+	return values
+}
+
+// QueryAttribute selects values from the attributes using a compiled path, converting
+// each value to T using ConvertAttribute.  If any selected value cannot be converted,
+// the first such error is returned.
+func QueryAttribute[T any](a AttributesAccessor, ap *AttributePath) ([]T, error) {
+	values := ap.Select(a)
+	results := make([]T, 0, len(values))
+	for _, v := range values {
+		r, err := ConvertAttribute[T](v)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// QueryFirstAttribute returns the first value selected by the path that converts to T.
+// If no such value exists, this function returns false.
+func QueryFirstAttribute[T any](a AttributesAccessor, ap *AttributePath) (v T, ok bool) {
+	for _, raw := range ap.Select(a) {
+		var err error
+		if v, err = ConvertAttribute[T](raw); err == nil {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
+
+// lookupKey returns the value of a key from a map with string keys or an AttributesAccessor.
+func lookupKey(v any, key string) (any, bool) {
+	switch vt := v.(type) {
+	case map[string]any:
+		result, ok := vt[key]
+		return result, ok
+
+	case AttributesAccessor:
+		return vt.Get(key)
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		if result := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())); result.IsValid() {
+			return result.Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// elements returns the elements of an array or slice, or false if v is neither.
+func elements(v any) ([]any, bool) {
+	if s, ok := v.([]any); ok {
+		return s, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	s := make([]any, rv.Len())
+	for i := range s {
+		s[i] = rv.Index(i).Interface()
+	}
+
+	return s, true
+}
+
+// mapValues returns the values of a map with string keys, ordered by key.
+func mapValues(v any) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	keys := rv.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		values[i] = rv.MapIndex(k).Interface()
+	}
+
+	return values
+}
+
+func keyStep(key string) pathStep {
+	return func(values []any) (next []any) {
+		for _, v := range values {
+			if result, ok := lookupKey(v, key); ok {
+				next = append(next, result)
+			}
+		}
+
+		return
+	}
+}
+
+func indexStep(index int) pathStep {
+	return func(values []any) (next []any) {
+		for _, v := range values {
+			if s, ok := elements(v); ok {
+				i := index
+				if i < 0 {
+					i += len(s)
+				}
+
+				if i >= 0 && i < len(s) {
+					next = append(next, s[i])
+				}
+			}
+		}
+
+		return
+	}
+}
+
+func wildcardIndexStep(values []any) (next []any) {
+	for _, v := range values {
+		if s, ok := elements(v); ok {
+			next = append(next, s...)
+		}
+	}
+
+	return
+}
+
+func wildcardKeyStep(values []any) (next []any) {
+	for _, v := range values {
+		next = append(next, mapValues(v)...)
+	}
+
+	return
+}
+
+func filterStep(predicate func(any) bool) pathStep {
+	return func(values []any) (next []any) {
+		for _, v := range values {
+			if s, ok := elements(v); ok {
+				for _, e := range s {
+					if predicate(e) {
+						next = append(next, e)
+					}
+				}
+			}
+		}
+
+		return
+	}
+}
+
+// pathCompiler is a simple scanner that turns an expression into steps.
+type pathCompiler struct {
+	expr string
+	pos  int
+}
+
+func (pc *pathCompiler) errorf(offset int, msg string) error {
+	return &AttributePathError{
+		Expr:   pc.expr,
+		Offset: offset,
+		Msg:    msg,
+	}
+}
+
+func (pc *pathCompiler) eof() bool {
+	return pc.pos >= len(pc.expr)
+}
+
+func (pc *pathCompiler) peek() byte {
+	if pc.eof() {
+		return 0
+	}
+
+	return pc.expr[pc.pos]
+}
+
+func (pc *pathCompiler) skipSpace() {
+	for !pc.eof() && pc.expr[pc.pos] == ' ' {
+		pc.pos++
+	}
+}
+
+func isNameByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') ||
+		b == '_' || b == '-' || b == '$'
+}
+
+func (pc *pathCompiler) name() (string, error) {
+	start := pc.pos
+	for !pc.eof() && isNameByte(pc.expr[pc.pos]) {
+		pc.pos++
+	}
+
+	if start == pc.pos {
+		return "", pc.errorf(start, "expected a name")
+	}
+
+	return pc.expr[start:pc.pos], nil
+}
+
+func (pc *pathCompiler) quoted() (string, error) {
+	start := pc.pos
+	quote := pc.peek()
+	if quote != '"' && quote != '\'' {
+		return "", pc.errorf(start, "expected a quoted string")
+	}
+
+	pc.pos++
+	var o strings.Builder
+	for !pc.eof() {
+		c := pc.expr[pc.pos]
+		pc.pos++
+		switch {
+		case c == quote:
+			return o.String(), nil
+
+		case c == '\\':
+			if pc.eof() {
+				return "", pc.errorf(pc.pos, "unterminated escape")
+			}
+
+			o.WriteByte(pc.expr[pc.pos])
+			pc.pos++
+
+		default:
+			o.WriteByte(c)
+		}
+	}
+
+	return "", pc.errorf(start, "unterminated string")
+}
+
+func (pc *pathCompiler) expect(c byte) error {
+	if pc.peek() != c {
+		return pc.errorf(pc.pos, "expected '"+string(c)+"'")
+	}
+
+	pc.pos++
+	return nil
+}
+
+func (pc *pathCompiler) compile() (steps []pathStep, err error) {
+	if len(pc.expr) == 0 {
+		return nil, pc.errorf(0, "empty path")
+	}
+
+	// the first step may omit the leading dot
+	if pc.peek() != '[' {
+		var n string
+		if n, err = pc.name(); err != nil {
+			return
+		}
+
+		steps = append(steps, keyStep(n))
+	}
+
+	for err == nil && !pc.eof() {
+		var step pathStep
+		switch pc.peek() {
+		case '.':
+			pc.pos++
+			if pc.peek() == '*' {
+				pc.pos++
+				step = wildcardKeyStep
+			} else {
+				var n string
+				if n, err = pc.name(); err == nil {
+					step = keyStep(n)
+				}
+			}
+
+		case '[':
+			pc.pos++
+			step, err = pc.bracket()
+
+		default:
+			err = pc.errorf(pc.pos, "unexpected '"+string(pc.peek())+"'")
+		}
+
+		if err == nil {
+			steps = append(steps, step)
+		}
+	}
+
+	return
+}
+
+// bracket compiles the contents of a [...] step.  The opening bracket has been consumed.
+func (pc *pathCompiler) bracket() (step pathStep, err error) {
+	pc.skipSpace()
+	switch c := pc.peek(); {
+	case c == '*':
+		pc.pos++
+		step = wildcardIndexStep
+
+	case c == '"' || c == '\'':
+		var key string
+		if key, err = pc.quoted(); err == nil {
+			step = keyStep(key)
+		}
+
+	case c == '?':
+		pc.pos++
+		step, err = pc.filter()
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := pc.pos
+		pc.pos++
+		for !pc.eof() && pc.peek() >= '0' && pc.peek() <= '9' {
+			pc.pos++
+		}
+
+		var index int
+		if index, err = strconv.Atoi(pc.expr[start:pc.pos]); err != nil {
+			err = pc.errorf(start, "invalid index")
+		} else {
+			step = indexStep(index)
+		}
+
+	default:
+		err = pc.errorf(pc.pos, "expected an index, a quoted key, '*', or a filter")
+	}
+
+	if err == nil {
+		pc.skipSpace()
+		err = pc.expect(']')
+	}
+
+	return
+}
+
+// filter compiles a filter expression.  The leading '?' has been consumed.
+func (pc *pathCompiler) filter() (pathStep, error) {
+	pc.skipSpace()
+	var keys []string
+	for {
+		key, err := pc.name()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+		if pc.peek() != '.' {
+			break
+		}
+
+		pc.pos++
+	}
+
+	resolve := func(e any) (v any, ok bool) {
+		v = e
+		for i := 0; i < len(keys); i++ {
+			if v, ok = lookupKey(v, keys[i]); !ok {
+				break
+			}
+		}
+
+		return
+	}
+
+	pc.skipSpace()
+	opStart := pc.pos
+	for !pc.eof() && strings.IndexByte("=!<>", pc.peek()) >= 0 {
+		pc.pos++
+	}
+
+	op := pc.expr[opStart:pc.pos]
+	if len(op) == 0 {
+		return filterStep(func(e any) bool {
+			v, ok := resolve(e)
+			b, isBool := v.(bool)
+			return ok && v != nil && (!isBool || b)
+		}), nil
+	}
+
+	compare, ok := filterComparisons[op]
+	if !ok {
+		return nil, pc.errorf(opStart, "unknown operator '"+op+"'")
+	}
+
+	pc.skipSpace()
+	literal, err := pc.literal()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterStep(func(e any) bool {
+		v, ok := resolve(e)
+		return ok && compare(v, literal)
+	}), nil
+}
+
+// literal compiles a filter literal.
+func (pc *pathCompiler) literal() (any, error) {
+	c := pc.peek()
+	if c == '"' || c == '\'' {
+		return pc.quoted()
+	}
+
+	start := pc.pos
+	for !pc.eof() && (isNameByte(pc.peek()) || pc.peek() == '.' || pc.peek() == '+') {
+		pc.pos++
+	}
+
+	text := pc.expr[start:pc.pos]
+	switch text {
+	case "true":
+		return true, nil
+
+	case "false":
+		return false, nil
+
+	case "null":
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, pc.errorf(start, "expected a literal")
+	}
+
+	return f, nil
+}
+
+// compareFilterValues compares an attribute value against a literal.  The returned int
+// has the same meaning as for strings.Compare.  If the values are not comparable, this
+// function returns false.
+func compareFilterValues(v, literal any) (int, bool) {
+	switch lt := literal.(type) {
+	case nil:
+		if v == nil {
+			return 0, true
+		}
+
+	case string:
+		if s, err := ConvertAttribute[string](v); err == nil {
+			return strings.Compare(s, lt), true
+		}
+
+	case bool:
+		// booleans are only ever equal or unequal
+		if b, err := ConvertAttribute[bool](v); err == nil && b == lt {
+			return 0, true
+		}
+
+	case float64:
+		if f, err := ConvertAttribute[float64](v); err == nil {
+			switch {
+			case f < lt:
+				return -1, true
+			case f > lt:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+var filterComparisons = map[string]func(any, any) bool{
+	"==": func(v, literal any) bool {
+		c, ok := compareFilterValues(v, literal)
+		return ok && c == 0
+	},
+	"!=": func(v, literal any) bool {
+		c, ok := compareFilterValues(v, literal)
+		return !ok || c != 0
+	},
+	"<": func(v, literal any) bool {
+		c, ok := compareFilterValues(v, literal)
+		return ok && c < 0
+	},
+	"<=": func(v, literal any) bool {
+		c, ok := compareFilterValues(v, literal)
+		return ok && c <= 0
+	},
+	">": func(v, literal any) bool {
+		c, ok := compareFilterValues(v, literal)
+		return ok && c > 0
+	},
+	">=": func(v, literal any) bool {
+		c, ok := compareFilterValues(v, literal)
+		return ok && c >= 0
+	},
+}
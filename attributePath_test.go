@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AttributePathTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AttributePathTestSuite) testAttributes() AttributesAccessor {
+	var claims map[string]any
+	suite.Require().NoError(json.Unmarshal(
+		[]byte(`{
+			"sub": "joe",
+			"realm_access": {"roles": ["admin", "user"]},
+			"tenants": [
+				{"id": "x", "level": 3, "active": true, "scopes": ["read", "write"], "owner": {"id": "joe"}},
+				{"id": "y", "level": 1, "active": false, "scopes": ["read"]},
+				{"id": "z", "level": 5, "scopes": ["admin"], "owner": null}
+			],
+			"resource_access": {
+				"b-client": {"roles": ["b1"]},
+				"a-client": {"roles": ["a1", "a2"]}
+			},
+			"https://example.com/groups": ["g1"],
+			"matrix": [[1, 2], [3, 4]]
+		}`),
+		&claims,
+	))
+
+	return testAttributes(claims)
+}
+
+func (suite *AttributePathTestSuite) TestSelect() {
+	testCases := []struct {
+		expr     string
+		expected []any
+	}{
+		{expr: `sub`, expected: []any{"joe"}},
+		{expr: `missing`},
+		{expr: `sub.missing`},
+		{expr: `realm_access.roles`, expected: []any{[]any{"admin", "user"}}},
+		{expr: `realm_access.roles[*]`, expected: []any{"admin", "user"}},
+		{expr: `realm_access.roles[0]`, expected: []any{"admin"}},
+		{expr: `realm_access.roles[-1]`, expected: []any{"user"}},
+		{expr: `realm_access.roles[2]`},
+		{expr: `realm_access.roles[-3]`},
+		{expr: `sub[0]`},
+		{expr: `sub[*]`},
+		{expr: `["realm_access"]["roles"][ 1 ]`, expected: []any{"user"}},
+		{expr: `['https://example.com/groups'][*]`, expected: []any{"g1"}},
+		{expr: `tenants[*].id`, expected: []any{"x", "y", "z"}},
+		{expr: `tenants[?id=="x"].scopes`, expected: []any{[]any{"read", "write"}}},
+		{expr: `tenants[?id=='x'].scopes[*]`, expected: []any{"read", "write"}},
+		{expr: `tenants[?id!="x"].id`, expected: []any{"y", "z"}},
+		{expr: `tenants[?level>=3].id`, expected: []any{"x", "z"}},
+		{expr: `tenants[?level > 3].id`, expected: []any{"z"}},
+		{expr: `tenants[?level<3].id`, expected: []any{"y"}},
+		{expr: `tenants[?level<=1].id`, expected: []any{"y"}},
+		{expr: `tenants[?level=="3"].id`},
+		{expr: `tenants[?active].id`, expected: []any{"x"}},
+		{expr: `tenants[?active==false].id`, expected: []any{"y"}},
+		{expr: `tenants[?owner].id`, expected: []any{"x"}},
+		{expr: `tenants[?owner==null].id`, expected: []any{"z"}},
+		{expr: `tenants[?owner.id=="joe"].id`, expected: []any{"x"}},
+		{expr: `tenants[?missing].id`},
+		{expr: `sub[?id=="x"]`},
+		{expr: `resource_access.*.roles[*]`, expected: []any{"a1", "a2", "b1"}},
+		{expr: `resource_access.*.roles[0]`, expected: []any{"a1", "b1"}},
+		{expr: `sub.*`},
+		{expr: `matrix[*][1]`, expected: []any{2.0, 4.0}},
+		{expr: `matrix[1][*]`, expected: []any{3.0, 4.0}},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.expr, func() {
+			ap, err := CompileAttributePath(testCase.expr)
+			suite.Require().NoError(err)
+			suite.Equal(testCase.expr, ap.String())
+			suite.Equal(testCase.expected, ap.Select(suite.testAttributes()))
+		})
+	}
+}
+
+func (suite *AttributePathTestSuite) TestSelectNil() {
+	suite.Empty(MustCompileAttributePath("sub").Select(nil))
+}
+
+func (suite *AttributePathTestSuite) TestSelectNestedAccessor() {
+	a := testAttributes{
+		"nested": AttributesAccessor(testAttributes{
+			"roles": []string{"x", "y"},
+		}),
+		"typed": map[string][]string{
+			"roles": {"z"},
+		},
+	}
+
+	suite.Equal([]any{"y"}, MustCompileAttributePath("nested.roles[1]").Select(a))
+	suite.Equal([]any{"z"}, MustCompileAttributePath("typed.roles[*]").Select(a))
+	suite.Equal([]any{[]string{"z"}}, MustCompileAttributePath("typed.*").Select(a))
+}
+
+func (suite *AttributePathTestSuite) TestInvalid() {
+	testCases := []struct {
+		expr           string
+		expectedOffset int
+	}{
+		{expr: ``, expectedOffset: 0},
+		{expr: `.sub`, expectedOffset: 0},
+		{expr: `sub.`, expectedOffset: 4},
+		{expr: `sub..x`, expectedOffset: 4},
+		{expr: `sub x`, expectedOffset: 3},
+		{expr: `sub[`, expectedOffset: 4},
+		{expr: `sub[0`, expectedOffset: 5},
+		{expr: `sub[-]`, expectedOffset: 4},
+		{expr: `sub[x]`, expectedOffset: 4},
+		{expr: `sub["x`, expectedOffset: 4},
+		{expr: `sub["x\`, expectedOffset: 7},
+		{expr: `sub[?]`, expectedOffset: 5},
+		{expr: `sub[?id=]`, expectedOffset: 7},
+		{expr: `sub[?id==]`, expectedOffset: 9},
+		{expr: `sub[?id=="x"`, expectedOffset: 12},
+		{expr: `sub[?id=<"x"]`, expectedOffset: 7},
+		{expr: `sub[?id==x]`, expectedOffset: 9},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.expr, func() {
+			ap, err := CompileAttributePath(testCase.expr)
+			suite.Nil(ap)
+
+			var ape *AttributePathError
+			suite.Require().ErrorAs(err, &ape)
+			suite.Equal(testCase.expr, ape.Expr)
+			suite.Equal(testCase.expectedOffset, ape.Offset, ape.Error())
+			suite.NotEmpty(ape.Msg)
+		})
+	}
+
+	suite.Panics(func() {
+		MustCompileAttributePath("sub[")
+	})
+}
+
+func (suite *AttributePathTestSuite) TestQueryAttribute() {
+	a := suite.testAttributes()
+
+	levels, err := QueryAttribute[int](a, MustCompileAttributePath("tenants[*].level"))
+	suite.NoError(err)
+	suite.Equal([]int{3, 1, 5}, levels)
+
+	scopes, err := QueryAttribute[[]string](a, MustCompileAttributePath(`tenants[?id=="x"].scopes`))
+	suite.NoError(err)
+	suite.Equal([][]string{{"read", "write"}}, scopes)
+
+	none, err := QueryAttribute[string](a, MustCompileAttributePath("missing"))
+	suite.NoError(err)
+	suite.Empty(none)
+
+	_, err = QueryAttribute[int](a, MustCompileAttributePath("tenants[*].id"))
+	suite.ErrorIs(err, ErrAttributeConversion)
+}
+
+func (suite *AttributePathTestSuite) TestQueryFirstAttribute() {
+	a := testAttributes{
+		"values": []any{"x", 2.0, 3.0},
+	}
+
+	v, ok := QueryFirstAttribute[int](a, MustCompileAttributePath("values[*]"))
+	suite.True(ok)
+	suite.Equal(2, v)
+
+	_, ok = QueryFirstAttribute[bool](a, MustCompileAttributePath("values[*]"))
+	suite.False(ok)
+}
+
+func (suite *AttributePathTestSuite) TestApprover() {
+	// compile once, reuse for every request
+	scopes := MustCompileAttributePath(`tenants[?id=="x"].scopes[*]`)
+	approver := ApproverFunc[string](func(_ context.Context, scope string, t Token) error {
+		var a AttributesAccessor
+		if TokenAs(t, &a) {
+			granted, _ := QueryAttribute[string](a, scopes)
+			for _, g := range granted {
+				if g == scope {
+					return nil
+				}
+			}
+		}
+
+		return ErrUnauthorized
+	})
+
+	token := struct {
+		Token
+		AttributesAccessor
+	}{
+		Token:              StubToken("joe"),
+		AttributesAccessor: suite.testAttributes(),
+	}
+
+	suite.NoError(approver.Approve(context.Background(), "write", token))
+	suite.ErrorIs(approver.Approve(context.Background(), "admin", token), ErrUnauthorized)
+}
+
+func TestAttributePath(t *testing.T) {
+	suite.Run(t, new(AttributePathTestSuite))
+}
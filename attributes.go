@@ -12,7 +12,8 @@ type AttributesAccessor interface {
 
 // GetAttribute provides a typesafe way of obtaining attribute values.
 // This function will return false if either the attribute doesn't exist
-// or if the attribute's value of not of type T.
+// or if the attribute's value is not of type T and cannot be converted to T
+// by ConvertAttribute.
 //
 // Multiple keys may be passed to this function, in which case the keys will
 // be traversed to find the nested key.  If any intervening keys are not of
@@ -40,7 +41,11 @@ func GetAttribute[T any](a AttributesAccessor, keys ...string) (v T, ok bool) {
 	}
 
 	if ok {
-		v, ok = raw.(T)
+		if v, ok = raw.(T); !ok {
+			var err error
+			v, err = ConvertAttribute[T](raw)
+			ok = err == nil
+		}
 	}
 
 	return
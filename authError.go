@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"errors"
+	"strings"
+)
+
+// Stage identifies the step of a bascule workflow in which an error occurred.
+type Stage string
+
+const (
+	// StageNone is the zero value, used when the stage is not known.
+	StageNone Stage = ""
+
+	// StageParse indicates that token parsing failed.
+	StageParse Stage = "parse"
+
+	// StageValidate indicates that token validation failed.
+	StageValidate Stage = "validate"
+
+	// StageAuthorize indicates that authorization failed.
+	StageAuthorize Stage = "authorize"
+)
+
+// String returns the string form of this stage.
+func (s Stage) String() string {
+	return string(s)
+}
+
+// AuthError is the error returned by the Authenticator and Authorizer workflows.
+// It describes where a workflow failed, separating a message that is safe to
+// return to clients from the internal error.
+//
+// An AuthError always unwraps to the underlying error, so errors.Is and errors.As
+// continue to work for the existing sentinels such as ErrBadCredentials.
+//
+// Workflow components may return an AuthError themselves, e.g. to supply the scheme
+// or a client-facing message.  The workflow fills in any fields left unset rather
+// than wrapping the component's AuthError in another one.
+type AuthError struct {
+	// Stage is the workflow step that failed.
+	Stage Stage
+
+	// Principal is the principal of the token involved, if known.  This field
+	// is empty when parsing failed.
+	Principal string
+
+	// Scheme is the credential scheme that was presented, e.g. "Basic", if known.
+	Scheme string
+
+	// Component is the name of the parser, validator, or approver that failed,
+	// as reported by ComponentName.
+	Component string
+
+	// Message is an optional client-facing description of the failure.  This message
+	// must not contain any internal detail.  If unset, SafeMessage supplies a
+	// generic message based on the error's category.
+	Message string
+
+	// Err is the underlying error, which may contain internal detail.  This field
+	// is required.
+	Err error
+}
+
+// Unwrap returns the underlying error.
+func (ae *AuthError) Unwrap() error {
+	return ae.Err
+}
+
+// Error returns the underlying error's text, so that an AuthError does not change
+// how an error is rendered.  Use Detail for a description that includes context.
+func (ae *AuthError) Error() string {
+	if ae.Err == nil {
+		return "authentication error"
+	}
+
+	return ae.Err.Error()
+}
+
+// Detail returns an internal description of this error that includes the stage,
+// component, principal, and scheme.  This text is intended for logs, and should
+// not be returned to clients.
+func (ae *AuthError) Detail() string {
+	var o strings.Builder
+	if ae.Stage != StageNone {
+		o.WriteString(string(ae.Stage))
+	} else {
+		o.WriteString("workflow")
+	}
+
+	o.WriteString(" failed")
+	appendDetail(&o, "component", ae.Component)
+	appendDetail(&o, "principal", ae.Principal)
+	appendDetail(&o, "scheme", ae.Scheme)
+	o.WriteString(": ")
+	o.WriteString(ae.Error())
+	return o.String()
+}
+
+func appendDetail(o *strings.Builder, name, value string) {
+	if len(value) > 0 {
+		o.WriteString(" ")
+		o.WriteString(name)
+		o.WriteString("=")
+		o.WriteString(value)
+	}
+}
+
+// SafeMessage returns a client-facing description of this error.  If Message is
+// set, it is returned.  Otherwise, a generic message is chosen based on the
+// error's category.
+func (ae *AuthError) SafeMessage() string {
+	if len(ae.Message) > 0 {
+		return ae.Message
+	}
+
+	category := CategorizeError(ae.Err)
+	if category == CategoryOther && ae.Stage == StageAuthorize {
+		return "authorization failed"
+	}
+
+	return safeMessages[category]
+}
+
+// safeMessages are the generic client-facing messages for each error category.
+var safeMessages = map[ErrorCategory]string{
+	CategoryNone:               "",
+	CategoryMissingCredentials: "missing credentials",
+	CategoryInvalidCredentials: "invalid credentials",
+	CategoryBadCredentials:     "bad credentials",
+	CategoryTokenExpired:       "token expired",
+	CategoryTokenNotYetValid:   "token not yet valid",
+	CategoryRevokedCredentials: "revoked credentials",
+	CategoryPanic:              "internal error",
+	CategoryLockedOut:          "too many failed attempts",
+	CategoryUnauthorized:       "access denied",
+	CategoryNoTokenParsers:     "internal error",
+	CategoryOther:              "authentication failed",
+}
+
+// SafeMessage returns a client-facing description of any error.  If err has an
+// *AuthError in its chain, that error's SafeMessage is returned.  Otherwise,
+// a generic message is chosen based on the error's category.  This function
+// returns the empty string for a nil error.
+func SafeMessage(err error) string {
+	var ae *AuthError
+	if errors.As(err, &ae) {
+		return ae.SafeMessage()
+	}
+
+	return safeMessages[CategorizeError(err)]
+}
+
+// newAuthError creates the AuthError for a failed workflow step.  If err is
+// itself an *AuthError, a copy is returned with any unset fields taken from
+// defaults.  If an *AuthError is further down the chain, its fields are used as
+// further defaults, but err is wrapped as is.
+func newAuthError(err error, defaults AuthError) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		merged AuthError
+		inner  *AuthError
+	)
+
+	if errors.As(err, &inner) {
+		merged = *inner
+		if inner != err {
+			merged.Err = err
+		}
+	} else {
+		merged.Err = err
+	}
+
+	if merged.Stage == StageNone {
+		merged.Stage = defaults.Stage
+	}
+
+	if len(merged.Principal) == 0 {
+		merged.Principal = defaults.Principal
+	}
+
+	if len(merged.Scheme) == 0 {
+		merged.Scheme = defaults.Scheme
+	}
+
+	if len(merged.Component) == 0 {
+		merged.Component = defaults.Component
+	}
+
+	if len(merged.Message) == 0 {
+		merged.Message = defaults.Message
+	}
+
+	return &merged
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthErrorTestSuite struct {
+	TestSuite
+}
+
+func (suite *AuthErrorTestSuite) TestStage() {
+	suite.Equal("parse", StageParse.String())
+	suite.Equal("validate", StageValidate.String())
+	suite.Equal("authorize", StageAuthorize.String())
+	suite.Empty(StageNone.String())
+}
+
+func (suite *AuthErrorTestSuite) TestError() {
+	suite.Run("Full", func() {
+		ae := &AuthError{
+			Stage:     StageValidate,
+			Principal: "joe",
+			Scheme:    "Basic",
+			Component: "test validator",
+			Err:       ErrBadCredentials,
+		}
+
+		suite.ErrorIs(ae, ErrBadCredentials)
+		suite.Equal(ErrBadCredentials.Error(), ae.Error())
+		suite.Equal(
+			"validate failed component=test validator principal=joe scheme=Basic: bad credentials",
+			ae.Detail(),
+		)
+	})
+
+	suite.Run("Minimal", func() {
+		ae := &AuthError{}
+		suite.Equal("authentication error", ae.Error())
+		suite.Equal("workflow failed: authentication error", ae.Detail())
+	})
+}
+
+func (suite *AuthErrorTestSuite) TestSafeMessage() {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name: "Nil",
+		},
+		{
+			name:     "Sentinel",
+			err:      ErrMissingCredentials,
+			expected: "missing credentials",
+		},
+		{
+			name:     "Internal",
+			err:      errors.New("connection refused to 10.0.0.1"),
+			expected: "authentication failed",
+		},
+		{
+			name:     "Panic",
+			err:      &PanicError{Component: "test", Value: "expected"},
+			expected: "internal error",
+		},
+		{
+			name:     "AuthErrorDefault",
+			err:      &AuthError{Stage: StageParse, Err: fmt.Errorf("detail: %w", ErrInvalidCredentials)},
+			expected: "invalid credentials",
+		},
+		{
+			name:     "AuthErrorMessage",
+			err:      fmt.Errorf("wrapped: %w", &AuthError{Message: "try again", Err: ErrBadCredentials}),
+			expected: "try again",
+		},
+		{
+			name:     "AuthorizeOther",
+			err:      &AuthError{Stage: StageAuthorize, Err: errors.New("policy engine unavailable")},
+			expected: "authorization failed",
+		},
+		{
+			name:     "AuthorizeUnauthorized",
+			err:      &AuthError{Stage: StageAuthorize, Err: ErrUnauthorized},
+			expected: "access denied",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.Equal(testCase.expected, SafeMessage(testCase.err))
+		})
+	}
+}
+
+func (suite *AuthErrorTestSuite) TestNewAuthError() {
+	defaults := AuthError{
+		Stage:     StageParse,
+		Principal: "joe",
+		Scheme:    "Basic",
+		Component: "test parser",
+		Message:   "default message",
+	}
+
+	suite.Run("Nil", func() {
+		suite.NoError(newAuthError(nil, defaults))
+	})
+
+	suite.Run("Plain", func() {
+		err := newAuthError(ErrBadCredentials, defaults)
+		expected := defaults
+		expected.Err = ErrBadCredentials
+		suite.Equal(&expected, err)
+	})
+
+	suite.Run("Merge", func() {
+		original := &AuthError{Scheme: "Bearer", Err: ErrBadCredentials}
+		err := newAuthError(original, defaults)
+
+		suite.Equal(
+			&AuthError{
+				Stage:     StageParse,
+				Principal: "joe",
+				Scheme:    "Bearer",
+				Component: "test parser",
+				Message:   "default message",
+				Err:       ErrBadCredentials,
+			},
+			err,
+		)
+
+		// the original error must not be modified
+		suite.Equal(&AuthError{Scheme: "Bearer", Err: ErrBadCredentials}, original)
+	})
+
+	suite.Run("Nested", func() {
+		inner := fmt.Errorf("wrapped: %w", &AuthError{Scheme: "Bearer", Err: ErrBadCredentials})
+		err := newAuthError(inner, defaults)
+
+		var ae *AuthError
+		suite.Require().ErrorAs(err, &ae)
+		suite.Equal("Bearer", ae.Scheme)
+		suite.Equal(StageParse, ae.Stage)
+		suite.Same(inner, ae.Err)
+		suite.ErrorIs(err, ErrBadCredentials)
+	})
+}
+
+func (suite *AuthErrorTestSuite) TestAuthenticatorMerge() {
+	a, err := NewAuthenticator(
+		WithTokenParsers[string](
+			AsTokenParser[string](func(context.Context, string) (Token, error) {
+				return nil, &AuthError{Scheme: "Basic", Message: "bad password", Err: ErrBadCredentials}
+			}),
+		),
+	)
+
+	suite.Require().NoError(err)
+
+	_, err = a.Authenticate(suite.testContext(), "source")
+	suite.ErrorIs(err, ErrBadCredentials)
+
+	var ae *AuthError
+	suite.Require().ErrorAs(err, &ae)
+	suite.Equal(StageParse, ae.Stage)
+	suite.Equal("Basic", ae.Scheme)
+	suite.Equal("bascule.tokenParserFunc[string]", ae.Component)
+	suite.Equal("bad password", ae.SafeMessage())
+	suite.Empty(ae.Principal)
+
+	var pe *ParseError
+	suite.ErrorAs(err, &pe)
+}
+
+func TestAuthError(t *testing.T) {
+	suite.Run(t, new(AuthErrorTestSuite))
+}
@@ -3,7 +3,11 @@
 
 package bascule
 
-import "context"
+import (
+	"context"
+	"slices"
+	"time"
+)
 
 // AuthenticateEvent represents the result of bascule's authenticate workflow.
 type AuthenticateEvent[S any] struct {
@@ -16,8 +20,38 @@ type AuthenticateEvent[S any] struct {
 	Token Token
 
 	// Err is the error that resulted from authentication.  This field will be
-	// nil for a successful authentication.
+	// nil for a successful authentication.  Otherwise, this error is an *AuthError
+	// that describes the stage and component that failed.  If parsing failed, it
+	// wraps a *ParseError, which callers should obtain with errors.As rather than
+	// a type assertion.
 	Err error
+
+	// Attempts records each token parser that was invoked, in order, along with
+	// its outcome.  This field is nil when the result came from an AuthenticateCache
+	// or when no token parsers were invoked.
+	Attempts []ParseAttempt
+
+	// Validations records each validator that was invoked, in order.  Validation
+	// halts at the first failure, so only the last record can have an error.  This
+	// field is nil when the result came from an AuthenticateCache or when parsing failed.
+	Validations []ValidatorRecord
+
+	// Start is the time at which authentication began.
+	Start time.Time
+
+	// Duration is the total time taken by authentication, including parsing and validation.
+	Duration time.Duration
+}
+
+// Matched returns the attempt for the parser that produced the token.  If no parser
+// produced a token, including when the result came from a cache, this method
+// returns false.
+func (ae AuthenticateEvent[S]) Matched() (ParseAttempt, bool) {
+	if n := len(ae.Attempts); n > 0 && ae.Attempts[n-1].Outcome() == ParseMatched {
+		return ae.Attempts[n-1], true
+	}
+
+	return ParseAttempt{}, false
 }
 
 // AuthenticatorOption is a configurable option for an Authenticator.
@@ -51,8 +85,23 @@ func WithAuthenticateListenerFuncs[S any](more ...ListenerFunc[AuthenticateEvent
 	)
 }
 
+// WithAuthenticateClock sets the closure used to obtain the current time when
+// measuring the workflow for events.  By default, time.Now is used.  A nil closure
+// restores the default.
+func WithAuthenticateClock[S any](now func() time.Time) AuthenticatorOption[S] {
+	return authenticatorOptionFunc[S](
+		func(a *Authenticator[S]) error {
+			a.now = now
+			return nil
+		},
+	)
+}
+
 // WithTokenParsers adds token parsers to the Authenticator being built.
 // Multiple calls for this option are cumulative.
+//
+// The first parser to produce a token wins.  To require several credentials from
+// the same source, supply a single parser created with TokenParsers.All.
 func WithTokenParsers[S any](more ...TokenParser[S]) AuthenticatorOption[S] {
 	return authenticatorOptionFunc[S](
 		func(a *Authenticator[S]) error {
@@ -73,6 +122,38 @@ func WithValidators[S any](more ...Validator[S]) AuthenticatorOption[S] {
 	)
 }
 
+// WithAuthenticateCache enables caching of authentication results using the given cache.
+// A nil cache disables caching.  Listeners are still dispatched an event for each
+// call to Authenticate, whether or not the result came from the cache.
+//
+// A cache hit skips every parser and validator, including revocation, lockout, and
+// time window checks.  See AuthenticateCache for how to keep cached results current.
+func WithAuthenticateCache[S any](cache *AuthenticateCache[S]) AuthenticatorOption[S] {
+	return authenticatorOptionFunc[S](
+		func(a *Authenticator[S]) error {
+			a.cache = cache
+			return nil
+		},
+	)
+}
+
+// WithAuthenticatePanicRecovery enables panic recovery for the Authenticator being built.
+// A panic in any parser, validator, or listener is converted into a *PanicError and
+// reported to the given hook, which may be nil.  A panic in a parser or validator fails
+// authentication with that *PanicError.  A panic in a listener does not affect the
+// result or the remaining listeners.
+//
+// Panic recovery applies to all components, regardless of the order of options.
+func WithAuthenticatePanicRecovery[S any](hook PanicHook) AuthenticatorOption[S] {
+	return authenticatorOptionFunc[S](
+		func(a *Authenticator[S]) error {
+			a.recoverPanics = true
+			a.panicHook = hook
+			return nil
+		},
+	)
+}
+
 // NewAuthenticator constructs an Authenticator workflow using the supplied options.
 //
 // At least (1) token parser must be supplied in the options, or this
@@ -87,15 +168,49 @@ func NewAuthenticator[S any](opts ...AuthenticatorOption[S]) (a *Authenticator[S
 		return nil, ErrNoTokenParsers
 	}
 
+	if a.now == nil {
+		a.now = time.Now
+	}
+
+	if a.recoverPanics {
+		a.enablePanicRecovery()
+	}
+
 	return
 }
 
+// enablePanicRecovery decorates each of this Authenticator's components so that
+// panics are recovered.
+func (a *Authenticator[S]) enablePanicRecovery() {
+	for i, tp := range a.parsers {
+		a.parsers[i] = recoverTokenParser[S]{next: tp, hook: a.panicHook}
+	}
+
+	for i, v := range a.validators {
+		a.validators[i] = recoverValidator[S]{next: v, hook: a.panicHook}
+	}
+
+	a.listeners = a.listeners.Recover(a.panicHook)
+}
+
 // Authenticator provides bascule's authentication workflow.  This type handles
 // parsing tokens, validating them, and dispatching authentication events to listeners.
 type Authenticator[S any] struct {
 	listeners  Listeners[AuthenticateEvent[S]]
 	parsers    TokenParsers[S]
 	validators Validators[S]
+	cache      *AuthenticateCache[S]
+	now        func() time.Time
+
+	recoverPanics bool
+	panicHook     PanicHook
+}
+
+// TokenParsers returns a copy of the token parsers used by this Authenticator, in
+// order.  If panic recovery is enabled, each parser is decorated, and the original
+// parser can be obtained with an 'Unwrap() TokenParser[S]' method.
+func (a *Authenticator[S]) TokenParsers() TokenParsers[S] {
+	return slices.Clone(a.parsers)
 }
 
 // Authenticate implements bascule's authentication pipeline.  The following steps are
@@ -104,21 +219,108 @@ type Authenticator[S any] struct {
 // (1) The token is extracted from the source using the configured parser(s)
 // (2) The token is validated using any configured validator(s)
 // (3) Appropriate events are dispatched to listeners after either of steps (1) or (2)
+//
+// If a cache is configured, steps (1) and (2) are skipped whenever the source's
+// credential has a cached result.
+//
+// Any error from steps (1) or (2) is returned as an *AuthError that describes the
+// stage and component that failed.
 func (a *Authenticator[S]) Authenticate(ctx context.Context, source S) (token Token, err error) {
-	token, err = a.parsers.Parse(ctx, source)
-	if err == nil {
-		var next Token
-		next, err = a.validators.Validate(ctx, source, token)
-		if next != nil {
-			token = next
+	var (
+		key         cacheKey
+		cacheable   bool
+		ce          cacheEntry
+		hit         bool
+		attempts    []ParseAttempt
+		validations []ValidatorRecord
+		start       = a.now()
+	)
+
+	if a.cache != nil {
+		key, cacheable = a.cache.key(source)
+	}
+
+	if cacheable {
+		ce, hit = a.cache.get(key)
+	}
+
+	if hit {
+		token, err = ce.token, ce.err
+	} else {
+		token, attempts, validations, err = a.authenticate(ctx, source)
+		if cacheable {
+			a.cache.put(key, token, err)
 		}
 	}
 
 	a.listeners.OnEvent(AuthenticateEvent[S]{
-		Source: source,
-		Token:  token,
-		Err:    err,
+		Source:      source,
+		Token:       token,
+		Err:         err,
+		Attempts:    attempts,
+		Validations: validations,
+		Start:       start,
+		Duration:    a.now().Sub(start),
 	})
 
 	return
 }
+
+// authenticate performs the parsing and validation steps of the workflow.
+func (a *Authenticator[S]) authenticate(ctx context.Context, source S) (token Token, attempts []ParseAttempt, validations []ValidatorRecord, err error) {
+	token, attempts, err = a.parsers.parse(ctx, source)
+	if err != nil {
+		var component string
+		if n := len(attempts); n > 0 {
+			component = attempts[n-1].Name
+		}
+
+		err = newAuthError(err, AuthError{
+			Stage:     StageParse,
+			Component: component,
+		})
+
+		return
+	}
+
+	token, validations, err = a.validate(ctx, source, token)
+	if err != nil {
+		err = newAuthError(err, AuthError{
+			Stage:     StageValidate,
+			Principal: principalOf(token),
+			Component: validations[len(validations)-1].Name,
+		})
+	}
+
+	return
+}
+
+// validate runs each validator in turn, with the same semantics as Validators.Validate,
+// and records each validator's execution.  If validation fails, the original token
+// is returned along with the error.
+func (a *Authenticator[S]) validate(ctx context.Context, source S, original Token) (Token, []ValidatorRecord, error) {
+	var validations []ValidatorRecord
+	token := original
+	for i, v := range a.validators {
+		start := a.now()
+		next, err := v.Validate(ctx, source, token)
+		vr := ValidatorRecord{
+			Index:    i,
+			Name:     ComponentName(v),
+			Duration: a.now().Sub(start),
+			Err:      err,
+		}
+
+		if next != nil && !sameToken(next, token) {
+			vr.Replaced = true
+			token = next
+		}
+
+		validations = append(validations, vr)
+		if err != nil {
+			return original, validations, err
+		}
+	}
+
+	return token, validations, nil
+}
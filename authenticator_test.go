@@ -7,18 +7,33 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type AuthenticatorTestSuite struct {
 	suite.Suite
+
+	now time.Time
+}
+
+func (suite *AuthenticatorTestSuite) SetupTest() {
+	suite.now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *AuthenticatorTestSuite) clock() time.Time {
+	return suite.now
 }
 
 // newAuthenticator creates an Authenticator under test, asserting
-// that no errors occurred.
+// that no errors occurred.  The Authenticator uses this suite's clock.
 func (suite *AuthenticatorTestSuite) newAuthenticator(opts ...AuthenticatorOption[string]) *Authenticator[string] {
-	a, err := NewAuthenticator(opts...)
+	a, err := NewAuthenticator(
+		append([]AuthenticatorOption[string]{WithAuthenticateClock[string](suite.clock)}, opts...)...,
+	)
+
 	suite.Require().NoError(err)
 	suite.Require().NotNil(a)
 	return a
@@ -47,6 +62,17 @@ func (suite *AuthenticatorTestSuite) TestNoOptions() {
 	suite.ErrorIs(err, ErrNoTokenParsers)
 }
 
+func (suite *AuthenticatorTestSuite) TestTokenParsers() {
+	parser := new(mockTokenParser[string])
+	a := suite.newAuthenticator(WithTokenParsers[string](parser))
+
+	tps := a.TokenParsers()
+	suite.Equal(TokenParsers[string]{parser}, tps)
+
+	tps[0] = nil
+	suite.Equal(TokenParsers[string]{parser}, a.TokenParsers())
+}
+
 func (suite *AuthenticatorTestSuite) TestFullSuccess() {
 	var (
 		expectedCtx    = suite.newCtx()
@@ -72,17 +98,19 @@ func (suite *AuthenticatorTestSuite) TestFullSuccess() {
 	validator.ExpectValidate(expectedCtx, expectedSource, expectedToken).
 		Return(Token(nil), error(nil)).Once()
 
-	listener1.ExpectOnEvent(AuthenticateEvent[string]{
-		Source: expectedSource,
-		Token:  expectedToken,
-		Err:    nil,
-	}).Once()
+	expectedEvent := AuthenticateEvent[string]{
+		Source:   expectedSource,
+		Token:    expectedToken,
+		Err:      nil,
+		Attempts: []ParseAttempt{{Index: 0, Name: "*bascule.mockTokenParser[string]", Token: expectedToken}},
+		Validations: []ValidatorRecord{
+			{Index: 0, Name: "*bascule.mockValidator[string]"},
+		},
+		Start: suite.now,
+	}
 
-	listener2.ExpectOnEvent(AuthenticateEvent[string]{
-		Source: expectedSource,
-		Token:  expectedToken,
-		Err:    nil,
-	}).Once()
+	listener1.ExpectOnEvent(expectedEvent).Once()
+	listener2.ExpectOnEvent(expectedEvent).Once()
 
 	actualToken, err := a.Authenticate(expectedCtx, expectedSource)
 	suite.Equal(expectedToken, actualToken)
@@ -114,10 +142,18 @@ func (suite *AuthenticatorTestSuite) TestFullTokenParserFail() {
 	parser.ExpectParse(expectedCtx, expectedSource).
 		Return(Token(nil), expectedErr).Once()
 
+	expectedAttempts := []ParseAttempt{{Index: 0, Name: "*bascule.mockTokenParser[string]", Err: expectedErr}}
+
 	listener.ExpectOnEvent(AuthenticateEvent[string]{
 		Source: expectedSource,
 		Token:  nil,
-		Err:    expectedErr,
+		Err: &AuthError{
+			Stage:     StageParse,
+			Component: "*bascule.mockTokenParser[string]",
+			Err:       &ParseError{Attempts: expectedAttempts},
+		},
+		Attempts: expectedAttempts,
+		Start:    suite.now,
 	}).Once()
 
 	// we don't actually care what is returned for the token
@@ -156,7 +192,17 @@ func (suite *AuthenticatorTestSuite) TestFullValidatorFail() {
 	listener.ExpectOnEvent(AuthenticateEvent[string]{
 		Source: expectedSource,
 		Token:  expectedToken,
-		Err:    expectedErr,
+		Err: &AuthError{
+			Stage:     StageValidate,
+			Principal: "test",
+			Component: "*bascule.mockValidator[string]",
+			Err:       expectedErr,
+		},
+		Attempts: []ParseAttempt{{Index: 0, Name: "*bascule.mockTokenParser[string]", Token: expectedToken}},
+		Validations: []ValidatorRecord{
+			{Index: 0, Name: "*bascule.mockValidator[string]", Err: expectedErr},
+		},
+		Start: suite.now,
 	}).Once()
 
 	// we don't actually care what is returned for the token
@@ -168,6 +214,136 @@ func (suite *AuthenticatorTestSuite) TestFullValidatorFail() {
 	listener.AssertExpectations(suite.T())
 }
 
+func (suite *AuthenticatorTestSuite) TestNilTokenValidatorFail() {
+	var (
+		expectedErr = errors.New("expected")
+
+		a = suite.newAuthenticator(
+			WithTokenParsers(AsTokenParser[string](func(string) (Token, error) {
+				return nil, nil
+			})),
+			WithValidators(AsValidator[string](func(Token) error {
+				return expectedErr
+			})),
+		)
+	)
+
+	var err error
+	suite.NotPanics(func() {
+		_, err = a.Authenticate(suite.newCtx(), suite.newSource())
+	})
+
+	suite.ErrorIs(err, expectedErr)
+
+	var ae *AuthError
+	suite.Require().ErrorAs(err, &ae)
+	suite.Equal(StageValidate, ae.Stage)
+	suite.Empty(ae.Principal)
+}
+
+func (suite *AuthenticatorTestSuite) TestAttempts() {
+	var (
+		expectedCtx    = suite.newCtx()
+		expectedSource = suite.newSource()
+		expectedToken  = suite.newToken()
+
+		parser1  = new(mockTokenParser[string])
+		parser2  = new(mockTokenParser[string])
+		listener = new(mockAuthenticateListener[string])
+
+		a = suite.newAuthenticator(
+			WithTokenParsers[string](parser1, parser2),
+			WithAuthenticateListeners(listener),
+		)
+	)
+
+	parser1.ExpectParse(expectedCtx, expectedSource).
+		Return(Token(nil), ErrMissingCredentials).Once()
+
+	parser2.ExpectParse(expectedCtx, expectedSource).
+		Return(expectedToken, error(nil)).Once()
+
+	listener.ExpectOnEvent(AuthenticateEvent[string]{
+		Source: expectedSource,
+		Token:  expectedToken,
+		Attempts: []ParseAttempt{
+			{Index: 0, Name: "*bascule.mockTokenParser[string]", Err: ErrMissingCredentials},
+			{Index: 1, Name: "*bascule.mockTokenParser[string]", Token: expectedToken},
+		},
+		Start: suite.now,
+	}).Once()
+
+	actualToken, err := a.Authenticate(expectedCtx, expectedSource)
+	suite.Equal(expectedToken, actualToken)
+	suite.NoError(err)
+
+	parser1.AssertExpectations(suite.T())
+	parser2.AssertExpectations(suite.T())
+	listener.AssertExpectations(suite.T())
+}
+
+func (suite *AuthenticatorTestSuite) TestProvenance() {
+	var (
+		expectedCtx      = suite.newCtx()
+		expectedSource   = suite.newSource()
+		parsedToken      = suite.newToken()
+		replacementToken = StubToken("replacement")
+
+		parser     = new(mockTokenParser[string])
+		validator1 = new(mockValidator[string])
+		validator2 = new(mockValidator[string])
+
+		event AuthenticateEvent[string]
+
+		a = suite.newAuthenticator(
+			WithTokenParsers[string](parser),
+			WithValidators(validator1, validator2),
+			WithAuthenticateListenerFuncs(func(e AuthenticateEvent[string]) {
+				event = e
+			}),
+		)
+	)
+
+	start := suite.now
+	parser.ExpectParse(expectedCtx, expectedSource).
+		Return(parsedToken, error(nil)).Once()
+
+	validator1.ExpectValidate(expectedCtx, expectedSource, parsedToken).
+		Run(func(mock.Arguments) { suite.now = suite.now.Add(time.Second) }).
+		Return(replacementToken, error(nil)).Once()
+
+	validator2.ExpectValidate(expectedCtx, expectedSource, replacementToken).
+		Run(func(mock.Arguments) { suite.now = suite.now.Add(2 * time.Second) }).
+		Return(Token(nil), error(nil)).Once()
+
+	actualToken, err := a.Authenticate(expectedCtx, expectedSource)
+	suite.Require().NoError(err)
+	suite.Equal(replacementToken, actualToken)
+
+	suite.Equal(replacementToken, event.Token)
+	suite.Equal(start, event.Start)
+	suite.Equal(3*time.Second, event.Duration)
+	suite.Equal(
+		[]ValidatorRecord{
+			{Index: 0, Name: "*bascule.mockValidator[string]", Duration: time.Second, Replaced: true},
+			{Index: 1, Name: "*bascule.mockValidator[string]", Duration: 2 * time.Second},
+		},
+		event.Validations,
+	)
+
+	matched, ok := event.Matched()
+	suite.True(ok)
+	suite.Equal(0, matched.Index)
+	suite.Equal(parsedToken, matched.Token)
+
+	_, ok = AuthenticateEvent[string]{}.Matched()
+	suite.False(ok)
+
+	parser.AssertExpectations(suite.T())
+	validator1.AssertExpectations(suite.T())
+	validator2.AssertExpectations(suite.T())
+}
+
 func TestAuthenticator(t *testing.T) {
 	suite.Run(t, new(AuthenticatorTestSuite))
 }
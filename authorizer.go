@@ -6,6 +6,7 @@ package bascule
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
@@ -28,6 +29,26 @@ type AuthorizeEvent[R any] struct {
 	// Err is the error that resulted from authorization.  This field will be
 	// nil for a successful authorization..
 	Err error
+
+	// Approvals records each approver that was invoked, in order.  Authorization
+	// halts at the first denial, so only the last record can have an error.
+	Approvals []ApproverRecord
+
+	// Start is the time at which authorization began.
+	Start time.Time
+
+	// Duration is the total time taken by authorization.
+	Duration time.Duration
+}
+
+// Denied returns the record for the approver that denied access.  If access
+// was granted, this method returns false.
+func (ae AuthorizeEvent[R]) Denied() (ApproverRecord, bool) {
+	if n := len(ae.Approvals); n > 0 && ae.Approvals[n-1].Err != nil {
+		return ae.Approvals[n-1], true
+	}
+
+	return ApproverRecord{}, false
 }
 
 // AuthorizerOption is a configurable option for an Authorizer.
@@ -61,6 +82,18 @@ func WithAuthorizeListenerFuncs[R any](more ...ListenerFunc[AuthorizeEvent[R]])
 	)
 }
 
+// WithAuthorizeClock sets the closure used to obtain the current time when
+// measuring the workflow for events.  By default, time.Now is used.  A nil closure
+// restores the default.
+func WithAuthorizeClock[R any](now func() time.Time) AuthorizerOption[R] {
+	return authorizerOptionFunc[R](
+		func(a *Authorizer[R]) error {
+			a.now = now
+			return nil
+		},
+	)
+}
+
 // WithApprovers adds approvers to the Authorizer being built.
 // Multiple calls for this option are cumulative.
 func WithApprovers[R any](more ...Approver[R]) AuthorizerOption[R] {
@@ -83,6 +116,23 @@ func WithApproverFuncs[R any](more ...ApproverFunc[R]) AuthorizerOption[R] {
 	)
 }
 
+// WithAuthorizePanicRecovery enables panic recovery for the Authorizer being built.
+// A panic in any approver or listener is converted into a *PanicError and reported
+// to the given hook, which may be nil.  A panic in an approver denies access with
+// that *PanicError.  A panic in a listener does not affect the result or the
+// remaining listeners.
+//
+// Panic recovery applies to all components, regardless of the order of options.
+func WithAuthorizePanicRecovery[R any](hook PanicHook) AuthorizerOption[R] {
+	return authorizerOptionFunc[R](
+		func(a *Authorizer[R]) error {
+			a.recoverPanics = true
+			a.panicHook = hook
+			return nil
+		},
+	)
+}
+
 // NewAuthorizer constructs an Authorizer workflow using the supplied options.
 //
 // If no options are supplied, the returned Authorizer will authorize all tokens
@@ -93,6 +143,18 @@ func NewAuthorizer[R any](opts ...AuthorizerOption[R]) (a *Authorizer[R], err er
 		err = opts[i].apply(a)
 	}
 
+	if a.now == nil {
+		a.now = time.Now
+	}
+
+	if a.recoverPanics {
+		for i, approver := range a.approvers {
+			a.approvers[i] = recoverApprover[R]{next: approver, hook: a.panicHook}
+		}
+
+		a.listeners = a.listeners.Recover(a.panicHook)
+	}
+
 	return
 }
 
@@ -101,6 +163,10 @@ func NewAuthorizer[R any](opts ...AuthorizerOption[R]) (a *Authorizer[R], err er
 type Authorizer[R any] struct {
 	listeners Listeners[AuthorizeEvent[R]]
 	approvers Approvers[R]
+	now       func() time.Time
+
+	recoverPanics bool
+	panicHook     PanicHook
 }
 
 // Authorize implements the bascule authorization workflow for a particular type of
@@ -109,14 +175,49 @@ type Authorizer[R any] struct {
 // (1) Each approver is invoked, and all approvers must approve access
 // (2) An AuthorizeEvent is dispatched to any listeners with the result
 //
-// Any error that occurred during authorization is returned.
+// Any error that occurred during authorization is returned as an *AuthError that
+// describes the approver that denied access.
 func (a *Authorizer[R]) Authorize(ctx context.Context, resource R, token Token) (err error) {
-	err = a.approvers.Approve(ctx, resource, token)
+	start := a.now()
+	var approvals []ApproverRecord
+	for i, approver := range a.approvers {
+		approverStart := a.now()
+		err = approver.Approve(ctx, resource, token)
+		approvals = append(approvals, ApproverRecord{
+			Index:    i,
+			Name:     ComponentName(approver),
+			Duration: a.now().Sub(approverStart),
+			Err:      err,
+		})
+
+		if err != nil {
+			err = newAuthError(err, AuthError{
+				Stage:     StageAuthorize,
+				Principal: principalOf(token),
+				Component: approvals[i].Name,
+			})
+
+			break
+		}
+	}
+
 	a.listeners.OnEvent(AuthorizeEvent[R]{
-		Resource: resource,
-		Token:    token,
-		Err:      err,
+		Resource:  resource,
+		Token:     token,
+		Err:       err,
+		Approvals: approvals,
+		Start:     start,
+		Duration:  a.now().Sub(start),
 	})
 
 	return
 }
+
+// principalOf returns the token's principal, tolerating a nil token.
+func principalOf(t Token) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Principal()
+}
@@ -7,18 +7,33 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type AuthorizerTestSuite struct {
 	suite.Suite
+
+	now time.Time
+}
+
+func (suite *AuthorizerTestSuite) SetupTest() {
+	suite.now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *AuthorizerTestSuite) clock() time.Time {
+	return suite.now
 }
 
 // newAuthorizer creates an Authorizer under test, asserting
-// that no errors occurred.
+// that no errors occurred.  The Authorizer uses this suite's clock.
 func (suite *AuthorizerTestSuite) newAuthorizer(opts ...AuthorizerOption[string]) *Authorizer[string] {
-	a, err := NewAuthorizer(opts...)
+	a, err := NewAuthorizer(
+		append([]AuthorizerOption[string]{WithAuthorizeClock[string](suite.clock)}, opts...)...,
+	)
+
 	suite.Require().NoError(err)
 	suite.Require().NotNil(a)
 	return a
@@ -81,17 +96,20 @@ func (suite *AuthorizerTestSuite) TestFullSuccess() {
 	approver3.ExpectApprove(expectedCtx, expectedResource, expectedToken).
 		Return(nil).Once()
 
-	listener1.ExpectOnEvent(AuthorizeEvent[string]{
+	expectedEvent := AuthorizeEvent[string]{
 		Resource: expectedResource,
 		Token:    expectedToken,
 		Err:      nil,
-	})
+		Approvals: []ApproverRecord{
+			{Index: 0, Name: "*bascule.mockApprover[string]"},
+			{Index: 1, Name: "*bascule.mockApprover[string]"},
+			{Index: 2, Name: "bascule.ApproverFunc[string]"},
+		},
+		Start: suite.now,
+	}
 
-	listener2.ExpectOnEvent(AuthorizeEvent[string]{
-		Resource: expectedResource,
-		Token:    expectedToken,
-		Err:      nil,
-	})
+	listener1.ExpectOnEvent(expectedEvent)
+	listener2.ExpectOnEvent(expectedEvent)
 
 	err := a.Authorize(expectedCtx, expectedResource, expectedToken)
 	suite.NoError(err)
@@ -127,7 +145,16 @@ func (suite *AuthorizerTestSuite) TestFullFirstApproverFail() {
 	listener.ExpectOnEvent(AuthorizeEvent[string]{
 		Resource: expectedResource,
 		Token:    expectedToken,
-		Err:      expectedErr,
+		Err: &AuthError{
+			Stage:     StageAuthorize,
+			Principal: "test",
+			Component: "*bascule.mockApprover[string]",
+			Err:       expectedErr,
+		},
+		Approvals: []ApproverRecord{
+			{Index: 0, Name: "*bascule.mockApprover[string]", Err: expectedErr},
+		},
+		Start: suite.now,
 	})
 
 	err := a.Authorize(expectedCtx, expectedResource, expectedToken)
@@ -164,7 +191,17 @@ func (suite *AuthorizerTestSuite) TestFullSecondApproverFail() {
 	listener.ExpectOnEvent(AuthorizeEvent[string]{
 		Resource: expectedResource,
 		Token:    expectedToken,
-		Err:      expectedErr,
+		Err: &AuthError{
+			Stage:     StageAuthorize,
+			Principal: "test",
+			Component: "*bascule.mockApprover[string]",
+			Err:       expectedErr,
+		},
+		Approvals: []ApproverRecord{
+			{Index: 0, Name: "*bascule.mockApprover[string]"},
+			{Index: 1, Name: "*bascule.mockApprover[string]", Err: expectedErr},
+		},
+		Start: suite.now,
 	})
 
 	err := a.Authorize(expectedCtx, expectedResource, expectedToken)
@@ -175,6 +212,57 @@ func (suite *AuthorizerTestSuite) TestFullSecondApproverFail() {
 	approver2.AssertExpectations(suite.T())
 }
 
+func (suite *AuthorizerTestSuite) TestDenied() {
+	var (
+		expectedCtx      = suite.newCtx()
+		expectedResource = suite.newResource()
+		expectedToken    = suite.newToken()
+		expectedErr      = errors.New("expected")
+
+		approver1 = new(mockApprover[string])
+		approver2 = new(mockApprover[string])
+
+		event AuthorizeEvent[string]
+
+		a = suite.newAuthorizer(
+			WithApprovers(approver1, approver2),
+			WithAuthorizeListenerFuncs(func(e AuthorizeEvent[string]) {
+				event = e
+			}),
+		)
+	)
+
+	start := suite.now
+	approver1.ExpectApprove(expectedCtx, expectedResource, expectedToken).
+		Run(func(mock.Arguments) { suite.now = suite.now.Add(time.Second) }).
+		Return(nil).Once()
+	approver2.ExpectApprove(expectedCtx, expectedResource, expectedToken).
+		Run(func(mock.Arguments) { suite.now = suite.now.Add(2 * time.Second) }).
+		Return(expectedErr).Once()
+
+	err := a.Authorize(expectedCtx, expectedResource, expectedToken)
+	suite.ErrorIs(err, expectedErr)
+
+	suite.Equal(start, event.Start)
+	suite.Equal(3*time.Second, event.Duration)
+
+	denied, ok := event.Denied()
+	suite.True(ok)
+	suite.Equal(
+		ApproverRecord{Index: 1, Name: "*bascule.mockApprover[string]", Duration: 2 * time.Second, Err: expectedErr},
+		denied,
+	)
+
+	_, ok = AuthorizeEvent[string]{Approvals: event.Approvals[:1]}.Denied()
+	suite.False(ok)
+
+	_, ok = AuthorizeEvent[string]{}.Denied()
+	suite.False(ok)
+
+	approver1.AssertExpectations(suite.T())
+	approver2.AssertExpectations(suite.T())
+}
+
 func TestAuthorizer(t *testing.T) {
 	suite.Run(t, new(AuthorizerTestSuite))
 }
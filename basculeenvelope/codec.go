@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// GenericCodecName is the name of the codec used for tokens whose type has
+	// no registered codec.
+	GenericCodecName = "generic"
+
+	// multiCodecName is the reserved name of the node type that represents a
+	// bascule.MultiToken.
+	multiCodecName = "multi"
+
+	// delegationCodecName is the reserved name of the node type that represents
+	// a *bascule.DelegationToken.
+	delegationCodecName = "delegation"
+)
+
+var (
+	// ErrDuplicateCodec indicates that a codec name or token type was already registered.
+	ErrDuplicateCodec = errors.New("codec already registered")
+
+	// ErrUnknownCodec indicates that an envelope referred to a codec that is not registered.
+	ErrUnknownCodec = errors.New("unknown codec")
+)
+
+// Codec marshals tokens of a particular type to and from JSON.
+type Codec interface {
+	// Marshal produces the JSON representation of a token.
+	Marshal(bascule.Token) ([]byte, error)
+
+	// Unmarshal reconstructs a token from its JSON representation.
+	Unmarshal([]byte) (bascule.Token, error)
+}
+
+// AttributesMap is an optional interface that a token may implement to expose all of
+// its attributes.  bascule.AttributesAccessor has no way to enumerate keys, so the
+// generic codec uses this interface to discover the attributes to marshal.
+type AttributesMap interface {
+	// Attributes returns every attribute of the token.
+	Attributes() map[string]any
+}
+
+// Token is the token produced when the generic codec unmarshals an envelope.  It
+// implements bascule.CapabilitiesAccessor, bascule.AttributesAccessor, and AttributesMap.
+type Token struct {
+	principal    string
+	capabilities []string
+	attributes   map[string]any
+}
+
+// Principal returns the principal carried by the envelope.
+func (t *Token) Principal() string { return t.principal }
+
+// Capabilities returns a copy of the capabilities carried by the envelope.
+func (t *Token) Capabilities() []string { return slices.Clone(t.capabilities) }
+
+// Get returns an attribute carried by the envelope.  Note that numeric attributes are
+// represented as float64, as with encoding/json.  bascule.GetAttribute will convert
+// these to other numeric types.
+func (t *Token) Get(key string) (v any, ok bool) {
+	v, ok = t.attributes[key]
+	return
+}
+
+// Attributes returns a copy of all the attributes carried by the envelope.  The copy
+// is shallow, so nested attribute values must not be modified.
+func (t *Token) Attributes() map[string]any { return maps.Clone(t.attributes) }
+
+// genericForm is the JSON representation used by the generic codec.
+type genericForm struct {
+	Principal    string         `json:"principal"`
+	Capabilities []string       `json:"capabilities,omitempty"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+}
+
+type genericCodec struct {
+	keys []string
+}
+
+// NewGenericCodec creates the codec used for tokens whose type has no registered codec.
+// The principal and any capabilities are always marshaled.  Attributes are taken from
+// AttributesMap, if the token implements it.  Otherwise, only the given keys are
+// fetched from the token's bascule.AttributesAccessor.
+//
+// Only the token's own methods are consulted.  Subtokens are marshaled separately, as
+// children in the envelope's token tree, so their capabilities and attributes are never
+// copied into their parent.
+func NewGenericCodec(attributeKeys ...string) Codec {
+	return genericCodec{
+		keys: append([]string(nil), attributeKeys...),
+	}
+}
+
+func (gc genericCodec) Marshal(t bascule.Token) ([]byte, error) {
+	gf := genericForm{
+		Principal: t.Principal(),
+	}
+
+	if ca, ok := t.(bascule.CapabilitiesAccessor); ok {
+		gf.Capabilities = ca.Capabilities()
+	}
+
+	am, isMap := t.(AttributesMap)
+	aa, isAccessor := t.(bascule.AttributesAccessor)
+	switch {
+	case isMap:
+		gf.Attributes = am.Attributes()
+
+	case len(gc.keys) > 0 && isAccessor:
+		gf.Attributes = make(map[string]any, len(gc.keys))
+		for _, k := range gc.keys {
+			if v, ok := aa.Get(k); ok {
+				gf.Attributes[k] = v
+			}
+		}
+	}
+
+	return json.Marshal(gf)
+}
+
+func (gc genericCodec) Unmarshal(data []byte) (bascule.Token, error) {
+	var gf genericForm
+	if err := json.Unmarshal(data, &gf); err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		principal:    gf.Principal,
+		capabilities: gf.Capabilities,
+		attributes:   gf.Attributes,
+	}, nil
+}
+
+// actorForm is the JSON representation of a bascule.Actor, using the member names
+// of an RFC 8693 act claim.
+type actorForm struct {
+	Subject string `json:"sub"`
+	Issuer  string `json:"iss,omitempty"`
+}
+
+// marshalActors produces the node data for a *bascule.DelegationToken.
+func marshalActors(actors []bascule.Actor) ([]byte, error) {
+	forms := make([]actorForm, len(actors))
+	for i, a := range actors {
+		forms[i] = actorForm{Subject: a.Subject, Issuer: a.Issuer}
+	}
+
+	return json.Marshal(forms)
+}
+
+// unmarshalActors reconstructs the actor chain of a *bascule.DelegationToken.
+func unmarshalActors(data []byte) ([]bascule.Actor, error) {
+	var forms []actorForm
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return nil, err
+	}
+
+	actors := make([]bascule.Actor, len(forms))
+	for i, f := range forms {
+		actors[i] = bascule.Actor{Subject: f.Subject, Issuer: f.Issuer}
+	}
+
+	return actors, nil
+}
+
+// jsonCodec marshals a token type directly with encoding/json.
+type jsonCodec[T bascule.Token] struct{}
+
+func (jsonCodec[T]) Marshal(t bascule.Token) ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (jsonCodec[T]) Unmarshal(data []byte) (bascule.Token, error) {
+	var t T
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// NewJSONCodec creates a Codec for a token type that marshals directly to and from JSON,
+// typically a struct with exported fields.  If T is a pointer type, Unmarshal allocates
+// a new value.
+func NewJSONCodec[T bascule.Token]() Codec {
+	return jsonCodec[T]{}
+}
+
+// Registry maps token types to the codecs that marshal them.  A Registry is safe for
+// concurrent use.
+type Registry struct {
+	lock   sync.RWMutex
+	byName map[string]Codec
+	byType map[reflect.Type]string
+}
+
+// NewRegistry creates a Registry that contains only the generic codec.
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: map[string]Codec{
+			GenericCodecName: NewGenericCodec(),
+		},
+		byType: make(map[reflect.Type]string),
+	}
+}
+
+// SetGenericCodec replaces the codec used for tokens with no registered codec.
+func (r *Registry) SetGenericCodec(c Codec) {
+	r.lock.Lock()
+	r.byName[GenericCodecName] = c
+	r.lock.Unlock()
+}
+
+// Register associates a codec with the token type T under the given name.  The name
+// is written into envelopes, so it must be the same for senders and recipients.
+// Names and types may only be registered once.
+func Register[T bascule.Token](r *Registry, name string, c Codec) error {
+	t := reflect.TypeFor[T]()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, nameExists := r.byName[name]
+	_, typeExists := r.byType[t]
+	if nameExists || typeExists || name == multiCodecName || name == delegationCodecName {
+		return ErrDuplicateCodec
+	}
+
+	r.byName[name] = c
+	r.byType[t] = name
+	return nil
+}
+
+// forToken returns the name and codec for a token.
+func (r *Registry) forToken(t bascule.Token) (string, Codec) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	name, ok := r.byType[reflect.TypeOf(t)]
+	if !ok {
+		name = GenericCodecName
+	}
+
+	return name, r.byName[name]
+}
+
+// forName returns the codec with the given name.
+func (r *Registry) forName(name string) (c Codec, ok bool) {
+	r.lock.RLock()
+	c, ok = r.byName[name]
+	r.lock.RUnlock()
+	return
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type CodecTestSuite struct {
+	TestSuite
+}
+
+func (suite *CodecTestSuite) TestGenericCodec() {
+	suite.Run("AttributesMap", func() {
+		c := NewGenericCodec()
+		data, err := c.Marshal(attributesToken{
+			principal:    "joe",
+			capabilities: []string{"doc:read"},
+			attributes:   map[string]any{"level": 3, "name": "Joe"},
+		})
+
+		suite.Require().NoError(err)
+		suite.JSONEq(
+			`{"principal": "joe", "capabilities": ["doc:read"], "attributes": {"level": 3, "name": "Joe"}}`,
+			string(data),
+		)
+
+		t, err := c.Unmarshal(data)
+		suite.Require().NoError(err)
+		suite.Require().IsType((*Token)(nil), t)
+		suite.Equal("joe", t.Principal())
+		suite.Equal([]string{"doc:read"}, t.(*Token).Capabilities())
+
+		level, ok := bascule.GetAttribute[int](t.(*Token), "level")
+		suite.True(ok)
+		suite.Equal(3, level)
+		suite.Equal(map[string]any{"level": 3.0, "name": "Joe"}, t.(*Token).Attributes())
+	})
+
+	suite.Run("Immutable", func() {
+		c := NewGenericCodec()
+		t, err := c.Unmarshal([]byte(`{"principal": "joe", "capabilities": ["doc:read"], "attributes": {"name": "Joe"}}`))
+		suite.Require().NoError(err)
+		suite.Require().IsType((*Token)(nil), t)
+
+		t.(*Token).Capabilities()[0] = "doc:write"
+		t.(*Token).Attributes()["name"] = "Jane"
+		t.(*Token).Attributes()["admin"] = true
+
+		suite.Equal([]string{"doc:read"}, t.(*Token).Capabilities())
+		suite.Equal(map[string]any{"name": "Joe"}, t.(*Token).Attributes())
+	})
+
+	suite.Run("AttributeKeys", func() {
+		c := NewGenericCodec("name", "missing")
+		data, err := c.Marshal(accessorToken{
+			principal:  "joe",
+			attributes: map[string]any{"name": "Joe", "secret": "xyz"},
+		})
+
+		suite.Require().NoError(err)
+		suite.JSONEq(`{"principal": "joe", "attributes": {"name": "Joe"}}`, string(data))
+	})
+
+	suite.Run("PrincipalOnly", func() {
+		data, err := NewGenericCodec().Marshal(accessorToken{principal: "joe"})
+		suite.Require().NoError(err)
+		suite.JSONEq(`{"principal": "joe"}`, string(data))
+	})
+
+	suite.Run("BadJSON", func() {
+		t, err := NewGenericCodec().Unmarshal([]byte("{"))
+		suite.Nil(t)
+		suite.Error(err)
+	})
+}
+
+func (suite *CodecTestSuite) TestJSONCodec() {
+	c := NewJSONCodec[deviceToken]()
+	data, err := c.Marshal(deviceToken{ID: "mac:112233445566", Model: "xb7"})
+	suite.Require().NoError(err)
+	suite.JSONEq(`{"id": "mac:112233445566", "model": "xb7"}`, string(data))
+
+	t, err := c.Unmarshal(data)
+	suite.Require().NoError(err)
+	suite.Equal(deviceToken{ID: "mac:112233445566", Model: "xb7"}, t)
+
+	t, err = c.Unmarshal([]byte("["))
+	suite.Nil(t)
+	suite.Error(err)
+}
+
+func (suite *CodecTestSuite) TestRegistry() {
+	r := NewRegistry()
+	suite.NoError(Register[deviceToken](r, "device", NewJSONCodec[deviceToken]()))
+	suite.ErrorIs(Register[deviceToken](r, "other", NewJSONCodec[deviceToken]()), ErrDuplicateCodec)
+	suite.ErrorIs(Register[accessorToken](r, "device", NewGenericCodec()), ErrDuplicateCodec)
+	suite.ErrorIs(Register[accessorToken](r, GenericCodecName, NewGenericCodec()), ErrDuplicateCodec)
+	suite.ErrorIs(Register[accessorToken](r, multiCodecName, NewGenericCodec()), ErrDuplicateCodec)
+	suite.ErrorIs(Register[accessorToken](r, delegationCodecName, NewGenericCodec()), ErrDuplicateCodec)
+
+	name, c := r.forToken(deviceToken{})
+	suite.Equal("device", name)
+	suite.NotNil(c)
+
+	name, c = r.forToken(accessorToken{})
+	suite.Equal(GenericCodecName, name)
+	suite.NotNil(c)
+
+	generic := NewGenericCodec("name")
+	r.SetGenericCodec(generic)
+	c, ok := r.forName(GenericCodecName)
+	suite.True(ok)
+	suite.Equal(generic, c)
+
+	_, ok = r.forName("nosuch")
+	suite.False(ok)
+}
+
+func TestCodec(t *testing.T) {
+	suite.Run(t, new(CodecTestSuite))
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculeenvelope serializes bascule tokens so that an authenticated identity
+can be forwarded to downstream services.
+
+An envelope is a compact, URL-safe string of the form:
+
+	base64url(payload) "." base64url(signature)
+
+The payload is JSON that holds the token tree, along with the signing algorithm and
+the envelope's issue and expiry times.  Each token in the tree is marshaled by a Codec
+registered for its type.  Tokens without a registered codec are marshaled by the
+generic codec, which preserves the principal, capabilities, and attributes.  Trees of
+tokens, such as bascule.MultiToken, are preserved, as are the actors of a
+*bascule.DelegationToken.  Other tokens that implement bascule.DelegationAccessor lose
+their actors unless a Codec that preserves them is registered.
+
+Envelopes are signed with either HMAC-SHA256 or Ed25519.  A Decoder rejects any envelope
+whose signature does not verify, so recipients can trust the identity it carries.  A
+Decoder is also a bascule.TokenParser[string].
+*/
+package basculeenvelope
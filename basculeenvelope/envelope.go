@@ -0,0 +1,396 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// Version is the envelope format version written by an Encoder.
+	Version = 1
+
+	// DefaultTTL is the default lifetime of an envelope.
+	DefaultTTL = 5 * time.Minute
+
+	// DefaultMaxDepth is the default maximum depth of a token tree within an envelope.
+	DefaultMaxDepth = 8
+)
+
+var (
+	// ErrInvalidConfig indicates that an Encoder or Decoder option was invalid.
+	ErrInvalidConfig = errors.New("invalid envelope configuration")
+
+	// ErrMalformedEnvelope indicates that an envelope could not be parsed.
+	ErrMalformedEnvelope = errors.New("malformed envelope")
+
+	// ErrTreeTooDeep indicates that a token tree exceeded the maximum depth.
+	ErrTreeTooDeep = errors.New("token tree too deep")
+)
+
+// Option is a configurable option for an Encoder or a Decoder.
+type Option interface {
+	apply(*config) error
+}
+
+type optionFunc func(*config) error
+
+func (of optionFunc) apply(c *config) error { return of(c) }
+
+// WithRegistry sets the Registry of codecs.  Encoders and Decoders that exchange
+// envelopes must register the same codecs under the same names.  By default, a
+// Registry with only the generic codec is used.
+func WithRegistry(r *Registry) Option {
+	return optionFunc(func(c *config) error {
+		if r == nil {
+			return ErrInvalidConfig
+		}
+
+		c.registry = r
+		return nil
+	})
+}
+
+// WithTTL sets the lifetime of envelopes produced by an Encoder.  A zero value
+// produces envelopes that never expire.  This option has no effect on a Decoder.
+// If this option is not supplied, DefaultTTL is used.
+func WithTTL(ttl time.Duration) Option {
+	return optionFunc(func(c *config) error {
+		if ttl < 0 {
+			return ErrInvalidConfig
+		}
+
+		c.ttl = ttl
+		return nil
+	})
+}
+
+// WithMaxDepth sets the maximum depth of a token tree that may be encoded or decoded.
+// If this option is not supplied, DefaultMaxDepth is used.
+func WithMaxDepth(maxDepth int) Option {
+	return optionFunc(func(c *config) error {
+		if maxDepth < 1 {
+			return ErrInvalidConfig
+		}
+
+		c.maxDepth = maxDepth
+		return nil
+	})
+}
+
+// WithClock sets the closure used to obtain the current time.  By default,
+// time.Now is used.  A nil closure restores the default.
+func WithClock(now func() time.Time) Option {
+	return optionFunc(func(c *config) error {
+		c.now = now
+		return nil
+	})
+}
+
+type config struct {
+	registry *Registry
+	ttl      time.Duration
+	maxDepth int
+	now      func() time.Time
+}
+
+func newConfig(opts []Option) (c config, err error) {
+	c = config{
+		ttl:      DefaultTTL,
+		maxDepth: DefaultMaxDepth,
+	}
+
+	for i := 0; err == nil && i < len(opts); i++ {
+		err = opts[i].apply(&c)
+	}
+
+	if c.registry == nil {
+		c.registry = NewRegistry()
+	}
+
+	if c.now == nil {
+		c.now = time.Now
+	}
+
+	return
+}
+
+// node is the JSON representation of a single token in a tree.
+type node struct {
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	Children []node          `json:"children,omitempty"`
+}
+
+// payload is the signed JSON content of an envelope.
+type payload struct {
+	Version   int    `json:"v"`
+	Algorithm string `json:"alg"`
+	IssuedAt  int64  `json:"iat"`
+	Expires   int64  `json:"exp,omitempty"`
+	Token     node   `json:"token"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// Encoder marshals tokens into signed envelopes.  An Encoder is safe for concurrent use.
+type Encoder struct {
+	signer Signer
+	cfg    config
+}
+
+// NewEncoder creates an Encoder that signs envelopes with the given Signer.
+func NewEncoder(signer Signer, opts ...Option) (*Encoder, error) {
+	if signer == nil {
+		return nil, ErrInvalidConfig
+	}
+
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encoder{
+		signer: signer,
+		cfg:    cfg,
+	}, nil
+}
+
+// Encode marshals a token, along with any subtokens, into a signed envelope.
+func (e *Encoder) Encode(t bascule.Token) (string, error) {
+	root, err := e.encodeNode(t, 1)
+	if err != nil {
+		return "", err
+	}
+
+	now := e.cfg.now()
+	p := payload{
+		Version:   Version,
+		Algorithm: e.signer.Algorithm(),
+		IssuedAt:  now.Unix(),
+		Token:     root,
+	}
+
+	if e.cfg.ttl > 0 {
+		p.Expires = now.Add(e.cfg.ttl).Unix()
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := encoding.EncodeToString(data)
+	signature, err := e.signer.Sign([]byte(encoded))
+	if err != nil {
+		return "", err
+	}
+
+	return encoded + "." + encoding.EncodeToString(signature), nil
+}
+
+func (e *Encoder) encodeNode(t bascule.Token, depth int) (n node, err error) {
+	if depth > e.cfg.maxDepth {
+		return node{}, ErrTreeTooDeep
+	}
+
+	var children []bascule.Token
+	switch tt := t.(type) {
+	case bascule.MultiToken:
+		n.Type = multiCodecName
+		children = tt
+
+	case *bascule.DelegationToken:
+		n.Type = delegationCodecName
+		if n.Data, err = marshalActors(tt.Actors()); err != nil {
+			return node{}, err
+		}
+
+		children = bascule.UnwrapToken(t)
+
+	default:
+		var c Codec
+		n.Type, c = e.cfg.registry.forToken(t)
+		if n.Data, err = c.Marshal(t); err != nil {
+			return node{}, err
+		}
+
+		children = bascule.UnwrapToken(t)
+	}
+
+	for _, child := range children {
+		var cn node
+		if cn, err = e.encodeNode(child, depth+1); err != nil {
+			return node{}, err
+		}
+
+		n.Children = append(n.Children, cn)
+	}
+
+	return
+}
+
+// treeToken is produced when a decoded token had subtokens.  The decoded token's
+// own methods are still available via bascule.TokenAs.
+type treeToken struct {
+	bascule.Token
+	children []bascule.Token
+}
+
+func (tt treeToken) Unwrap() []bascule.Token { return tt.children }
+
+// Decoder verifies and unmarshals envelopes.  A Decoder is safe for concurrent use.
+type Decoder struct {
+	verifier Verifier
+	cfg      config
+}
+
+var _ bascule.TokenParser[string] = (*Decoder)(nil)
+
+// NewDecoder creates a Decoder that verifies envelopes with the given Verifier.
+func NewDecoder(verifier Verifier, opts ...Option) (*Decoder, error) {
+	if verifier == nil {
+		return nil, ErrInvalidConfig
+	}
+
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decoder{
+		verifier: verifier,
+		cfg:      cfg,
+	}, nil
+}
+
+// Decode verifies an envelope and unmarshals its token tree.  The signature is verified
+// before any part of the payload is unmarshaled.
+//
+// An envelope that cannot be parsed, or that refers to an unregistered codec, results
+// in an error with both bascule.ErrInvalidCredentials and a more specific error in its
+// chain.  An envelope with the wrong algorithm or a bad signature results in an error
+// with bascule.ErrBadCredentials and ErrInvalidSignature in its chain.  An expired
+// envelope results in bascule.ErrTokenExpired.
+func (d *Decoder) Decode(envelope string) (bascule.Token, error) {
+	encoded, encodedSignature, ok := cutEnvelope(envelope)
+	if !ok {
+		return nil, malformed(nil)
+	}
+
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, malformed(err)
+	}
+
+	data, err := encoding.DecodeString(encoded)
+	if err != nil {
+		return nil, malformed(err)
+	}
+
+	// nothing within the payload is trusted, or even unmarshaled, until
+	// the signature has been verified
+	if err = d.verifier.Verify([]byte(encoded), signature); err != nil {
+		return nil, fmt.Errorf("%w: %w", bascule.ErrBadCredentials, err)
+	}
+
+	var p payload
+	if err = json.Unmarshal(data, &p); err != nil || p.Version != Version {
+		return nil, malformed(err)
+	}
+
+	// the algorithm must match, which prevents a Verifier from being used
+	// with a mismatched signature scheme
+	if p.Algorithm != d.verifier.Algorithm() {
+		return nil, fmt.Errorf("%w: %w", bascule.ErrBadCredentials, ErrInvalidSignature)
+	}
+
+	if p.Expires > 0 && !d.cfg.now().Before(time.Unix(p.Expires, 0)) {
+		return nil, bascule.ErrTokenExpired
+	}
+
+	return d.decodeNode(p.Token, 1)
+}
+
+// Parse decodes the source as an envelope.  This method allows a Decoder to be used
+// as a bascule.TokenParser[string].
+func (d *Decoder) Parse(_ context.Context, source string) (bascule.Token, error) {
+	return d.Decode(source)
+}
+
+func (d *Decoder) decodeNode(n node, depth int) (bascule.Token, error) {
+	if depth > d.cfg.maxDepth {
+		return nil, fmt.Errorf("%w: %w", bascule.ErrInvalidCredentials, ErrTreeTooDeep)
+	}
+
+	children := make([]bascule.Token, 0, len(n.Children))
+	for _, cn := range n.Children {
+		child, err := d.decodeNode(cn, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, child)
+	}
+
+	switch n.Type {
+	case multiCodecName:
+		if len(children) == 0 {
+			return nil, malformed(nil)
+		}
+
+		return bascule.MultiToken(children), nil
+
+	case delegationCodecName:
+		actors, err := unmarshalActors(n.Data)
+		if err != nil || len(actors) == 0 || len(children) != 1 {
+			return nil, malformed(err)
+		}
+
+		return bascule.NewDelegationToken(children[0], actors...), nil
+	}
+
+	c, ok := d.cfg.registry.forName(n.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %q", bascule.ErrInvalidCredentials, ErrUnknownCodec, n.Type)
+	}
+
+	t, err := c.Unmarshal(n.Data)
+	if err != nil {
+		return nil, malformed(err)
+	}
+
+	if len(children) > 0 {
+		t = treeToken{
+			Token:    t,
+			children: children,
+		}
+	}
+
+	return t, nil
+}
+
+// cutEnvelope splits an envelope into its encoded payload and signature.
+func cutEnvelope(envelope string) (encoded, signature string, ok bool) {
+	encoded, signature, ok = strings.Cut(envelope, ".")
+	ok = ok && len(encoded) > 0 && len(signature) > 0
+	return
+}
+
+// malformed produces the error returned for an envelope that cannot be parsed.
+func malformed(cause error) error {
+	if cause == nil {
+		return fmt.Errorf("%w: %w", bascule.ErrInvalidCredentials, ErrMalformedEnvelope)
+	}
+
+	return fmt.Errorf("%w: %w: %w", bascule.ErrInvalidCredentials, ErrMalformedEnvelope, cause)
+}
@@ -0,0 +1,384 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+// wrapperToken decorates a single subtoken.
+type wrapperToken struct {
+	principal string
+	wrapped   bascule.Token
+}
+
+func (t wrapperToken) Principal() string     { return t.principal }
+func (t wrapperToken) Unwrap() bascule.Token { return t.wrapped }
+
+type EnvelopeTestSuite struct {
+	TestSuite
+
+	now time.Time
+}
+
+func (suite *EnvelopeTestSuite) SetupTest() {
+	suite.now = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *EnvelopeTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *EnvelopeTestSuite) newEncoder(opts ...Option) *Encoder {
+	e, err := NewEncoder(suite.newHMAC(), append([]Option{WithClock(suite.clock)}, opts...)...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(e)
+	return e
+}
+
+func (suite *EnvelopeTestSuite) newDecoder(opts ...Option) *Decoder {
+	d, err := NewDecoder(suite.newHMAC(), append([]Option{WithClock(suite.clock)}, opts...)...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(d)
+	return d
+}
+
+func (suite *EnvelopeTestSuite) encode(e *Encoder, t bascule.Token) string {
+	envelope, err := e.Encode(t)
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(envelope)
+	return envelope
+}
+
+// sign produces a correctly signed envelope with an arbitrary payload.
+func (suite *EnvelopeTestSuite) sign(data []byte) string {
+	encoded := encoding.EncodeToString(data)
+	signature, err := suite.newHMAC().Sign([]byte(encoded))
+	suite.Require().NoError(err)
+	return encoded + "." + encoding.EncodeToString(signature)
+}
+
+// resign replaces the payload of an envelope and signs it again.
+func (suite *EnvelopeTestSuite) resign(envelope string, modify func(map[string]any)) string {
+	encoded, _, _ := strings.Cut(envelope, ".")
+	data, err := encoding.DecodeString(encoded)
+	suite.Require().NoError(err)
+
+	var p map[string]any
+	suite.Require().NoError(json.Unmarshal(data, &p))
+	modify(p)
+	data, err = json.Marshal(p)
+	suite.Require().NoError(err)
+	return suite.sign(data)
+}
+
+func (suite *EnvelopeTestSuite) TestInvalidConfig() {
+	e, err := NewEncoder(nil)
+	suite.Nil(e)
+	suite.ErrorIs(err, ErrInvalidConfig)
+
+	d, err := NewDecoder(nil)
+	suite.Nil(d)
+	suite.ErrorIs(err, ErrInvalidConfig)
+
+	for _, o := range []Option{WithRegistry(nil), WithTTL(-1), WithMaxDepth(0)} {
+		e, err = NewEncoder(suite.newHMAC(), o)
+		suite.Nil(e)
+		suite.ErrorIs(err, ErrInvalidConfig)
+
+		d, err = NewDecoder(suite.newHMAC(), o)
+		suite.Nil(d)
+		suite.ErrorIs(err, ErrInvalidConfig)
+	}
+}
+
+func (suite *EnvelopeTestSuite) TestGeneric() {
+	envelope := suite.encode(suite.newEncoder(), attributesToken{
+		principal:    "joe",
+		capabilities: []string{"doc:read", "doc:write"},
+		attributes:   map[string]any{"tenant": "acme"},
+	})
+
+	t, err := suite.newDecoder().Decode(envelope)
+	suite.Require().NoError(err)
+	suite.Equal("joe", t.Principal())
+
+	caps, ok := bascule.GetCapabilities(t)
+	suite.True(ok)
+	suite.Equal([]string{"doc:read", "doc:write"}, caps)
+
+	tenant, ok := bascule.GetAttribute[string](t.(*Token), "tenant")
+	suite.True(ok)
+	suite.Equal("acme", tenant)
+}
+
+func (suite *EnvelopeTestSuite) TestRegisteredCodec() {
+	r := NewRegistry()
+	suite.Require().NoError(Register[deviceToken](r, "device", NewJSONCodec[deviceToken]()))
+
+	envelope := suite.encode(suite.newEncoder(WithRegistry(r)), deviceToken{ID: "mac:112233445566", Model: "xb7"})
+
+	suite.Run("SameRegistry", func() {
+		t, err := suite.newDecoder(WithRegistry(r)).Decode(envelope)
+		suite.Require().NoError(err)
+		suite.Equal(deviceToken{ID: "mac:112233445566", Model: "xb7"}, t)
+	})
+
+	suite.Run("UnknownCodec", func() {
+		t, err := suite.newDecoder().Decode(envelope)
+		suite.Nil(t)
+		suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+		suite.ErrorIs(err, ErrUnknownCodec)
+	})
+}
+
+func (suite *EnvelopeTestSuite) TestMultiToken() {
+	envelope := suite.encode(suite.newEncoder(), bascule.JoinTokens(
+		attributesToken{principal: "joe", capabilities: []string{"doc:read"}},
+		wrapperToken{
+			principal: "device",
+			wrapped:   attributesToken{principal: "inner", capabilities: []string{"mail:send"}},
+		},
+	))
+
+	t, err := suite.newDecoder().Decode(envelope)
+	suite.Require().NoError(err)
+	suite.Require().IsType(bascule.MultiToken{}, t)
+	suite.Equal("joe", t.Principal())
+
+	children := bascule.UnwrapToken(t)
+	suite.Require().Len(children, 2)
+	suite.Equal("joe", children[0].Principal())
+	suite.Equal("device", children[1].Principal())
+
+	grandchildren := bascule.UnwrapToken(children[1])
+	suite.Require().Len(grandchildren, 1)
+	suite.Equal("inner", grandchildren[0].Principal())
+
+	// TokenAs finds the first token in the tree with capabilities
+	var ca bascule.CapabilitiesAccessor
+	suite.True(bascule.TokenAs(grandchildren[0], &ca))
+	suite.Equal([]string{"mail:send"}, ca.Capabilities())
+}
+
+func (suite *EnvelopeTestSuite) TestOwnAccessors() {
+	envelope := suite.encode(suite.newEncoder(), wrapperToken{
+		principal: "device",
+		wrapped:   attributesToken{principal: "inner", capabilities: []string{"mail:send"}},
+	})
+
+	encoded, _, _ := strings.Cut(envelope, ".")
+	data, err := encoding.DecodeString(encoded)
+	suite.Require().NoError(err)
+
+	var p payload
+	suite.Require().NoError(json.Unmarshal(data, &p))
+	suite.JSONEq(`{"principal": "device"}`, string(p.Token.Data))
+	suite.Require().Len(p.Token.Children, 1)
+	suite.JSONEq(`{"principal": "inner", "capabilities": ["mail:send"]}`, string(p.Token.Children[0].Data))
+}
+
+func (suite *EnvelopeTestSuite) TestDelegationToken() {
+	var (
+		actors = []bascule.Actor{
+			{Subject: "support-tool", Issuer: "https://issuer.example.com"},
+			{Subject: "admin"},
+		}
+
+		subject = attributesToken{principal: "customer", capabilities: []string{"doc:read"}}
+	)
+
+	envelope := suite.encode(suite.newEncoder(), bascule.NewDelegationToken(subject, actors...))
+	t, err := suite.newDecoder().Decode(envelope)
+	suite.Require().NoError(err)
+	suite.Require().IsType((*bascule.DelegationToken)(nil), t)
+	suite.Equal("customer", t.Principal())
+
+	decoded, ok := bascule.GetActors(t)
+	suite.True(ok)
+	suite.Equal(actors, decoded)
+
+	var ca bascule.CapabilitiesAccessor
+	suite.Require().True(bascule.TokenAs(t, &ca))
+	suite.Equal([]string{"doc:read"}, ca.Capabilities())
+
+	suite.Run("Malformed", func() {
+		d := suite.newDecoder()
+		for _, token := range []map[string]any{
+			{"type": delegationCodecName, "data": []any{}, "children": []any{map[string]any{"type": GenericCodecName, "data": map[string]any{"principal": "customer"}}}},
+			{"type": delegationCodecName, "data": []any{map[string]any{"sub": "admin"}}},
+			{"type": delegationCodecName, "data": "bad", "children": []any{map[string]any{"type": GenericCodecName, "data": map[string]any{"principal": "customer"}}}},
+		} {
+			t, err := d.Decode(suite.resign(envelope, func(p map[string]any) { p["token"] = token }))
+			suite.Nil(t)
+			suite.ErrorIs(err, ErrMalformedEnvelope)
+		}
+	})
+}
+
+func (suite *EnvelopeTestSuite) TestMaxDepth() {
+	t := bascule.Token(attributesToken{principal: "leaf"})
+	for i := 0; i < 3; i++ {
+		t = wrapperToken{principal: "wrapper", wrapped: t}
+	}
+
+	suite.Run("Encode", func() {
+		envelope, err := suite.newEncoder(WithMaxDepth(3)).Encode(t)
+		suite.Empty(envelope)
+		suite.ErrorIs(err, ErrTreeTooDeep)
+	})
+
+	suite.Run("Decode", func() {
+		envelope := suite.encode(suite.newEncoder(), t)
+		decoded, err := suite.newDecoder(WithMaxDepth(3)).Decode(envelope)
+		suite.Nil(decoded)
+		suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+		suite.ErrorIs(err, ErrTreeTooDeep)
+	})
+}
+
+func (suite *EnvelopeTestSuite) TestEd25519() {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	signer, err := NewEd25519Signer(private)
+	suite.Require().NoError(err)
+	verifier, err := NewEd25519Verifier(public)
+	suite.Require().NoError(err)
+
+	e, err := NewEncoder(signer)
+	suite.Require().NoError(err)
+	d, err := NewDecoder(verifier)
+	suite.Require().NoError(err)
+
+	envelope := suite.encode(e, attributesToken{principal: "joe"})
+	t, err := d.Decode(envelope)
+	suite.Require().NoError(err)
+	suite.Equal("joe", t.Principal())
+
+	suite.Run("AlgorithmMismatch", func() {
+		t, err := suite.newDecoder().Decode(envelope)
+		suite.Nil(t)
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.ErrorIs(err, ErrInvalidSignature)
+	})
+}
+
+func (suite *EnvelopeTestSuite) TestTampered() {
+	envelope := suite.encode(suite.newEncoder(), attributesToken{principal: "joe"})
+	encoded, signature, _ := strings.Cut(envelope, ".")
+
+	data, err := encoding.DecodeString(encoded)
+	suite.Require().NoError(err)
+	data = []byte(strings.Replace(string(data), "joe", "bob", 1))
+
+	t, err := suite.newDecoder().Decode(encoding.EncodeToString(data) + "." + signature)
+	suite.Nil(t)
+	suite.ErrorIs(err, bascule.ErrBadCredentials)
+	suite.ErrorIs(err, ErrInvalidSignature)
+}
+
+func (suite *EnvelopeTestSuite) TestVerifiedBeforeUnmarshal() {
+	valid := suite.encode(suite.newEncoder(), attributesToken{principal: "joe"})
+	_, signature, _ := strings.Cut(valid, ".")
+
+	t, err := suite.newDecoder().Decode(encoding.EncodeToString([]byte("{")) + "." + signature)
+	suite.Nil(t)
+	suite.ErrorIs(err, bascule.ErrBadCredentials)
+	suite.ErrorIs(err, ErrInvalidSignature)
+	suite.NotErrorIs(err, ErrMalformedEnvelope)
+}
+
+func (suite *EnvelopeTestSuite) TestExpiration() {
+	envelope := suite.encode(suite.newEncoder(WithTTL(time.Minute)), attributesToken{principal: "joe"})
+	d := suite.newDecoder()
+
+	suite.now = suite.now.Add(59 * time.Second)
+	t, err := d.Decode(envelope)
+	suite.NoError(err)
+	suite.NotNil(t)
+
+	suite.now = suite.now.Add(time.Second)
+	t, err = d.Decode(envelope)
+	suite.Nil(t)
+	suite.ErrorIs(err, bascule.ErrTokenExpired)
+
+	suite.Run("NoExpiry", func() {
+		envelope := suite.encode(suite.newEncoder(WithTTL(0)), attributesToken{principal: "joe"})
+		suite.now = suite.now.Add(24 * time.Hour)
+		t, err := d.Decode(envelope)
+		suite.NoError(err)
+		suite.NotNil(t)
+	})
+}
+
+func (suite *EnvelopeTestSuite) TestMalformed() {
+	valid := suite.encode(suite.newEncoder(), attributesToken{principal: "joe"})
+	encoded, signature, _ := strings.Cut(valid, ".")
+
+	testCases := []struct {
+		name     string
+		envelope string
+	}{
+		{name: "Empty", envelope: ""},
+		{name: "NoSignature", envelope: encoded},
+		{name: "EmptySignature", envelope: encoded + "."},
+		{name: "EmptyPayload", envelope: "." + signature},
+		{name: "BadSignatureEncoding", envelope: encoded + ".!!!"},
+		{name: "BadPayloadEncoding", envelope: "!!!." + signature},
+		{name: "BadJSON", envelope: suite.sign([]byte("{"))},
+		{
+			name: "WrongVersion",
+			envelope: suite.resign(valid, func(p map[string]any) {
+				p["v"] = Version + 1
+			}),
+		},
+		{
+			name: "EmptyMultiToken",
+			envelope: suite.resign(valid, func(p map[string]any) {
+				p["token"] = map[string]any{"type": multiCodecName}
+			}),
+		},
+		{
+			name: "BadTokenData",
+			envelope: suite.resign(valid, func(p map[string]any) {
+				p["token"] = map[string]any{"type": GenericCodecName, "data": []int{1}}
+			}),
+		},
+	}
+
+	d := suite.newDecoder()
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			t, err := d.Decode(testCase.envelope)
+			suite.Nil(t)
+			suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+			suite.ErrorIs(err, ErrMalformedEnvelope)
+		})
+	}
+}
+
+func (suite *EnvelopeTestSuite) TestTokenParser() {
+	envelope := suite.encode(suite.newEncoder(), attributesToken{principal: "joe"})
+
+	var tps bascule.TokenParsers[string]
+	tps = tps.Append(suite.newDecoder())
+
+	t, err := tps.Parse(context.Background(), envelope)
+	suite.Require().NoError(err)
+	suite.Equal("joe", t.Principal())
+}
+
+func TestEnvelope(t *testing.T) {
+	suite.Run(t, new(EnvelopeTestSuite))
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+)
+
+const (
+	// AlgorithmHS256 identifies envelopes signed with HMAC-SHA256.
+	AlgorithmHS256 = "HS256"
+
+	// AlgorithmEdDSA identifies envelopes signed with Ed25519.
+	AlgorithmEdDSA = "EdDSA"
+
+	// MinHMACKeySize is the minimum size, in bytes, of an HMAC key.
+	MinHMACKeySize = sha256.Size
+)
+
+var (
+	// ErrInvalidKey indicates that a signing or verification key was unusable,
+	// e.g. an HMAC key that was too short.
+	ErrInvalidKey = errors.New("invalid envelope key")
+
+	// ErrInvalidSignature indicates that an envelope's signature did not verify.
+	ErrInvalidSignature = errors.New("invalid envelope signature")
+)
+
+// Signer produces envelope signatures.
+type Signer interface {
+	// Algorithm returns the algorithm identifier written into each envelope.
+	Algorithm() string
+
+	// Sign returns the signature of the given data.
+	Sign(data []byte) ([]byte, error)
+}
+
+// Verifier checks envelope signatures.
+type Verifier interface {
+	// Algorithm returns the algorithm identifier this Verifier accepts.  Envelopes
+	// declaring any other algorithm are rejected before verification.
+	Algorithm() string
+
+	// Verify checks the signature of the given data.  If the signature does not
+	// verify, this method must return an error.
+	Verify(data, signature []byte) error
+}
+
+// HMAC is both a Signer and a Verifier that uses HMAC-SHA256 with a shared secret.
+type HMAC struct {
+	key []byte
+}
+
+var (
+	_ Signer   = (*HMAC)(nil)
+	_ Verifier = (*HMAC)(nil)
+)
+
+// NewHMAC creates an HMAC Signer and Verifier.  The key must be at least
+// MinHMACKeySize bytes.  The key is copied.
+func NewHMAC(key []byte) (*HMAC, error) {
+	if len(key) < MinHMACKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return &HMAC{
+		key: append([]byte(nil), key...),
+	}, nil
+}
+
+// Algorithm returns AlgorithmHS256.
+func (h *HMAC) Algorithm() string { return AlgorithmHS256 }
+
+// Sign computes the HMAC-SHA256 of the data.
+func (h *HMAC) Sign(data []byte) ([]byte, error) {
+	m := hmac.New(sha256.New, h.key)
+	m.Write(data)
+	return m.Sum(nil), nil
+}
+
+// Verify checks the HMAC-SHA256 of the data in constant time.
+func (h *HMAC) Verify(data, signature []byte) error {
+	expected, _ := h.Sign(data)
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Ed25519Signer signs envelopes with an Ed25519 private key.
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+var _ Signer = (*Ed25519Signer)(nil)
+
+// NewEd25519Signer creates a Signer from an Ed25519 private key.
+func NewEd25519Signer(key ed25519.PrivateKey) (*Ed25519Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return &Ed25519Signer{
+		key: key,
+	}, nil
+}
+
+// Algorithm returns AlgorithmEdDSA.
+func (s *Ed25519Signer) Algorithm() string { return AlgorithmEdDSA }
+
+// Sign computes the Ed25519 signature of the data.
+func (s *Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, data), nil
+}
+
+// Ed25519Verifier verifies envelopes with an Ed25519 public key.
+type Ed25519Verifier struct {
+	key ed25519.PublicKey
+}
+
+var _ Verifier = (*Ed25519Verifier)(nil)
+
+// NewEd25519Verifier creates a Verifier from an Ed25519 public key.
+func NewEd25519Verifier(key ed25519.PublicKey) (*Ed25519Verifier, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return &Ed25519Verifier{
+		key: key,
+	}, nil
+}
+
+// Algorithm returns AlgorithmEdDSA.
+func (v *Ed25519Verifier) Algorithm() string { return AlgorithmEdDSA }
+
+// Verify checks the Ed25519 signature of the data.
+func (v *Ed25519Verifier) Verify(data, signature []byte) error {
+	if !ed25519.Verify(v.key, data, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SignerTestSuite struct {
+	TestSuite
+}
+
+func (suite *SignerTestSuite) TestHMAC() {
+	suite.Run("ShortKey", func() {
+		h, err := NewHMAC(make([]byte, MinHMACKeySize-1))
+		suite.Nil(h)
+		suite.ErrorIs(err, ErrInvalidKey)
+	})
+
+	suite.Run("SignAndVerify", func() {
+		h := suite.newHMAC()
+		suite.Equal(AlgorithmHS256, h.Algorithm())
+
+		signature, err := h.Sign([]byte("data"))
+		suite.Require().NoError(err)
+		suite.NoError(h.Verify([]byte("data"), signature))
+		suite.ErrorIs(h.Verify([]byte("other"), signature), ErrInvalidSignature)
+	})
+
+	suite.Run("DifferentKey", func() {
+		signature, err := suite.newHMAC().Sign([]byte("data"))
+		suite.Require().NoError(err)
+
+		key := suite.hmacKey()
+		key[0]++
+		other, err := NewHMAC(key)
+		suite.Require().NoError(err)
+		suite.ErrorIs(other.Verify([]byte("data"), signature), ErrInvalidSignature)
+	})
+}
+
+func (suite *SignerTestSuite) TestEd25519() {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	suite.Run("InvalidKeys", func() {
+		s, err := NewEd25519Signer(private[:10])
+		suite.Nil(s)
+		suite.ErrorIs(err, ErrInvalidKey)
+
+		v, err := NewEd25519Verifier(public[:10])
+		suite.Nil(v)
+		suite.ErrorIs(err, ErrInvalidKey)
+	})
+
+	suite.Run("SignAndVerify", func() {
+		s, err := NewEd25519Signer(private)
+		suite.Require().NoError(err)
+		v, err := NewEd25519Verifier(public)
+		suite.Require().NoError(err)
+		suite.Equal(AlgorithmEdDSA, s.Algorithm())
+		suite.Equal(AlgorithmEdDSA, v.Algorithm())
+
+		signature, err := s.Sign([]byte("data"))
+		suite.Require().NoError(err)
+		suite.NoError(v.Verify([]byte("data"), signature))
+		suite.ErrorIs(v.Verify([]byte("other"), signature), ErrInvalidSignature)
+	})
+}
+
+func TestSigner(t *testing.T) {
+	suite.Run(t, new(SignerTestSuite))
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeenvelope
+
+import (
+	"bytes"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// attributesToken is a token that exposes all its attributes via AttributesMap.
+type attributesToken struct {
+	principal    string
+	capabilities []string
+	attributes   map[string]any
+}
+
+func (t attributesToken) Principal() string          { return t.principal }
+func (t attributesToken) Capabilities() []string     { return t.capabilities }
+func (t attributesToken) Attributes() map[string]any { return t.attributes }
+
+func (t attributesToken) Get(key string) (v any, ok bool) {
+	v, ok = t.attributes[key]
+	return
+}
+
+// accessorToken has attributes that can only be fetched by key.
+type accessorToken struct {
+	principal  string
+	attributes map[string]any
+}
+
+func (t accessorToken) Principal() string { return t.principal }
+
+func (t accessorToken) Get(key string) (v any, ok bool) {
+	v, ok = t.attributes[key]
+	return
+}
+
+// deviceToken is marshaled directly with encoding/json.
+type deviceToken struct {
+	ID    string `json:"id"`
+	Model string `json:"model"`
+}
+
+func (t deviceToken) Principal() string { return t.ID }
+
+// TestSuite supplies keys and signers for envelope tests.
+type TestSuite struct {
+	suite.Suite
+}
+
+func (suite *TestSuite) hmacKey() []byte {
+	return bytes.Repeat([]byte{0x5A}, MinHMACKeySize)
+}
+
+func (suite *TestSuite) newHMAC() *HMAC {
+	h, err := NewHMAC(suite.hmacKey())
+	suite.Require().NoError(err)
+	suite.Require().NotNil(h)
+	return h
+}
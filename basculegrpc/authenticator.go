@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"github.com/xmidt-org/bascule"
+)
+
+// NewAuthenticator is a convenient wrapper around bascule.NewAuthenticator.
+// This function eases the syntactical pain of generics when creating an Interceptor.
+func NewAuthenticator(opts ...bascule.AuthenticatorOption[*Call]) (*bascule.Authenticator[*Call], error) {
+	return bascule.NewAuthenticator(opts...)
+}
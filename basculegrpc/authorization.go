@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+const (
+	// DefaultAuthorizationKey is the default metadata key used for authorization
+	// tokens.  gRPC metadata keys are always lowercase.
+	DefaultAuthorizationKey = "authorization"
+)
+
+// lower returns a lowercased version of a scheme, for case-insensitive matches.
+func lower(s basculehttp.Scheme) basculehttp.Scheme {
+	return basculehttp.Scheme(strings.ToLower(string(s)))
+}
+
+// AuthorizationParserOption is a configurable option for an AuthorizationParser.
+type AuthorizationParserOption interface {
+	apply(*AuthorizationParser) error
+}
+
+type authorizationParserOptionFunc func(*AuthorizationParser) error
+
+func (apof authorizationParserOptionFunc) apply(ap *AuthorizationParser) error { return apof(ap) }
+
+// WithAuthorizationKey changes the metadata key holding the token.  By default,
+// the key used is DefaultAuthorizationKey.
+func WithAuthorizationKey(key string) AuthorizationParserOption {
+	return authorizationParserOptionFunc(func(ap *AuthorizationParser) error {
+		ap.key = key
+		return nil
+	})
+}
+
+// WithScheme registers a string-based token parser that handles a
+// specific authorization scheme.  Invocations to this option are cumulative
+// and will overwrite any existing registration.  Schemes are case-insensitive.
+func WithScheme(scheme basculehttp.Scheme, parser bascule.TokenParser[string]) AuthorizationParserOption {
+	return authorizationParserOptionFunc(func(ap *AuthorizationParser) error {
+		// we want case-insensitive matches, so lowercase everything
+		ap.parsers[lower(scheme)] = parser
+		return nil
+	})
+}
+
+// WithBasic is a shorthand for WithScheme that registers basic token parsing using
+// the default scheme.
+func WithBasic() AuthorizationParserOption {
+	return WithScheme(basculehttp.SchemeBasic, basculehttp.BasicTokenParser{})
+}
+
+// AuthorizationParser is a bascule.TokenParser that handles authorization metadata.
+// The metadata value uses the same format as the HTTP Authorization header, i.e.
+// <scheme><single space><credential value>.
+type AuthorizationParser struct {
+	key     string
+	parsers map[basculehttp.Scheme]bascule.TokenParser[string]
+}
+
+var _ bascule.TokenParser[*Call] = (*AuthorizationParser)(nil)
+
+// NewAuthorizationParser constructs an AuthorizationParser from a set
+// of configuration options.
+func NewAuthorizationParser(opts ...AuthorizationParserOption) (*AuthorizationParser, error) {
+	ap := &AuthorizationParser{
+		parsers: make(map[basculehttp.Scheme]bascule.TokenParser[string]),
+	}
+
+	for _, o := range opts {
+		if err := o.apply(ap); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ap.key) == 0 {
+		ap.key = DefaultAuthorizationKey
+	}
+
+	return ap, nil
+}
+
+// Parse extracts the authorization metadata and parses the scheme and value.
+//
+// If no authorization metadata is present, this method returns bascule.ErrMissingCredentials.
+// If the value is not in the correct format, bascule.ErrInvalidCredentials is returned.
+//
+// If a token parser is registered for the given scheme, that token parser is invoked.
+// Otherwise, *basculehttp.UnsupportedSchemeError is returned.  Both that error and any
+// error from the scheme's token parser are wrapped in a *bascule.AuthError that
+// carries the scheme.
+func (ap *AuthorizationParser) Parse(ctx context.Context, source *Call) (bascule.Token, error) {
+	authValue, ok := source.Get(ap.key)
+	if !ok || len(authValue) == 0 {
+		return nil, bascule.ErrMissingCredentials
+	}
+
+	scheme, value, err := basculehttp.ParseAuthorization(authValue)
+	if err != nil {
+		return nil, bascule.ErrInvalidCredentials
+	}
+
+	p, registered := ap.parsers[lower(scheme)]
+	if !registered {
+		return nil, &bascule.AuthError{
+			Scheme: string(scheme),
+			Err: &basculehttp.UnsupportedSchemeError{
+				Scheme: scheme,
+			},
+		}
+	}
+
+	t, err := p.Parse(ctx, value)
+	if err != nil {
+		err = &bascule.AuthError{
+			Scheme: string(scheme),
+			Err:    err,
+		}
+	}
+
+	return t, err
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+type AuthorizationTestSuite struct {
+	TestSuite
+}
+
+func (suite *AuthorizationTestSuite) TestBasicAuthSuccess() {
+	suite.Run("DefaultKey", func() {
+		ap := suite.newAuthorizationParser(WithBasic())
+		token, err := ap.Parse(context.Background(), suite.newCall(DefaultAuthorizationKey, suite.basicAuth()))
+		suite.Require().NoError(err)
+		suite.assertBasicToken(token)
+	})
+
+	suite.Run("CustomKey", func() {
+		ap := suite.newAuthorizationParser(
+			WithAuthorizationKey("x-custom"),
+			WithScheme("BASIC", basculehttp.BasicTokenParser{}),
+		)
+
+		token, err := ap.Parse(context.Background(), suite.newCall("x-custom", suite.basicAuth()))
+		suite.Require().NoError(err)
+		suite.assertBasicToken(token)
+	})
+}
+
+func (suite *AuthorizationTestSuite) TestMissingCredentials() {
+	ap := suite.newAuthorizationParser(WithBasic())
+	token, err := ap.Parse(context.Background(), suite.newCall())
+	suite.Nil(token)
+	suite.ErrorIs(err, bascule.ErrMissingCredentials)
+
+	token, err = ap.Parse(context.Background(), suite.newCall(DefaultAuthorizationKey, ""))
+	suite.Nil(token)
+	suite.ErrorIs(err, bascule.ErrMissingCredentials)
+}
+
+func (suite *AuthorizationTestSuite) TestInvalidCredentials() {
+	ap := suite.newAuthorizationParser(WithBasic())
+	token, err := ap.Parse(context.Background(), suite.newCall(DefaultAuthorizationKey, "Basic  "))
+	suite.Nil(token)
+	suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+}
+
+func (suite *AuthorizationTestSuite) TestUnsupportedScheme() {
+	ap := suite.newAuthorizationParser(WithBasic())
+	token, err := ap.Parse(context.Background(), suite.newCall(DefaultAuthorizationKey, "Unsupported xyz"))
+	suite.Nil(token)
+
+	var use *basculehttp.UnsupportedSchemeError
+	suite.Require().ErrorAs(err, &use)
+	suite.Equal(basculehttp.Scheme("Unsupported"), use.Scheme)
+	var ae *bascule.AuthError
+	suite.Require().ErrorAs(err, &ae)
+	suite.Equal("Unsupported", ae.Scheme)
+}
+
+func (suite *AuthorizationTestSuite) TestOptionError() {
+	expectedErr := errors.New("expected")
+	ap, err := NewAuthorizationParser(authorizationParserOptionFunc(func(*AuthorizationParser) error {
+		return expectedErr
+	}))
+
+	suite.ErrorIs(err, expectedErr)
+	suite.Nil(ap)
+}
+
+func TestAuthorization(t *testing.T) {
+	suite.Run(t, new(AuthorizationTestSuite))
+}
@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"github.com/xmidt-org/bascule"
+)
+
+// NewAuthorizer is a convenient wrapper around bascule.NewAuthorizer.
+// This function eases the syntactical pain of generics when creating an Interceptor.
+func NewAuthorizer(opts ...bascule.AuthorizerOption[*Call]) (*bascule.Authorizer[*Call], error) {
+	return bascule.NewAuthorizer(opts...)
+}
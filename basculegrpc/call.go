@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Call describes a single gRPC invocation.  A *Call is both the source passed to
+// an Authenticator and the resource passed to an Authorizer.
+type Call struct {
+	// FullMethod is the full RPC method name, e.g. /package.Service/Method.
+	FullMethod string
+
+	// Metadata is the incoming metadata for the call.  This field is never nil.
+	Metadata metadata.MD
+
+	// Peer is the remote peer, if known.
+	Peer *peer.Peer
+
+	// Request is the request message for unary calls.  For streaming calls,
+	// this field is nil.
+	Request any
+
+	// IsClientStream is true if the client can send more than one message.
+	IsClientStream bool
+
+	// IsServerStream is true if the server can send more than one message.
+	IsServerStream bool
+}
+
+// Get returns the first metadata value for a key.  Keys are case-insensitive.
+func (c *Call) Get(key string) (v string, ok bool) {
+	if values := c.Metadata.Get(key); len(values) > 0 {
+		v, ok = values[0], true
+	}
+
+	return
+}
+
+// newCall creates a Call from the context of an incoming RPC.
+func newCall(ctx context.Context, fullMethod string) *Call {
+	c := &Call{
+		FullMethod: fullMethod,
+	}
+
+	c.Metadata, _ = metadata.FromIncomingContext(ctx)
+	if c.Metadata == nil {
+		c.Metadata = metadata.MD{}
+	}
+
+	c.Peer, _ = peer.FromContext(ctx)
+	return c
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type CallTestSuite struct {
+	TestSuite
+}
+
+func (suite *CallTestSuite) TestGet() {
+	c := suite.newCall("Key", "value1", "key", "value2")
+	v, ok := c.Get("KEY")
+	suite.True(ok)
+	suite.Equal("value1", v)
+
+	v, ok = c.Get("missing")
+	suite.False(ok)
+	suite.Empty(v)
+}
+
+func (suite *CallTestSuite) TestNewCall() {
+	suite.Run("Empty", func() {
+		c := newCall(context.Background(), expectedMethod)
+		suite.Equal(expectedMethod, c.FullMethod)
+		suite.NotNil(c.Metadata)
+		suite.Nil(c.Peer)
+	})
+
+	suite.Run("Full", func() {
+		p := &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234},
+		}
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("key", "value"))
+		ctx = peer.NewContext(ctx, p)
+
+		c := newCall(ctx, expectedMethod)
+		suite.Equal(expectedMethod, c.FullMethod)
+		suite.Equal([]string{"value"}, c.Metadata.Get("key"))
+		suite.Same(p, c.Peer)
+	})
+}
+
+func TestCall(t *testing.T) {
+	suite.Run(t, new(CallTestSuite))
+}
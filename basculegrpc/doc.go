@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculegrpc provides gRPC server interceptors that implement the bascule
+authentication and authorization workflows.
+
+The source and resource type for gRPC is *Call, which describes the method being
+invoked along with its incoming metadata.  An AuthorizationParser handles the
+"authorization" metadata key using the same scheme dispatch as basculehttp.
+Tokens are placed into the handler's context via bascule.WithToken.
+*/
+package basculegrpc
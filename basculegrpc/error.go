@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"errors"
+
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCoder is a strategy for determining the gRPC status code for an error.
+//
+// If this closure returns codes.OK, the caller should supply a useful default.
+type ErrorCoder func(call *Call, err error) codes.Code
+
+// DefaultErrorCoder is the strategy used when no ErrorCoder is supplied.
+// The following tests are done in order:
+//
+// (1) First, if err is nil, this method returns codes.OK.
+//
+// (2) If any error in the chain provides a 'GRPCStatus() *status.Status' method,
+// the code from that status is returned.
+//
+// (3) If err has bascule.ErrPanic in its chain, this function returns codes.Internal.
+//
+// (4) If err has bascule.ErrLockedOut in its chain, this function returns
+// codes.ResourceExhausted.
+//
+// (5) If err has bascule.ErrMissingCredentials, bascule.ErrBadCredentials,
+// bascule.ErrInvalidCredentials, bascule.ErrTokenExpired, bascule.ErrTokenNotYetValid,
+// or bascule.ErrRevokedCredentials in its chain, this function returns codes.Unauthenticated.
+//
+// (6) If err has a *basculehttp.UnsupportedSchemeError in its chain, this function
+// returns codes.Unauthenticated.
+//
+// (7) If err has bascule.ErrUnauthorized in its chain, this function returns
+// codes.PermissionDenied.
+//
+// (8) Otherwise, this method returns codes.OK to indicate that it doesn't know how to
+// produce a code from the error.
+func DefaultErrorCoder(_ *Call, err error) codes.Code {
+	type grpcStatuser interface {
+		GRPCStatus() *status.Status
+	}
+
+	var (
+		gs  grpcStatuser
+		use *basculehttp.UnsupportedSchemeError
+	)
+
+	switch {
+	case err == nil:
+		return codes.OK
+
+	case errors.As(err, &gs):
+		return gs.GRPCStatus().Code()
+
+	case errors.Is(err, bascule.ErrPanic):
+		return codes.Internal
+
+	case errors.Is(err, bascule.ErrLockedOut):
+		return codes.ResourceExhausted
+
+	case errors.Is(err, bascule.ErrMissingCredentials):
+		return codes.Unauthenticated
+
+	case errors.Is(err, bascule.ErrBadCredentials):
+		return codes.Unauthenticated
+
+	case errors.Is(err, bascule.ErrInvalidCredentials):
+		return codes.Unauthenticated
+
+	case errors.Is(err, bascule.ErrTokenExpired):
+		return codes.Unauthenticated
+
+	case errors.Is(err, bascule.ErrTokenNotYetValid):
+		return codes.Unauthenticated
+
+	case errors.Is(err, bascule.ErrRevokedCredentials):
+		return codes.Unauthenticated
+
+	case errors.As(err, &use):
+		return codes.Unauthenticated
+
+	case errors.Is(err, bascule.ErrUnauthorized):
+		return codes.PermissionDenied
+
+	default:
+		return codes.OK
+	}
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ErrorTestSuite struct {
+	TestSuite
+}
+
+func (suite *ErrorTestSuite) TestDefaultErrorCoder() {
+	testCases := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{err: nil, expected: codes.OK},
+		{err: status.Error(codes.ResourceExhausted, "expected"), expected: codes.ResourceExhausted},
+		{err: &bascule.LockoutError{RetryAfter: time.Minute}, expected: codes.ResourceExhausted},
+		{err: &bascule.PanicError{Value: bascule.ErrBadCredentials}, expected: codes.Internal},
+		{err: bascule.ErrMissingCredentials, expected: codes.Unauthenticated},
+		{err: bascule.ErrBadCredentials, expected: codes.Unauthenticated},
+		{err: bascule.ErrInvalidCredentials, expected: codes.Unauthenticated},
+		{err: bascule.ErrTokenExpired, expected: codes.Unauthenticated},
+		{err: bascule.ErrTokenNotYetValid, expected: codes.Unauthenticated},
+		{err: bascule.ErrRevokedCredentials, expected: codes.Unauthenticated},
+		{err: &basculehttp.UnsupportedSchemeError{Scheme: "Custom"}, expected: codes.Unauthenticated},
+		{err: bascule.ErrUnauthorized, expected: codes.PermissionDenied},
+		{err: fmt.Errorf("wrapped: %w", bascule.ErrUnauthorized), expected: codes.PermissionDenied},
+		{err: errors.New("unrecognized"), expected: codes.OK},
+	}
+
+	for i, testCase := range testCases {
+		suite.Run(fmt.Sprintf("case-%d", i), func() {
+			suite.Equal(testCase.expected, DefaultErrorCoder(suite.newCall(), testCase.err))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	suite.Run(t, new(ErrorTestSuite))
+}
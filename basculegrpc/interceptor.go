@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/xmidt-org/bascule"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrNoAuthenticator is returned by NewInterceptor to indicate that an Authorizer
+	// was configured without an Authenticator.
+	ErrNoAuthenticator = errors.New("An Authenticator is required if an Authorizer is configured")
+)
+
+// InterceptorOption is a functional option for tailoring an Interceptor.
+type InterceptorOption interface {
+	apply(*Interceptor) error
+}
+
+type interceptorOptionFunc func(*Interceptor) error
+
+func (iof interceptorOptionFunc) apply(i *Interceptor) error {
+	return iof(i)
+}
+
+// WithAuthenticator supplies the Authenticator workflow for the interceptor.
+func WithAuthenticator(authenticator *bascule.Authenticator[*Call]) InterceptorOption {
+	return UseAuthenticator(authenticator, nil)
+}
+
+// UseAuthenticator is a variant of WithAuthenticator that allows a caller to
+// nest function calls a little easier.  The output of NewAuthenticator
+// can be passed directly to this option.
+func UseAuthenticator(authenticator *bascule.Authenticator[*Call], err error) InterceptorOption {
+	return interceptorOptionFunc(func(i *Interceptor) error {
+		if err != nil {
+			return err
+		}
+
+		i.authenticator = authenticator
+		return nil
+	})
+}
+
+// WithAuthorizer supplies the Authorizer workflow for the interceptor.
+//
+// The Authorizer is optional.  If no authorizer is supplied, then no authorization
+// takes place and no authorization events are fired.
+func WithAuthorizer(authorizer *bascule.Authorizer[*Call]) InterceptorOption {
+	return UseAuthorizer(authorizer, nil)
+}
+
+// UseAuthorizer is a variant of WithAuthorizer that allows a caller to
+// nest function calls a little easier.  The output of NewAuthorizer
+// can be passed directly to this option.
+func UseAuthorizer(authorizer *bascule.Authorizer[*Call], err error) InterceptorOption {
+	return interceptorOptionFunc(func(i *Interceptor) error {
+		if err != nil {
+			return err
+		}
+
+		i.authorizer = authorizer
+		return nil
+	})
+}
+
+// WithErrorCoder sets the strategy used to map workflow errors onto gRPC status codes.
+// If this option is omitted or if ec is nil, DefaultErrorCoder is used.
+func WithErrorCoder(ec ErrorCoder) InterceptorOption {
+	return interceptorOptionFunc(func(i *Interceptor) error {
+		i.errorCoder = ec
+		return nil
+	})
+}
+
+// WithLogger sets the logger used to record the full text of workflow errors.  Clients
+// only ever receive the message given by bascule.SafeMessage, so this logger is the only
+// place internal error details, such as parser failures, appear.  If this option is
+// omitted or if l is nil, slog.Default() is used.
+func WithLogger(l *slog.Logger) InterceptorOption {
+	return interceptorOptionFunc(func(i *Interceptor) error {
+		i.logger = l
+		return nil
+	})
+}
+
+// Interceptor is an immutable gRPC workflow that produces server interceptors.
+//
+// As with basculehttp.Middleware, an Interceptor can have either or both of an
+// Authenticator and an Authorizer.  An Authorizer without an Authenticator is
+// an error.  If neither is supplied, the interceptors pass all calls through as is.
+type Interceptor struct {
+	authenticator *bascule.Authenticator[*Call]
+	authorizer    *bascule.Authorizer[*Call]
+	errorCoder    ErrorCoder
+	logger        *slog.Logger
+}
+
+// NewInterceptor creates an immutable Interceptor from a supplied set of options.
+//
+// If no authenticator is configured, but an authorizer is, this function returns
+// ErrNoAuthenticator.
+func NewInterceptor(opts ...InterceptorOption) (i *Interceptor, err error) {
+	i = new(Interceptor)
+	for _, o := range opts {
+		err = multierr.Append(err, o.apply(i))
+	}
+
+	switch {
+	case err != nil:
+		i = nil
+
+	case i.authenticator == nil && i.authorizer != nil:
+		err = multierr.Append(err, ErrNoAuthenticator)
+		i = nil
+
+	default:
+		if i.errorCoder == nil {
+			i.errorCoder = DefaultErrorCoder
+		}
+
+		if i.logger == nil {
+			i.logger = slog.Default()
+		}
+	}
+
+	return
+}
+
+// Unary returns a grpc.UnaryServerInterceptor that applies this workflow to unary calls.
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if i.authenticator == nil {
+			return handler(ctx, request)
+		}
+
+		call := newCall(ctx, info.FullMethod)
+		call.Request = request
+		ctx, err := i.serve(ctx, call)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, request)
+	}
+}
+
+// Stream returns a grpc.StreamServerInterceptor that applies this workflow to streaming
+// calls.  The workflow runs once, when the stream is opened.
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.authenticator == nil {
+			return handler(srv, ss)
+		}
+
+		call := newCall(ss.Context(), info.FullMethod)
+		call.IsClientStream = info.IsClientStream
+		call.IsServerStream = info.IsServerStream
+		ctx, err := i.serve(ss.Context(), call)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+	}
+}
+
+// serve executes the bascule workflow for a call.  The returned context holds
+// the authenticated token.
+func (i *Interceptor) serve(ctx context.Context, call *Call) (context.Context, error) {
+	token, err := i.authenticator.Authenticate(ctx, call)
+	if err != nil {
+		return ctx, i.statusError(ctx, call, codes.Unauthenticated, err)
+	}
+
+	ctx = bascule.WithToken(ctx, token)
+
+	// the authorizer is optional
+	if i.authorizer != nil {
+		if err = i.authorizer.Authorize(ctx, call, token); err != nil {
+			return ctx, i.statusError(ctx, call, codes.PermissionDenied, err)
+		}
+	}
+
+	return ctx, nil
+}
+
+// statusError converts a workflow error into a gRPC status error.  The defaultCode
+// is used when the ErrorCoder cannot determine a code.  The status message is the
+// error's bascule.SafeMessage, while the full error is logged.
+func (i *Interceptor) statusError(ctx context.Context, call *Call, defaultCode codes.Code, err error) error {
+	code := i.errorCoder(call, err)
+	if code == codes.OK {
+		code = defaultCode
+	}
+
+	i.logger.LogAttrs(
+		ctx,
+		slog.LevelInfo,
+		"call rejected",
+		slog.String("method", call.FullMethod),
+		slog.String("code", code.String()),
+		slog.String("category", bascule.CategorizeError(err).String()),
+		slog.String("error", err.Error()),
+	)
+
+	return status.Error(code, bascule.SafeMessage(err))
+}
+
+// serverStream decorates a grpc.ServerStream so that handlers see the
+// context that holds the token.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
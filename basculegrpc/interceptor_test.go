@@ -0,0 +1,279 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer is a test service that records the token visible to each handler.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	tokens chan bascule.Token
+}
+
+func (hs *healthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	t, _ := bascule.Get(ctx)
+	hs.tokens <- t
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+	}, nil
+}
+
+func (hs *healthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	t, _ := bascule.GetFrom(stream)
+	hs.tokens <- t
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{
+		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+	})
+}
+
+type InterceptorTestSuite struct {
+	TestSuite
+
+	health *healthServer
+	server *grpc.Server
+	conn   *grpc.ClientConn
+}
+
+func (suite *InterceptorTestSuite) SetupTest() {
+	suite.health = &healthServer{
+		tokens: make(chan bascule.Token, 1),
+	}
+}
+
+func (suite *InterceptorTestSuite) TearDownTest() {
+	if suite.conn != nil {
+		suite.conn.Close()
+		suite.conn = nil
+	}
+
+	if suite.server != nil {
+		suite.server.Stop()
+		suite.server = nil
+	}
+}
+
+func (suite *InterceptorTestSuite) newInterceptor(opts ...InterceptorOption) *Interceptor {
+	i, err := NewInterceptor(opts...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(i)
+	return i
+}
+
+func (suite *InterceptorTestSuite) newAuthenticator() *bascule.Authenticator[*Call] {
+	a, err := NewAuthenticator(
+		bascule.WithTokenParsers(suite.newAuthorizationParser(WithBasic())),
+	)
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(a)
+	return a
+}
+
+func (suite *InterceptorTestSuite) newAuthorizer(err error) *bascule.Authorizer[*Call] {
+	a, authErr := NewAuthorizer(
+		bascule.WithApprovers(
+			bascule.ApproverFunc[*Call](func(_ context.Context, resource *Call, _ bascule.Token) error {
+				suite.NotEmpty(resource.FullMethod)
+				return err
+			}),
+		),
+	)
+
+	suite.Require().NoError(authErr)
+	suite.Require().NotNil(a)
+	return a
+}
+
+// serve starts a bufconn server protected by the given interceptor and
+// returns a client for it.
+func (suite *InterceptorTestSuite) serve(i *Interceptor) grpc_health_v1.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	suite.server = grpc.NewServer(
+		grpc.UnaryInterceptor(i.Unary()),
+		grpc.StreamInterceptor(i.Stream()),
+	)
+
+	grpc_health_v1.RegisterHealthServer(suite.server, suite.health)
+	go suite.server.Serve(listener)
+
+	var err error
+	suite.conn, err = grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	suite.Require().NoError(err)
+	return grpc_health_v1.NewHealthClient(suite.conn)
+}
+
+func (suite *InterceptorTestSuite) authorizedContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), DefaultAuthorizationKey, suite.basicAuth())
+}
+
+// check invokes the unary method and returns the resulting status code.
+func (suite *InterceptorTestSuite) check(client grpc_health_v1.HealthClient, ctx context.Context) codes.Code {
+	_, err := client.Check(ctx, new(grpc_health_v1.HealthCheckRequest))
+	return status.Code(err)
+}
+
+// watch invokes the streaming method and returns the resulting status code.
+func (suite *InterceptorTestSuite) watch(client grpc_health_v1.HealthClient, ctx context.Context) codes.Code {
+	stream, err := client.Watch(ctx, new(grpc_health_v1.HealthCheckRequest))
+	suite.Require().NoError(err)
+
+	_, err = stream.Recv()
+	return status.Code(err)
+}
+
+func (suite *InterceptorTestSuite) TestNoAuthenticator() {
+	i, err := NewInterceptor(WithAuthorizer(suite.newAuthorizer(nil)))
+	suite.ErrorIs(err, ErrNoAuthenticator)
+	suite.Nil(i)
+}
+
+func (suite *InterceptorTestSuite) TestOptionError() {
+	expectedErr := errors.New("expected")
+	i, err := NewInterceptor(
+		UseAuthenticator(nil, expectedErr),
+		UseAuthorizer(nil, expectedErr),
+	)
+
+	suite.ErrorIs(err, expectedErr)
+	suite.Nil(i)
+}
+
+func (suite *InterceptorTestSuite) TestNoop() {
+	client := suite.serve(suite.newInterceptor())
+
+	suite.Equal(codes.OK, suite.check(client, context.Background()))
+	suite.Nil(<-suite.health.tokens)
+
+	suite.Equal(codes.OK, suite.watch(client, context.Background()))
+	suite.Nil(<-suite.health.tokens)
+}
+
+func (suite *InterceptorTestSuite) TestAuthenticate() {
+	client := suite.serve(suite.newInterceptor(
+		WithAuthenticator(suite.newAuthenticator()),
+	))
+
+	suite.Run("Unary", func() {
+		suite.Equal(codes.OK, suite.check(client, suite.authorizedContext()))
+		suite.assertBasicToken(<-suite.health.tokens)
+
+		suite.Equal(codes.Unauthenticated, suite.check(client, context.Background()))
+	})
+
+	suite.Run("Stream", func() {
+		suite.Equal(codes.OK, suite.watch(client, suite.authorizedContext()))
+		suite.assertBasicToken(<-suite.health.tokens)
+
+		suite.Equal(codes.Unauthenticated, suite.watch(client, context.Background()))
+	})
+}
+
+func (suite *InterceptorTestSuite) TestAuthorize() {
+	suite.Run("Approved", func() {
+		client := suite.serve(suite.newInterceptor(
+			WithAuthenticator(suite.newAuthenticator()),
+			WithAuthorizer(suite.newAuthorizer(nil)),
+		))
+
+		suite.Equal(codes.OK, suite.check(client, suite.authorizedContext()))
+		suite.assertBasicToken(<-suite.health.tokens)
+
+		suite.Equal(codes.OK, suite.watch(client, suite.authorizedContext()))
+		suite.assertBasicToken(<-suite.health.tokens)
+		suite.TearDownTest()
+	})
+
+	suite.Run("Denied", func() {
+		client := suite.serve(suite.newInterceptor(
+			WithAuthenticator(suite.newAuthenticator()),
+			WithAuthorizer(suite.newAuthorizer(bascule.ErrUnauthorized)),
+		))
+
+		suite.Equal(codes.PermissionDenied, suite.check(client, suite.authorizedContext()))
+		suite.Equal(codes.PermissionDenied, suite.watch(client, suite.authorizedContext()))
+		suite.TearDownTest()
+	})
+
+	suite.Run("UnrecognizedError", func() {
+		client := suite.serve(suite.newInterceptor(
+			WithAuthenticator(suite.newAuthenticator()),
+			WithAuthorizer(suite.newAuthorizer(errors.New("unrecognized"))),
+		))
+
+		suite.Equal(codes.PermissionDenied, suite.check(client, suite.authorizedContext()))
+		suite.TearDownTest()
+	})
+}
+
+func (suite *InterceptorTestSuite) TestCustomErrorCoder() {
+	client := suite.serve(suite.newInterceptor(
+		WithAuthenticator(suite.newAuthenticator()),
+		WithErrorCoder(func(call *Call, err error) codes.Code {
+			suite.Equal(expectedMethod, call.FullMethod)
+			suite.ErrorIs(err, bascule.ErrMissingCredentials)
+			return codes.Unavailable
+		}),
+	))
+
+	suite.Equal(codes.Unavailable, suite.check(client, context.Background()))
+}
+
+func (suite *InterceptorTestSuite) TestSafeMessage() {
+	var (
+		logs bytes.Buffer
+
+		authenticator, err = NewAuthenticator(
+			bascule.WithTokenParsers(
+				bascule.AsTokenParser[*Call](func(*Call) (bascule.Token, error) {
+					return nil, fmt.Errorf("%w: secret internal detail", bascule.ErrBadCredentials)
+				}),
+			),
+		)
+	)
+
+	suite.Require().NoError(err)
+	client := suite.serve(suite.newInterceptor(
+		WithAuthenticator(authenticator),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	))
+
+	_, err = client.Check(context.Background(), new(grpc_health_v1.HealthCheckRequest))
+	s, ok := status.FromError(err)
+	suite.Require().True(ok)
+	suite.Equal(codes.Unauthenticated, s.Code())
+	suite.Equal("bad credentials", s.Message())
+
+	suite.Contains(logs.String(), "secret internal detail")
+	suite.Contains(logs.String(), expectedMethod)
+}
+
+func TestInterceptor(t *testing.T) {
+	suite.Run(t, new(InterceptorTestSuite))
+}
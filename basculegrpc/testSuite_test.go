@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculegrpc
+
+import (
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	expectedPrincipal = "testPrincipal"
+	expectedPassword  = "test_password"
+	expectedMethod    = "/grpc.health.v1.Health/Check"
+)
+
+// TestSuite is a common suite that exposes some useful behaviors.
+type TestSuite struct {
+	suite.Suite
+}
+
+// basicAuth produces a formatted basic authorization value, including the scheme,
+// using this suite's expectations.
+func (suite *TestSuite) basicAuth() string {
+	return string(basculehttp.SchemeBasic) + " " + basculehttp.BasicAuth(expectedPrincipal, expectedPassword)
+}
+
+// newCall creates a test Call with the given metadata key/value pairs.
+func (suite *TestSuite) newCall(kv ...string) *Call {
+	return &Call{
+		FullMethod: expectedMethod,
+		Metadata:   metadata.Pairs(kv...),
+	}
+}
+
+// assertBasicToken asserts that the token was parsed from basicAuth.
+func (suite *TestSuite) assertBasicToken(token bascule.Token) {
+	suite.Require().NotNil(token)
+	suite.Equal(expectedPrincipal, token.Principal())
+	suite.Require().Implements((*basculehttp.BasicToken)(nil), token)
+	suite.Equal(expectedPassword, token.(basculehttp.BasicToken).Password())
+}
+
+// newAuthorizationParser creates an AuthorizationParser that is expected to be valid.
+func (suite *TestSuite) newAuthorizationParser(opts ...AuthorizationParserOption) *AuthorizationParser {
+	ap, err := NewAuthorizationParser(opts...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(ap)
+	return ap
+}
@@ -0,0 +1,362 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehash
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// DefaultLockoutThreshold is the default number of consecutive failures
+	// that triggers a lockout.
+	DefaultLockoutThreshold = 5
+
+	// DefaultLockoutBaseDelay is the default duration of the first lockout.
+	DefaultLockoutBaseDelay = time.Second
+
+	// DefaultLockoutMaxDelay is the default upper bound on a lockout's duration.
+	DefaultLockoutMaxDelay = 15 * time.Minute
+
+	// DefaultLockoutWindow is the default amount of time failures are remembered.
+	DefaultLockoutWindow = 15 * time.Minute
+
+	// principalKeyPrefix and sourceKeyPrefix keep principals and source keys
+	// from colliding within a LockoutStore.
+	principalKeyPrefix = "principal:"
+	sourceKeyPrefix    = "source:"
+)
+
+var (
+	// ErrInvalidLockoutConfig indicates that a lockout option was given an invalid value.
+	ErrInvalidLockoutConfig = errors.New("invalid lockout configuration")
+)
+
+// LockoutOption is a configurable option for a Lockout.
+type LockoutOption interface {
+	apply(*Lockout) error
+}
+
+type lockoutOptionFunc func(*Lockout) error
+
+func (lof lockoutOptionFunc) apply(l *Lockout) error { return lof(l) }
+
+// WithLockoutStore sets the store used to track failures.  If this option is not
+// supplied, a new MemoryLockoutStore with DefaultMemoryLockoutStoreMaxSize is used.
+func WithLockoutStore(s LockoutStore) LockoutOption {
+	return lockoutOptionFunc(func(l *Lockout) error {
+		if s == nil {
+			return ErrInvalidLockoutConfig
+		}
+
+		l.store = s
+		return nil
+	})
+}
+
+// WithLockoutThreshold sets the number of consecutive failures that triggers a
+// lockout.  A threshold of 1 applies backoff after every failure.  If this option
+// is not supplied, DefaultLockoutThreshold is used.
+func WithLockoutThreshold(threshold int) LockoutOption {
+	return lockoutOptionFunc(func(l *Lockout) error {
+		if threshold < 1 {
+			return ErrInvalidLockoutConfig
+		}
+
+		l.threshold = threshold
+		return nil
+	})
+}
+
+// WithLockoutBackoff sets the duration of the first lockout and the maximum duration
+// of any lockout.  Each failure beyond the threshold doubles the lockout duration, up
+// to maxDelay.  Setting both durations to the same value produces a fixed lockout.
+// If this option is not supplied, DefaultLockoutBaseDelay and DefaultLockoutMaxDelay
+// are used.
+func WithLockoutBackoff(baseDelay, maxDelay time.Duration) LockoutOption {
+	return lockoutOptionFunc(func(l *Lockout) error {
+		if baseDelay <= 0 || maxDelay < baseDelay {
+			return ErrInvalidLockoutConfig
+		}
+
+		l.baseDelay = baseDelay
+		l.maxDelay = maxDelay
+		return nil
+	})
+}
+
+// WithLockoutWindow sets how long failures are remembered after the most recent
+// failure or lockout.  If this option is not supplied, DefaultLockoutWindow is used.
+func WithLockoutWindow(window time.Duration) LockoutOption {
+	return lockoutOptionFunc(func(l *Lockout) error {
+		if window <= 0 {
+			return ErrInvalidLockoutConfig
+		}
+
+		l.window = window
+		return nil
+	})
+}
+
+// WithLockoutClock sets the closure used to obtain the current time.  By default,
+// time.Now is used.  A nil closure restores the default.
+func WithLockoutClock(now func() time.Time) LockoutOption {
+	return lockoutOptionFunc(func(l *Lockout) error {
+		l.now = now
+		return nil
+	})
+}
+
+// Lockout tracks failed authentication attempts by key and decides when a key
+// is locked out.  Once a key reaches the configured threshold of consecutive
+// failures, it is locked out for the base delay.  Each further failure doubles
+// the lockout, up to the maximum delay.
+//
+// A Lockout is safe for concurrent use.
+type Lockout struct {
+	store     LockoutStore
+	threshold int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	window    time.Duration
+	now       func() time.Time
+}
+
+// NewLockout creates a Lockout from a set of options.
+func NewLockout(opts ...LockoutOption) (*Lockout, error) {
+	l := &Lockout{
+		threshold: DefaultLockoutThreshold,
+		baseDelay: DefaultLockoutBaseDelay,
+		maxDelay:  DefaultLockoutMaxDelay,
+		window:    DefaultLockoutWindow,
+	}
+
+	for _, o := range opts {
+		if err := o.apply(l); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.now == nil {
+		l.now = time.Now
+	}
+
+	if l.store == nil {
+		l.store = NewMemoryLockoutStore(l.now)
+	}
+
+	return l, nil
+}
+
+// Check tests if any of the given keys are locked out.  If so, a *bascule.LockoutError
+// is returned that reports the longest remaining lockout.
+func (l *Lockout) Check(ctx context.Context, keys ...string) error {
+	now := l.now()
+	var retryAfter time.Duration
+	for _, k := range keys {
+		if ls, ok := l.store.Get(ctx, k); ok && now.Before(ls.Expires) && now.Before(ls.LockedUntil) {
+			retryAfter = max(retryAfter, ls.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return &bascule.LockoutError{
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return nil
+}
+
+// Reserve atomically checks and reserves an attempt for each of the given keys.  Unlike
+// Check, concurrent calls cannot all observe the same state: once the failures and
+// pending attempts for a key reach the threshold, further attempts are refused until
+// the pending ones are resolved.  This keeps a burst of concurrent attempts from
+// exceeding the threshold before any of their failures are recorded.
+//
+// If any key is refused, no attempt is reserved and a *bascule.LockoutError is returned.
+// Otherwise, each reservation must be resolved with exactly one call to Failure, Success,
+// or Release.  Reservations that are never resolved are forgotten with the rest of a
+// key's history once the lockout window passes.
+func (l *Lockout) Reserve(ctx context.Context, keys ...string) error {
+	var (
+		now        = l.now()
+		retryAfter time.Duration
+		reserved   = make([]string, 0, len(keys))
+	)
+
+	for _, k := range keys {
+		var refused time.Duration
+		l.store.Update(ctx, k, func(current LockoutState) LockoutState {
+			refused = 0
+			if !now.Before(current.Expires) {
+				current = LockoutState{} // forget expired history
+			}
+
+			switch {
+			case now.Before(current.LockedUntil):
+				refused = current.LockedUntil.Sub(now)
+
+			case current.Pending > 0 && current.Failures+current.Pending >= l.threshold:
+				// any pending attempt could trigger a lockout
+				refused = l.baseDelay
+
+			default:
+				current.Pending++
+				if expires := now.Add(l.window); expires.After(current.Expires) {
+					current.Expires = expires
+				}
+			}
+
+			return current
+		})
+
+		if refused > 0 {
+			retryAfter = max(retryAfter, refused)
+		} else {
+			reserved = append(reserved, k)
+		}
+	}
+
+	if retryAfter > 0 {
+		l.Release(ctx, reserved...)
+		return &bascule.LockoutError{
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return nil
+}
+
+// Release resolves an attempt reserved with Reserve for each of the given keys,
+// without recording a success or a failure.
+func (l *Lockout) Release(ctx context.Context, keys ...string) {
+	now := l.now()
+	for _, k := range keys {
+		l.store.Update(ctx, k, func(current LockoutState) LockoutState {
+			if !now.Before(current.Expires) {
+				return LockoutState{}
+			}
+
+			current.Pending = max(current.Pending-1, 0)
+			return current
+		})
+	}
+}
+
+// Failure records a failed attempt for each of the given keys.  Any attempt reserved
+// with Reserve is resolved.
+func (l *Lockout) Failure(ctx context.Context, keys ...string) {
+	now := l.now()
+	for _, k := range keys {
+		l.store.Update(ctx, k, func(current LockoutState) LockoutState {
+			if !now.Before(current.Expires) {
+				current = LockoutState{} // forget expired history
+			}
+
+			current.Pending = max(current.Pending-1, 0)
+			current.Failures++
+			current.Expires = now.Add(l.window)
+			if current.Failures >= l.threshold {
+				current.LockedUntil = now.Add(l.delay(current.Failures - l.threshold))
+				if current.LockedUntil.After(current.Expires) {
+					current.Expires = current.LockedUntil
+				}
+			}
+
+			return current
+		})
+	}
+}
+
+// Success clears the failure history, including any reserved attempts, for each of
+// the given keys.
+func (l *Lockout) Success(ctx context.Context, keys ...string) {
+	l.store.Delete(ctx, keys...)
+}
+
+// delay computes the lockout duration after a number of failures beyond the threshold.
+func (l *Lockout) delay(excess int) time.Duration {
+	d := l.baseDelay
+	for i := 0; i < excess && d < l.maxDelay; i++ {
+		d *= 2
+	}
+
+	return min(d, l.maxDelay)
+}
+
+// SourceKeyFunc extracts a key that identifies where a source came from, such as
+// a client IP address.  If no key can be determined, this closure must return false.
+type SourceKeyFunc[S any] func(S) (string, bool)
+
+type lockoutValidator[S any] struct {
+	next      bascule.Validator[S]
+	lockout   *Lockout
+	sourceKey SourceKeyFunc[S]
+}
+
+func (lv *lockoutValidator[S]) Validate(ctx context.Context, source S, t bascule.Token) (bascule.Token, error) {
+	if _, ok := bascule.GetPassword(t); !ok {
+		return lv.next.Validate(ctx, source, t)
+	}
+
+	principalKey := principalKeyPrefix + t.Principal()
+	keys := []string{principalKey}
+	if lv.sourceKey != nil {
+		if k, ok := lv.sourceKey(source); ok {
+			keys = append(keys, sourceKeyPrefix+k)
+		}
+	}
+
+	if err := lv.lockout.Reserve(ctx, keys...); err != nil {
+		return t, err
+	}
+
+	resolved := false
+	defer func() {
+		if !resolved {
+			// the decorated validator panicked
+			lv.lockout.Release(ctx, keys...)
+		}
+	}()
+
+	next, err := lv.next.Validate(ctx, source, t)
+	switch {
+	case err == nil:
+		// only the principal is cleared, so that one valid account cannot
+		// be used to reset the failures of a source
+		lv.lockout.Success(ctx, principalKey)
+		lv.lockout.Release(ctx, keys[1:]...)
+
+	case errors.Is(err, bascule.ErrBadCredentials):
+		lv.lockout.Failure(ctx, keys...)
+
+	default:
+		lv.lockout.Release(ctx, keys...)
+	}
+
+	resolved = true
+	return next, err
+}
+
+// NewLockoutValidator decorates a password Validator, such as one returned by NewValidator,
+// with brute-force protection.  Failures are tracked both for the token's principal and,
+// if sourceKey is not nil, for the key it extracts from the source, e.g. a client IP.
+//
+// While any of those keys are locked out, the decorated Validator is not invoked and a
+// *bascule.LockoutError is returned instead.  Each attempt is reserved with Lockout.Reserve
+// before the decorated Validator is invoked, so concurrent attempts cannot exceed the
+// lockout threshold.  Only failures with bascule.ErrBadCredentials
+// in their chain are counted.  A successful validation clears the principal's failures.
+// Tokens without a password are passed through untouched.
+func NewLockoutValidator[S any](next bascule.Validator[S], l *Lockout, sourceKey SourceKeyFunc[S]) bascule.Validator[S] {
+	return &lockoutValidator[S]{
+		next:      next,
+		lockout:   l,
+		sourceKey: sourceKey,
+	}
+}
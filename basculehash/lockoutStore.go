@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehash
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMemoryLockoutStoreMaxSize is the default maximum number of keys held
+	// by a MemoryLockoutStore.
+	DefaultMemoryLockoutStoreMaxSize = 100_000
+
+	// sweepInterval is the number of updates between sweeps of expired
+	// entries in a MemoryLockoutStore.
+	sweepInterval = 256
+
+	// evictionSample is the number of entries examined when a MemoryLockoutStore
+	// must evict a key to make room for a new one.
+	evictionSample = 16
+)
+
+// LockoutState is the failure history tracked for a single key.
+type LockoutState struct {
+	// Failures is the number of consecutive failed attempts.
+	Failures int
+
+	// LockedUntil is the time at which any lockout ends.  If this time is
+	// not in the future, the key is not locked out.
+	LockedUntil time.Time
+
+	// Expires is the time at which this state should be forgotten.
+	Expires time.Time
+
+	// Pending is the number of attempts reserved with Lockout.Reserve whose
+	// outcome has not yet been recorded.
+	Pending int
+}
+
+// IsZero tests if this state carries no history.
+func (ls LockoutState) IsZero() bool {
+	return ls.Failures == 0 && ls.Pending == 0 && ls.LockedUntil.IsZero() && ls.Expires.IsZero()
+}
+
+// LockoutStore holds LockoutState by key.  Implementations may be in-memory or
+// shared between processes, e.g. backed by a distributed cache.
+type LockoutStore interface {
+	// Get returns the state for a key, or false if no state exists.  Implementations
+	// may return expired states; callers are expected to check Expires.
+	Get(ctx context.Context, key string) (LockoutState, bool)
+
+	// Update atomically replaces the state for a key with the result of f.  The
+	// current state, or the zero value if none exists, is passed to f.  If f
+	// returns the zero value, the key is removed.  The new state is returned.
+	Update(ctx context.Context, key string, f func(LockoutState) LockoutState) LockoutState
+
+	// Delete removes the state for one or more keys.
+	Delete(ctx context.Context, keys ...string)
+}
+
+// MemoryLockoutStore is an in-memory, threadsafe LockoutStore.  Expired states
+// are periodically removed as the store is updated.
+//
+// Keys are frequently derived from attacker-chosen input, such as a principal.  To
+// keep failures under many distinct keys from growing memory without limit, this
+// store holds at most a fixed number of keys.  When a new key must be added to a
+// full store, an existing key is evicted, preferring expired states and then states
+// that are not locked out.  Eviction forgets that key's failures, so the maximum
+// size should comfortably exceed the number of keys expected to fail legitimately
+// within a lockout window.
+//
+// The zero value of this type is valid and ready to use, and holds at most
+// DefaultMemoryLockoutStoreMaxSize keys.  Instances of this type must not be
+// copied after creation.
+type MemoryLockoutStore struct {
+	now     func() time.Time
+	maxSize int
+
+	lock    sync.Mutex
+	states  map[string]LockoutState
+	updates int
+}
+
+var _ LockoutStore = (*MemoryLockoutStore)(nil)
+
+// NewMemoryLockoutStore creates a MemoryLockoutStore that uses the given closure to
+// obtain the current time when removing expired states.  If now is nil, time.Now is used.
+// The returned store holds at most DefaultMemoryLockoutStoreMaxSize keys.
+func NewMemoryLockoutStore(now func() time.Time) *MemoryLockoutStore {
+	return NewBoundedMemoryLockoutStore(now, DefaultMemoryLockoutStoreMaxSize)
+}
+
+// NewBoundedMemoryLockoutStore is like NewMemoryLockoutStore, but allows the maximum
+// number of keys to be set.  If maxSize is nonpositive, DefaultMemoryLockoutStoreMaxSize
+// is used.
+func NewBoundedMemoryLockoutStore(now func() time.Time, maxSize int) *MemoryLockoutStore {
+	return &MemoryLockoutStore{
+		now:     now,
+		maxSize: maxSize,
+	}
+}
+
+// MaxSize returns the maximum number of keys this store will hold.
+func (mls *MemoryLockoutStore) MaxSize() int {
+	if mls.maxSize > 0 {
+		return mls.maxSize
+	}
+
+	return DefaultMemoryLockoutStoreMaxSize
+}
+
+// Len returns the number of keys in this store, including any expired keys
+// that have not yet been removed.
+func (mls *MemoryLockoutStore) Len() (n int) {
+	mls.lock.Lock()
+	n = len(mls.states)
+	mls.lock.Unlock()
+	return
+}
+
+// Get returns the state for a key.
+func (mls *MemoryLockoutStore) Get(_ context.Context, key string) (ls LockoutState, exists bool) {
+	mls.lock.Lock()
+	ls, exists = mls.states[key]
+	mls.lock.Unlock()
+	return
+}
+
+// Update atomically replaces the state for a key.
+func (mls *MemoryLockoutStore) Update(_ context.Context, key string, f func(LockoutState) LockoutState) LockoutState {
+	mls.lock.Lock()
+	defer mls.lock.Unlock()
+
+	current, exists := mls.states[key]
+	next := f(current)
+	switch {
+	case next.IsZero():
+		delete(mls.states, key)
+
+	case mls.states == nil:
+		mls.states = map[string]LockoutState{key: next}
+
+	default:
+		if !exists && len(mls.states) >= mls.MaxSize() {
+			mls.evict()
+		}
+
+		mls.states[key] = next
+	}
+
+	if mls.updates++; mls.updates >= sweepInterval {
+		mls.updates = 0
+		mls.sweep()
+	}
+
+	return next
+}
+
+// Delete removes the state for one or more keys.
+func (mls *MemoryLockoutStore) Delete(_ context.Context, keys ...string) {
+	mls.lock.Lock()
+	for _, k := range keys {
+		delete(mls.states, k)
+	}
+
+	mls.lock.Unlock()
+}
+
+// Sweep removes all expired states from this store.  Sweeps happen automatically
+// as the store is updated, so calling this method is optional.
+func (mls *MemoryLockoutStore) Sweep() {
+	mls.lock.Lock()
+	mls.sweep()
+	mls.lock.Unlock()
+}
+
+// currentTime returns the current time from this store's clock.
+func (mls *MemoryLockoutStore) currentTime() time.Time {
+	if mls.now != nil {
+		return mls.now()
+	}
+
+	return time.Now()
+}
+
+// evict removes one key to make room for another.  Rather than scanning the whole
+// store, a small sample of keys is examined so that eviction stays cheap when an
+// attacker keeps the store full.  The first expired state found is removed.  Otherwise,
+// the sampled state that expires soonest is removed, preferring states that are not
+// locked out.  The lock must be held.
+func (mls *MemoryLockoutStore) evict() {
+	var (
+		current = mls.currentTime()
+		victim  string
+		best    LockoutState
+		found   bool
+		n       int
+	)
+
+	for k, ls := range mls.states {
+		if !current.Before(ls.Expires) {
+			delete(mls.states, k)
+			return
+		}
+
+		locked := current.Before(ls.LockedUntil)
+		bestLocked := current.Before(best.LockedUntil)
+		if !found || (bestLocked && !locked) || (bestLocked == locked && ls.Expires.Before(best.Expires)) {
+			victim, best, found = k, ls, true
+		}
+
+		if n++; n >= evictionSample {
+			break
+		}
+	}
+
+	if found {
+		delete(mls.states, victim)
+	}
+}
+
+// sweep removes expired states.  The lock must be held.
+func (mls *MemoryLockoutStore) sweep() {
+	current := mls.currentTime()
+	for k, ls := range mls.states {
+		if !current.Before(ls.Expires) {
+			delete(mls.states, k)
+		}
+	}
+}
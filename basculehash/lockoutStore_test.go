@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehash
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MemoryLockoutStoreTestSuite struct {
+	suite.Suite
+
+	now time.Time
+}
+
+func (suite *MemoryLockoutStoreTestSuite) SetupTest() {
+	suite.now = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *MemoryLockoutStoreTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *MemoryLockoutStoreTestSuite) TestZeroValue() {
+	var mls MemoryLockoutStore
+	_, exists := mls.Get(context.Background(), "key")
+	suite.False(exists)
+
+	mls.Delete(context.Background(), "key")
+	mls.Sweep()
+	suite.Zero(mls.Len())
+
+	next := mls.Update(context.Background(), "key", func(current LockoutState) LockoutState {
+		suite.True(current.IsZero())
+		current.Failures = 1
+		current.Expires = time.Now().Add(time.Hour)
+		return current
+	})
+
+	suite.Equal(1, next.Failures)
+	suite.Equal(1, mls.Len())
+}
+
+func (suite *MemoryLockoutStoreTestSuite) TestUpdate() {
+	mls := NewMemoryLockoutStore(suite.clock)
+	expected := LockoutState{
+		Failures: 2,
+		Expires:  suite.now.Add(time.Minute),
+	}
+
+	mls.Update(context.Background(), "key", func(LockoutState) LockoutState { return expected })
+	actual, exists := mls.Get(context.Background(), "key")
+	suite.True(exists)
+	suite.Equal(expected, actual)
+
+	// returning the zero value removes the key
+	mls.Update(context.Background(), "key", func(LockoutState) LockoutState { return LockoutState{} })
+	_, exists = mls.Get(context.Background(), "key")
+	suite.False(exists)
+}
+
+func (suite *MemoryLockoutStoreTestSuite) TestDelete() {
+	mls := NewMemoryLockoutStore(suite.clock)
+	for _, k := range []string{"a", "b", "c"} {
+		mls.Update(context.Background(), k, func(LockoutState) LockoutState {
+			return LockoutState{Failures: 1, Expires: suite.now.Add(time.Minute)}
+		})
+	}
+
+	mls.Delete(context.Background(), "a", "c")
+	suite.Equal(1, mls.Len())
+	_, exists := mls.Get(context.Background(), "b")
+	suite.True(exists)
+}
+
+func (suite *MemoryLockoutStoreTestSuite) TestSweep() {
+	mls := NewMemoryLockoutStore(suite.clock)
+	mls.Update(context.Background(), "expired", func(LockoutState) LockoutState {
+		return LockoutState{Failures: 1, Expires: suite.now.Add(time.Second)}
+	})
+
+	mls.Update(context.Background(), "current", func(LockoutState) LockoutState {
+		return LockoutState{Failures: 1, Expires: suite.now.Add(time.Hour)}
+	})
+
+	suite.now = suite.now.Add(time.Minute)
+	mls.Sweep()
+	suite.Equal(1, mls.Len())
+	_, exists := mls.Get(context.Background(), "current")
+	suite.True(exists)
+
+	suite.Run("Automatic", func() {
+		for i := 0; i < sweepInterval; i++ {
+			mls.Update(context.Background(), strconv.Itoa(i), func(LockoutState) LockoutState {
+				return LockoutState{Failures: 1, Expires: suite.now.Add(time.Second)}
+			})
+		}
+
+		suite.now = suite.now.Add(time.Minute)
+		suite.Equal(sweepInterval+1, mls.Len())
+		for i := 0; i < sweepInterval; i++ {
+			mls.Update(context.Background(), "current", func(current LockoutState) LockoutState { return current })
+		}
+
+		suite.Equal(1, mls.Len())
+	})
+}
+
+func (suite *MemoryLockoutStoreTestSuite) TestMaxSize() {
+	suite.Run("Default", func() {
+		var mls MemoryLockoutStore
+		suite.Equal(DefaultMemoryLockoutStoreMaxSize, mls.MaxSize())
+		suite.Equal(DefaultMemoryLockoutStoreMaxSize, NewMemoryLockoutStore(nil).MaxSize())
+		suite.Equal(DefaultMemoryLockoutStoreMaxSize, NewBoundedMemoryLockoutStore(nil, 0).MaxSize())
+	})
+
+	suite.Run("Bounded", func() {
+		mls := NewBoundedMemoryLockoutStore(suite.clock, 10)
+		suite.Equal(10, mls.MaxSize())
+		for i := 0; i < 100; i++ {
+			mls.Update(context.Background(), strconv.Itoa(i), func(LockoutState) LockoutState {
+				return LockoutState{Failures: 1, Expires: suite.now.Add(time.Minute)}
+			})
+
+			suite.LessOrEqual(mls.Len(), 10)
+		}
+
+		// updating an existing key in a full store evicts nothing
+		mls.Update(context.Background(), "99", func(current LockoutState) LockoutState {
+			current.Failures++
+			return current
+		})
+
+		suite.Equal(10, mls.Len())
+		state, exists := mls.Get(context.Background(), "99")
+		suite.True(exists)
+		suite.Equal(2, state.Failures)
+	})
+
+	suite.Run("PreferExpired", func() {
+		mls := NewBoundedMemoryLockoutStore(suite.clock, 2)
+		mls.Update(context.Background(), "current", func(LockoutState) LockoutState {
+			return LockoutState{Failures: 1, Expires: suite.now.Add(time.Hour)}
+		})
+
+		mls.Update(context.Background(), "expired", func(LockoutState) LockoutState {
+			return LockoutState{Failures: 1, Expires: suite.now}
+		})
+
+		mls.Update(context.Background(), "new", func(LockoutState) LockoutState {
+			return LockoutState{Failures: 1, Expires: suite.now.Add(time.Hour)}
+		})
+
+		suite.Equal(2, mls.Len())
+		_, exists := mls.Get(context.Background(), "expired")
+		suite.False(exists)
+		_, exists = mls.Get(context.Background(), "current")
+		suite.True(exists)
+	})
+
+	suite.Run("PreferUnlocked", func() {
+		mls := NewBoundedMemoryLockoutStore(suite.clock, 2)
+		mls.Update(context.Background(), "locked", func(LockoutState) LockoutState {
+			return LockoutState{
+				Failures:    5,
+				LockedUntil: suite.now.Add(time.Minute),
+				Expires:     suite.now.Add(time.Minute),
+			}
+		})
+
+		mls.Update(context.Background(), "unlocked", func(LockoutState) LockoutState {
+			return LockoutState{Failures: 1, Expires: suite.now.Add(time.Hour)}
+		})
+
+		mls.Update(context.Background(), "new", func(LockoutState) LockoutState {
+			return LockoutState{Failures: 1, Expires: suite.now.Add(time.Hour)}
+		})
+
+		suite.Equal(2, mls.Len())
+		_, exists := mls.Get(context.Background(), "unlocked")
+		suite.False(exists)
+		_, exists = mls.Get(context.Background(), "locked")
+		suite.True(exists)
+	})
+}
+
+func TestMemoryLockoutStore(t *testing.T) {
+	suite.Run(t, new(MemoryLockoutStoreTestSuite))
+}
@@ -0,0 +1,331 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehash
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type LockoutTestSuite struct {
+	TestSuite
+
+	now time.Time
+}
+
+func (suite *LockoutTestSuite) SetupTest() {
+	suite.TestSuite.SetupTest()
+	suite.now = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *LockoutTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *LockoutTestSuite) newLockout(opts ...LockoutOption) *Lockout {
+	l, err := NewLockout(append([]LockoutOption{WithLockoutClock(suite.clock)}, opts...)...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(l)
+	return l
+}
+
+// assertLockedOut asserts that the error is a lockout with the given retry duration.
+func (suite *LockoutTestSuite) assertLockedOut(expected time.Duration, err error) {
+	var le *bascule.LockoutError
+	suite.Require().ErrorAs(err, &le)
+	suite.Equal(expected, le.RetryAfter)
+}
+
+func (suite *LockoutTestSuite) TestInvalidConfig() {
+	for _, o := range []LockoutOption{
+		WithLockoutStore(nil),
+		WithLockoutThreshold(0),
+		WithLockoutBackoff(0, time.Second),
+		WithLockoutBackoff(time.Minute, time.Second),
+		WithLockoutWindow(0),
+	} {
+		l, err := NewLockout(o)
+		suite.Nil(l)
+		suite.ErrorIs(err, ErrInvalidLockoutConfig)
+	}
+}
+
+func (suite *LockoutTestSuite) TestDefaults() {
+	l := suite.newLockout()
+	suite.Equal(DefaultLockoutThreshold, l.threshold)
+	suite.Equal(DefaultLockoutBaseDelay, l.baseDelay)
+	suite.Equal(DefaultLockoutMaxDelay, l.maxDelay)
+	suite.Equal(DefaultLockoutWindow, l.window)
+	suite.IsType((*MemoryLockoutStore)(nil), l.store)
+}
+
+func (suite *LockoutTestSuite) TestBackoff() {
+	var (
+		ctx   = context.Background()
+		store = NewMemoryLockoutStore(suite.clock)
+		l     = suite.newLockout(
+			WithLockoutStore(store),
+			WithLockoutThreshold(3),
+			WithLockoutBackoff(time.Second, 5*time.Second),
+			WithLockoutWindow(time.Minute),
+		)
+	)
+
+	l.Failure(ctx, "key")
+	l.Failure(ctx, "key")
+	suite.NoError(l.Check(ctx, "key"))
+
+	l.Failure(ctx, "key")
+	suite.assertLockedOut(time.Second, l.Check(ctx, "key"))
+	suite.NoError(l.Check(ctx, "other"))
+
+	l.Failure(ctx, "key")
+	suite.assertLockedOut(2*time.Second, l.Check(ctx, "key"))
+
+	l.Failure(ctx, "key")
+	suite.assertLockedOut(4*time.Second, l.Check(ctx, "key"))
+
+	l.Failure(ctx, "key")
+	suite.assertLockedOut(5*time.Second, l.Check(ctx, "key")) // capped
+
+	suite.now = suite.now.Add(3 * time.Second)
+	suite.assertLockedOut(2*time.Second, l.Check(ctx, "key"))
+
+	suite.now = suite.now.Add(2 * time.Second)
+	suite.NoError(l.Check(ctx, "key"))
+
+	// still within the window, so the next failure locks out again
+	l.Failure(ctx, "key")
+	suite.assertLockedOut(5*time.Second, l.Check(ctx, "key"))
+
+	suite.Run("WindowExpires", func() {
+		suite.now = suite.now.Add(time.Hour)
+		suite.NoError(l.Check(ctx, "key"))
+
+		l.Failure(ctx, "key")
+		suite.NoError(l.Check(ctx, "key"))
+
+		ls, _ := store.Get(ctx, "key")
+		suite.Equal(1, ls.Failures)
+	})
+
+	suite.Run("Success", func() {
+		l.Failure(ctx, "key")
+		l.Failure(ctx, "key")
+		suite.Error(l.Check(ctx, "key"))
+
+		l.Success(ctx, "key")
+		suite.NoError(l.Check(ctx, "key"))
+		suite.Zero(store.Len())
+	})
+}
+
+func (suite *LockoutTestSuite) TestCheckMultipleKeys() {
+	ctx := context.Background()
+	l := suite.newLockout(WithLockoutThreshold(1), WithLockoutBackoff(time.Second, time.Minute))
+
+	l.Failure(ctx, "a")
+	l.Failure(ctx, "b", "b")
+	suite.assertLockedOut(2*time.Second, l.Check(ctx, "a", "b", "c"))
+	suite.assertLockedOut(time.Second, l.Check(ctx, "c", "a"))
+}
+
+func (suite *LockoutTestSuite) TestReserve() {
+	ctx := context.Background()
+	l := suite.newLockout(WithLockoutThreshold(2), WithLockoutBackoff(time.Second, time.Minute))
+
+	suite.NoError(l.Reserve(ctx, "a"))
+	suite.NoError(l.Reserve(ctx, "a"))
+
+	// both pending attempts could fail, so a third is refused
+	suite.assertLockedOut(time.Second, l.Reserve(ctx, "a"))
+
+	// a refused key releases the others
+	suite.assertLockedOut(time.Second, l.Reserve(ctx, "b", "a"))
+	ls, ok := l.store.Get(ctx, "b")
+	suite.True(!ok || ls.Pending == 0)
+
+	l.Release(ctx, "a")
+	suite.NoError(l.Reserve(ctx, "a"))
+
+	l.Failure(ctx, "a")
+	l.Failure(ctx, "a")
+	ls, ok = l.store.Get(ctx, "a")
+	suite.Require().True(ok)
+	suite.Equal(2, ls.Failures)
+	suite.Zero(ls.Pending)
+	suite.assertLockedOut(time.Second, l.Reserve(ctx, "a"))
+
+	// once the lockout lapses, only one attempt at a time is allowed
+	suite.now = suite.now.Add(time.Second)
+	suite.NoError(l.Reserve(ctx, "a"))
+	suite.assertLockedOut(time.Second, l.Reserve(ctx, "a"))
+
+	l.Success(ctx, "a")
+	_, ok = l.store.Get(ctx, "a")
+	suite.False(ok)
+}
+
+func (suite *LockoutTestSuite) TestConcurrentAttempts() {
+	const (
+		threshold = 3
+		attempts  = 50
+	)
+
+	var (
+		ctx     = context.Background()
+		l       = suite.newLockout(WithLockoutThreshold(threshold))
+		calls   atomic.Int32
+		gate    = make(chan struct{})
+		results = make(chan error, attempts)
+
+		v = NewLockoutValidator[string](
+			bascule.AsValidator[string](func(bascule.Token) error {
+				calls.Add(1)
+				<-gate
+				return bascule.ErrBadCredentials
+			}),
+			l,
+			nil,
+		)
+	)
+
+	for i := 0; i < attempts; i++ {
+		go func() {
+			_, err := v.Validate(ctx, "source", validatorTestToken{principal: "joe", password: "bad"})
+			results <- err
+		}()
+	}
+
+	// the attempts beyond the threshold are refused while the others are in flight
+	for i := 0; i < attempts-threshold; i++ {
+		select {
+		case err := <-results:
+			suite.ErrorIs(err, bascule.ErrLockedOut)
+
+		case <-time.After(5 * time.Second):
+			suite.FailNow("attempts were not refused")
+		}
+	}
+
+	suite.Equal(int32(threshold), calls.Load())
+	close(gate)
+	for i := 0; i < threshold; i++ {
+		suite.ErrorIs(<-results, bascule.ErrBadCredentials)
+	}
+
+	ls, ok := l.store.Get(ctx, principalKeyPrefix+"joe")
+	suite.Require().True(ok)
+	suite.Equal(threshold, ls.Failures)
+	suite.Zero(ls.Pending)
+}
+
+func (suite *LockoutTestSuite) newValidator(l *Lockout) (bascule.Validator[*http.Request], *int) {
+	hc := Bcrypt{Cost: bcrypt.MinCost}
+	compares := new(int)
+	v := NewValidator[*http.Request](hc, Principals{
+		"joe": suite.goodHash(hc.Hash(suite.plaintext)),
+	})
+
+	// count how many times the decorated validator is invoked
+	counting := bascule.AsValidator[*http.Request](
+		func(ctx context.Context, source *http.Request, t bascule.Token) (bascule.Token, error) {
+			*compares++
+			return v.Validate(ctx, source, t)
+		},
+	)
+
+	return NewLockoutValidator(counting, l, func(r *http.Request) (string, bool) {
+		return r.RemoteAddr, len(r.RemoteAddr) > 0
+	}), compares
+}
+
+func (suite *LockoutTestSuite) newRequest(remoteAddr string) *http.Request {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func (suite *LockoutTestSuite) TestValidator() {
+	var (
+		ctx      = context.Background()
+		l        = suite.newLockout(WithLockoutThreshold(2), WithLockoutBackoff(time.Minute, time.Hour))
+		v, calls = suite.newValidator(l)
+
+		good = validatorTestToken{principal: "joe", password: string(suite.plaintext)}
+		bad  = validatorTestToken{principal: "joe", password: "bad"}
+	)
+
+	suite.Run("NonPasswordToken", func() {
+		t := bascule.StubToken("joe")
+		next, err := v.Validate(ctx, suite.newRequest("attacker"), t)
+		suite.NoError(err)
+		suite.Equal(t, next)
+		suite.Equal(1, *calls)
+	})
+
+	suite.Run("Principal", func() {
+		*calls = 0
+		for i := 0; i < 2; i++ {
+			_, err := v.Validate(ctx, suite.newRequest("attacker"), bad)
+			suite.ErrorIs(err, bascule.ErrBadCredentials)
+		}
+
+		// even the correct password is refused, from any source, without a comparison
+		next, err := v.Validate(ctx, suite.newRequest("legitimate"), good)
+		suite.Equal(good, next)
+		suite.assertLockedOut(time.Minute, err)
+		suite.ErrorIs(err, bascule.ErrLockedOut)
+		suite.Equal(2, *calls)
+
+		suite.now = suite.now.Add(time.Minute)
+		_, err = v.Validate(ctx, suite.newRequest("legitimate"), good)
+		suite.NoError(err)
+		suite.Equal(3, *calls)
+	})
+
+	suite.Run("Source", func() {
+		// the attacker's lockout has lapsed, and a success does not clear its failures
+		_, err := v.Validate(ctx, suite.newRequest("attacker"), good)
+		suite.NoError(err)
+
+		// so a single failure against another principal locks the attacker out again
+		_, err = v.Validate(ctx, suite.newRequest("attacker"), validatorTestToken{principal: "fred", password: "bad"})
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+
+		_, err = v.Validate(ctx, suite.newRequest("attacker"), good)
+		suite.assertLockedOut(2*time.Minute, err)
+
+		// no source key, so only the principal is checked
+		_, err = v.Validate(ctx, suite.newRequest(""), good)
+		suite.NoError(err)
+	})
+
+	suite.Run("OtherErrors", func() {
+		expectedErr := errors.New("expected")
+		v := NewLockoutValidator[string](
+			bascule.AsValidator[string](func(bascule.Token) error { return expectedErr }),
+			l,
+			nil,
+		)
+
+		for i := 0; i < 5; i++ {
+			_, err := v.Validate(ctx, "source", validatorTestToken{principal: "sam", password: "x"})
+			suite.ErrorIs(err, expectedErr)
+		}
+	})
+}
+
+func TestLockout(t *testing.T) {
+	suite.Run(t, new(LockoutTestSuite))
+}
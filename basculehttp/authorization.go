@@ -7,9 +7,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculejwt"
 )
 
 const (
@@ -79,13 +82,47 @@ func WithBasic() AuthorizationParserOption {
 	return WithScheme(SchemeBasic, BasicTokenParser{})
 }
 
+// WithBearerParser registers a BearerTokenParser that delegates to the given parser
+// for the default Bearer scheme.  This option also adds a Bearer challenge to the
+// AuthorizationParser, as described by AuthorizationParser.Challenges.
+func WithBearerParser(parser bascule.TokenParser[string]) AuthorizationParserOption {
+	return authorizationParserOptionFunc(func(ap *AuthorizationParser) error {
+		if parser == nil {
+			return errors.New("A bearer token parser is required")
+		}
+
+		ap.parsers[SchemeBearer.lower()] = BearerTokenParser{Parser: parser}
+		if !slices.ContainsFunc(ap.challenges, func(c Challenge) bool { return c.Scheme == SchemeBearer }) {
+			ap.challenges = ap.challenges.Append(NewBearerChallenge(""))
+		}
+
+		return nil
+	})
+}
+
+// WithBearer is a shorthand for WithBearerParser that registers JWT parsing, via
+// basculejwt.NewTokenParser, for the default Bearer scheme.  The options are passed
+// as is to basculejwt.NewTokenParser, and typically supply the keys used to verify
+// signatures.
+func WithBearer(options ...jwt.ParseOption) AuthorizationParserOption {
+	parser, err := basculejwt.NewTokenParser(options...)
+	if err != nil {
+		return authorizationParserOptionFunc(func(*AuthorizationParser) error {
+			return err
+		})
+	}
+
+	return WithBearerParser(parser)
+}
+
 // AuthorizationParsers is a bascule.TokenParser that handles the Authorization header.
 //
 // By default, this parser will use the standard Authorization header, which can be
 // changed via with WithAuthorizationHeader option.
 type AuthorizationParser struct {
-	header  string
-	parsers map[Scheme]bascule.TokenParser[string]
+	header     string
+	parsers    map[Scheme]bascule.TokenParser[string]
+	challenges Challenges
 }
 
 // NewAuthorizationParser constructs an Authorization parser from a set
@@ -108,30 +145,67 @@ func NewAuthorizationParser(opts ...AuthorizationParserOption) (*AuthorizationPa
 	return ap, nil
 }
 
+// Challenges returns the challenges implied by the schemes registered with this
+// parser, e.g. a Bearer challenge when WithBearer is used.  Errors from Parse that
+// this parser produces itself, such as for a missing header, carry these challenges
+// in a *ChallengeError.  A Middleware whose authenticator uses this parser also sends
+// these challenges with every StatusUnauthorized response, including those caused by
+// validation failures.
+func (ap *AuthorizationParser) Challenges() Challenges {
+	return slices.Clone(ap.challenges)
+}
+
+// challenge wraps an error in a *ChallengeError if this parser has any challenges.
+func (ap *AuthorizationParser) challenge(err error) error {
+	if len(ap.challenges) == 0 {
+		return err
+	}
+
+	return &ChallengeError{
+		Challenges: ap.Challenges(),
+		Err:        err,
+	}
+}
+
 // Parse extracts the appropriate header, Authorization by default, and parses the
 // scheme and value.  Schemes are case-insensitive, e.g. BASIC and Basic are the same scheme.
 //
 // If no authorization header is found in the request, this method returns ErrMissingCredentials.
+// If this parser has any Challenges, this error and any other error produced by this parser
+// itself is wrapped in a *ChallengeError.
 //
 // If a token parser is registered for the given scheme, that token parser is invoked.
 // Otherwise, UnsupportedSchemeError is returned, indicating the scheme in question.
+// Both UnsupportedSchemeError and any error from the scheme's token parser are wrapped
+// in a *bascule.AuthError that carries the scheme.
 func (ap *AuthorizationParser) Parse(ctx context.Context, source *http.Request) (bascule.Token, error) {
 	authValue := source.Header.Get(ap.header)
 	if len(authValue) == 0 {
-		return nil, bascule.ErrMissingCredentials
+		return nil, ap.challenge(bascule.ErrMissingCredentials)
 	}
 
 	scheme, value, err := ParseAuthorization(authValue)
 	if err != nil {
-		return nil, bascule.ErrInvalidCredentials
+		return nil, ap.challenge(bascule.ErrInvalidCredentials)
 	}
 
 	p, registered := ap.parsers[scheme.lower()]
 	if !registered {
-		return nil, &UnsupportedSchemeError{
-			Scheme: scheme,
+		return nil, &bascule.AuthError{
+			Scheme: string(scheme),
+			Err: ap.challenge(&UnsupportedSchemeError{
+				Scheme: scheme,
+			}),
+		}
+	}
+
+	t, err := p.Parse(ctx, value)
+	if err != nil {
+		err = &bascule.AuthError{
+			Scheme: string(scheme),
+			Err:    err,
 		}
 	}
 
-	return p.Parse(ctx, value)
+	return t, err
 }
@@ -8,8 +8,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/suite"
 	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculejwt"
 )
 
 // withAuthorizationParserOptionErr is an option that returns an error.
@@ -110,6 +113,80 @@ func (suite *AuthorizationTestSuite) TestUnsupportedScheme() {
 	var use *UnsupportedSchemeError
 	suite.Require().ErrorAs(err, &use)
 	suite.Equal(Scheme("Unsupported"), use.Scheme)
+	var ae *bascule.AuthError
+	suite.Require().ErrorAs(err, &ae)
+	suite.Equal("Unsupported", ae.Scheme)
+}
+
+func (suite *AuthorizationTestSuite) TestBearer() {
+	suite.Run("Success", func() {
+		ap := suite.newAuthorizationParser(
+			WithBearer(jwt.WithKey(jwa.HS256, bearerKey)),
+		)
+
+		token, err := ap.Parse(context.Background(), suite.newBearerRequest(suite.signJWT(bearerKey)))
+		suite.Require().NoError(err)
+		suite.Equal(expectedPrincipal, token.Principal())
+
+		var claims basculejwt.Claims
+		suite.True(bascule.TokenAs(token, &claims))
+	})
+
+	suite.Run("BadSignature", func() {
+		ap := suite.newAuthorizationParser(
+			WithBearer(jwt.WithKey(jwa.HS256, bearerKey)),
+		)
+
+		token, err := ap.Parse(context.Background(), suite.newBearerRequest(suite.signJWT([]byte("wrong key"))))
+		suite.Nil(token)
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+
+		var ae *bascule.AuthError
+		suite.Require().ErrorAs(err, &ae)
+		suite.Equal(string(SchemeBearer), ae.Scheme)
+
+		var ce *ChallengeError
+		suite.Require().ErrorAs(err, &ce)
+		suite.Equal(Challenges{NewBearerChallenge(BearerInvalidToken)}, ce.Challenges)
+	})
+
+	suite.Run("Challenges", func() {
+		ap := suite.newAuthorizationParser(
+			WithBasic(),
+			WithBearerParser(BasicTokenParser{}),
+			WithBearer(jwt.WithKey(jwa.HS256, bearerKey)),
+		)
+
+		suite.Equal(Challenges{NewBearerChallenge("")}, ap.Challenges())
+
+		token, err := ap.Parse(context.Background(), suite.newRequest())
+		suite.Nil(token)
+		suite.ErrorIs(err, bascule.ErrMissingCredentials)
+
+		var ce *ChallengeError
+		suite.Require().ErrorAs(err, &ce)
+		suite.Equal(ap.Challenges(), ce.Challenges)
+
+		request := suite.newRequest()
+		request.Header.Set(DefaultAuthorizationHeader, "Unsupported xyz")
+		_, err = ap.Parse(context.Background(), request)
+		suite.Require().ErrorAs(err, &ce)
+		suite.Equal(ap.Challenges(), ce.Challenges)
+	})
+
+	suite.Run("NoChallenges", func() {
+		ap := suite.newAuthorizationParser(WithBasic())
+		suite.Empty(ap.Challenges())
+
+		_, err := ap.Parse(context.Background(), suite.newRequest())
+		suite.Equal(bascule.ErrMissingCredentials, err)
+	})
+
+	suite.Run("NilParser", func() {
+		ap, err := NewAuthorizationParser(WithBearerParser(nil))
+		suite.Error(err)
+		suite.Nil(ap)
+	})
 }
 
 func (suite *AuthorizationTestSuite) TestOptionError() {
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// BearerErrorParameter is the RFC 6750 challenge parameter that holds an error code.
+	BearerErrorParameter = "error"
+
+	// BearerInvalidRequest is the RFC 6750 error code for a malformed request.
+	BearerInvalidRequest = "invalid_request"
+
+	// BearerInvalidToken is the RFC 6750 error code for a token that is expired,
+	// revoked, malformed, or otherwise invalid.
+	BearerInvalidToken = "invalid_token"
+)
+
+// isToken68Char tests if c is allowed in the body of an RFC 6750 b64token.
+func isToken68Char(c byte) bool {
+	switch {
+	case 'A' <= c && c <= 'Z':
+		return true
+
+	case 'a' <= c && c <= 'z':
+		return true
+
+	case '0' <= c && c <= '9':
+		return true
+
+	default:
+		return c == '-' || c == '.' || c == '_' || c == '~' || c == '+' || c == '/'
+	}
+}
+
+// IsToken68 tests if v conforms to the b64token syntax required for Bearer credentials
+// by RFC 6750, section 2.1.  The value must contain at least one character from the
+// token68 alphabet, followed by any number of '=' padding characters.
+func IsToken68(v string) bool {
+	i := 0
+	for i < len(v) && isToken68Char(v[i]) {
+		i++
+	}
+
+	if i == 0 {
+		return false
+	}
+
+	for i < len(v) && v[i] == '=' {
+		i++
+	}
+
+	return i == len(v)
+}
+
+// NewBearerChallenge creates a Challenge for the Bearer scheme.  If errorCode is
+// not empty, it is set as the BearerErrorParameter, e.g. BearerInvalidToken.
+func NewBearerChallenge(errorCode string) (c Challenge) {
+	c = Challenge{
+		Scheme: SchemeBearer,
+	}
+
+	// ignore errors, as this function allows the error code to be empty.
+	c.Parameters.Set(BearerErrorParameter, errorCode)
+	return
+}
+
+// BearerTokenParser is a string-based bascule.TokenParser for RFC 6750 Bearer
+// credentials.  The credential value is checked with IsToken68 before it is handed
+// off to Parser.
+//
+// A value that is not a valid b64token results in bascule.ErrInvalidCredentials.
+// Any error from Parser is returned as is.  In both cases, the error is wrapped in a
+// *ChallengeError that carries a Bearer challenge with the appropriate RFC 6750
+// error code, which the Middleware writes for StatusUnauthorized responses.
+type BearerTokenParser struct {
+	// Parser is the parser for the token value, e.g. from basculejwt.NewTokenParser.
+	// This field is required.
+	Parser bascule.TokenParser[string]
+}
+
+// Parse validates the syntax of the value, then delegates to the Parser.
+func (btp BearerTokenParser) Parse(ctx context.Context, value string) (bascule.Token, error) {
+	if !IsToken68(value) {
+		return nil, &ChallengeError{
+			Challenges: Challenges{NewBearerChallenge(BearerInvalidRequest)},
+			Err:        fmt.Errorf("%w: malformed bearer token", bascule.ErrInvalidCredentials),
+		}
+	}
+
+	t, err := btp.Parser.Parse(ctx, value)
+	if err != nil {
+		err = &ChallengeError{
+			Challenges: Challenges{NewBearerChallenge(BearerInvalidToken)},
+			Err:        err,
+		}
+	}
+
+	return t, err
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type BearerTestSuite struct {
+	TestSuite
+}
+
+// assertChallenge asserts that err carries a single Bearer challenge with the given error code.
+func (suite *BearerTestSuite) assertChallenge(err error, errorCode string) {
+	var ce *ChallengeError
+	suite.Require().ErrorAs(err, &ce)
+
+	header := make(http.Header)
+	suite.Require().NoError(ce.Challenges.WriteHeader(header))
+	suite.Equal(
+		[]string{`Bearer error="` + errorCode + `"`},
+		header.Values(WWWAuthenticateHeader),
+	)
+}
+
+func (suite *BearerTestSuite) TestIsToken68() {
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "=", expected: false},
+		{value: "abc", expected: true},
+		{value: "eyJhbGciOi.eyJzdWIiOi.c2lnbmF0dXJl", expected: true},
+		{value: "AZaz09-._~+/", expected: true},
+		{value: "abc==", expected: true},
+		{value: "abc=def", expected: false},
+		{value: "abc def", expected: false},
+		{value: "abc\t", expected: false},
+		{value: "abc,def", expected: false},
+		{value: "ab\"c", expected: false},
+		{value: "é", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.value, func() {
+			suite.Equal(testCase.expected, IsToken68(testCase.value))
+		})
+	}
+}
+
+func (suite *BearerTestSuite) TestNewBearerChallenge() {
+	suite.Run("NoErrorCode", func() {
+		c := NewBearerChallenge("")
+		suite.Equal(SchemeBearer, c.Scheme)
+		suite.Zero(c.Parameters.Len())
+	})
+
+	suite.Run("ErrorCode", func() {
+		c := NewBearerChallenge(BearerInvalidToken)
+		suite.Equal(SchemeBearer, c.Scheme)
+		suite.Equal(`error="invalid_token"`, c.Parameters.String())
+	})
+}
+
+func (suite *BearerTestSuite) TestBearerTokenParser() {
+	suite.Run("Success", func() {
+		btp := BearerTokenParser{
+			Parser: bascule.AsTokenParser[string](func(_ context.Context, value string) (bascule.Token, error) {
+				suite.Equal("abc==", value)
+				return bascule.StubToken("joe"), nil
+			}),
+		}
+
+		t, err := btp.Parse(context.Background(), "abc==")
+		suite.NoError(err)
+		suite.Equal(bascule.StubToken("joe"), t)
+	})
+
+	suite.Run("Malformed", func() {
+		btp := BearerTokenParser{
+			Parser: bascule.AsTokenParser[string](func(context.Context, string) (bascule.Token, error) {
+				suite.Fail("the parser should not have been called")
+				return nil, nil
+			}),
+		}
+
+		t, err := btp.Parse(context.Background(), "not a token68")
+		suite.Nil(t)
+		suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+		suite.assertChallenge(err, BearerInvalidRequest)
+	})
+
+	suite.Run("ParserError", func() {
+		expectedErr := errors.New("expected")
+		btp := BearerTokenParser{
+			Parser: bascule.AsTokenParser[string](func(context.Context, string) (bascule.Token, error) {
+				return nil, expectedErr
+			}),
+		}
+
+		t, err := btp.Parse(context.Background(), "abc")
+		suite.Nil(t)
+		suite.ErrorIs(err, expectedErr)
+		suite.assertChallenge(err, BearerInvalidToken)
+	})
+}
+
+func TestBearer(t *testing.T) {
+	suite.Run(t, new(BearerTestSuite))
+}
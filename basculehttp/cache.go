@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"net/http"
+
+	"github.com/xmidt-org/bascule"
+)
+
+// HeaderCacheKey returns a bascule.CacheKeyFunc that uses the value of the given
+// header as the raw credential.  If header is blank, DefaultAuthorizationHeader is used.
+// Requests without the header are never cached.
+func HeaderCacheKey(header string) bascule.CacheKeyFunc[*http.Request] {
+	if len(header) == 0 {
+		header = DefaultAuthorizationHeader
+	}
+
+	return func(request *http.Request) ([]byte, bool) {
+		v := request.Header.Get(header)
+		return []byte(v), len(v) > 0
+	}
+}
+
+// NewAuthenticateCache is a convenient wrapper around bascule.NewAuthenticateCache that
+// keys results by the DefaultAuthorizationHeader.  The returned cache can be passed to
+// bascule.WithAuthenticateCache.
+func NewAuthenticateCache(opts ...bascule.CacheOption) (*bascule.AuthenticateCache[*http.Request], error) {
+	return bascule.NewAuthenticateCache(HeaderCacheKey(DefaultAuthorizationHeader), opts...)
+}
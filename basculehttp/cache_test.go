@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type CacheTestSuite struct {
+	TestSuite
+}
+
+func (suite *CacheTestSuite) TestHeaderCacheKey() {
+	suite.Run("Default", func() {
+		key := HeaderCacheKey("")
+		raw, ok := key(suite.newRequest())
+		suite.False(ok)
+		suite.Empty(raw)
+
+		raw, ok = key(suite.newBasicAuthRequest())
+		suite.True(ok)
+		suite.Equal("Basic "+suite.basicAuth(), string(raw))
+	})
+
+	suite.Run("Custom", func() {
+		request := suite.newRequest()
+		request.Header.Set("X-Custom", "value")
+		raw, ok := HeaderCacheKey("X-Custom")(request)
+		suite.True(ok)
+		suite.Equal("value", string(raw))
+	})
+}
+
+func (suite *CacheTestSuite) TestMiddleware() {
+	var (
+		validations int
+		cache, err  = NewAuthenticateCache()
+	)
+
+	suite.Require().NoError(err)
+
+	m, err := NewMiddleware(
+		UseAuthenticator(
+			NewAuthenticator(
+				bascule.WithTokenParsers(suite.newAuthorizationParser(WithBasic())),
+				bascule.WithValidators(
+					AsValidator(func(bascule.Token) error {
+						validations++
+						return nil
+					}),
+				),
+				bascule.WithAuthenticateCache(cache),
+			),
+		),
+	)
+
+	suite.Require().NoError(err)
+	h := m.ThenFunc(func(response http.ResponseWriter, request *http.Request) {
+		token, ok := bascule.GetFrom(request)
+		suite.Require().True(ok)
+		suite.assertBasicToken(token)
+	})
+
+	for i := 0; i < 3; i++ {
+		response := httptest.NewRecorder()
+		h.ServeHTTP(response, suite.newBasicAuthRequest())
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	suite.Equal(1, validations)
+	suite.Equal(1, cache.Len())
+}
+
+func TestCache(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
@@ -0,0 +1,384 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// SPIFFEScheme is the URI scheme of a SPIFFE ID.
+	SPIFFEScheme = "spiffe"
+)
+
+var (
+	// ErrInvalidCertificateConfig indicates that a certificate token parser option
+	// was given an invalid value.
+	ErrInvalidCertificateConfig = errors.New("invalid certificate token parser configuration")
+
+	// errUnverifiedCertificate indicates that the TLS server did not verify the client's
+	// certificate and that WithCertificateVerification was not used.
+	errUnverifiedCertificate = errors.New("client certificate was not verified")
+)
+
+// CertificateToken is the interface that tokens created from TLS client
+// certificates implement.  The leaf certificate is always the first certificate
+// in the chain, and the chain is always one that has been verified.
+type CertificateToken interface {
+	// Certificate returns the leaf certificate presented by the client.
+	Certificate() *x509.Certificate
+
+	// Chain returns the verified certificate chain, beginning with the leaf
+	// certificate and ending with the trusted root.
+	Chain() []*x509.Certificate
+
+	// Subject returns the subject of the leaf certificate.
+	Subject() pkix.Name
+
+	// DNSNames returns the DNS subject alternative names of the leaf certificate.
+	DNSNames() []string
+
+	// URIs returns the URI subject alternative names of the leaf certificate.
+	URIs() []*url.URL
+
+	// EmailAddresses returns the email subject alternative names of the leaf certificate.
+	EmailAddresses() []string
+
+	// Fingerprint returns the lowercase, hex-encoded SHA-256 digest of the
+	// leaf certificate's DER encoding.
+	Fingerprint() string
+}
+
+// certificateToken is the internal CertificateToken implementation.  This type
+// also allows certificates to be used with bascule.NewTimeWindowValidator.
+type certificateToken struct {
+	principal    string
+	chain        []*x509.Certificate
+	capabilities []string
+}
+
+var (
+	_ CertificateToken             = (*certificateToken)(nil)
+	_ bascule.CapabilitiesAccessor = (*certificateToken)(nil)
+	_ bascule.ExpirationAccessor   = (*certificateToken)(nil)
+	_ bascule.NotBeforeAccessor    = (*certificateToken)(nil)
+)
+
+func (ct *certificateToken) Principal() string {
+	return ct.principal
+}
+
+func (ct *certificateToken) Certificate() *x509.Certificate {
+	return ct.chain[0]
+}
+
+func (ct *certificateToken) Chain() []*x509.Certificate {
+	return slices.Clone(ct.chain)
+}
+
+func (ct *certificateToken) Subject() pkix.Name {
+	return ct.chain[0].Subject
+}
+
+func (ct *certificateToken) DNSNames() []string {
+	return slices.Clone(ct.chain[0].DNSNames)
+}
+
+func (ct *certificateToken) URIs() []*url.URL {
+	return slices.Clone(ct.chain[0].URIs)
+}
+
+func (ct *certificateToken) EmailAddresses() []string {
+	return slices.Clone(ct.chain[0].EmailAddresses)
+}
+
+func (ct *certificateToken) Fingerprint() string {
+	digest := sha256.Sum256(ct.chain[0].Raw)
+	return hex.EncodeToString(digest[:])
+}
+
+func (ct *certificateToken) Capabilities() []string {
+	return slices.Clone(ct.capabilities)
+}
+
+// Expiration returns the NotAfter time of the leaf certificate.
+func (ct *certificateToken) Expiration() time.Time {
+	return ct.chain[0].NotAfter
+}
+
+// NotBefore returns the NotBefore time of the leaf certificate.
+func (ct *certificateToken) NotBefore() time.Time {
+	return ct.chain[0].NotBefore
+}
+
+// CertificatePrincipalFunc extracts a principal from a client certificate.  If the
+// certificate doesn't have the expected field, this closure must return false.
+type CertificatePrincipalFunc func(*x509.Certificate) (string, bool)
+
+// PrincipalFromCommonName is a CertificatePrincipalFunc that uses the subject's common name.
+func PrincipalFromCommonName(c *x509.Certificate) (string, bool) {
+	return c.Subject.CommonName, len(c.Subject.CommonName) > 0
+}
+
+// PrincipalFromDNSName is a CertificatePrincipalFunc that uses the first DNS subject
+// alternative name.
+func PrincipalFromDNSName(c *x509.Certificate) (string, bool) {
+	if len(c.DNSNames) > 0 {
+		return c.DNSNames[0], true
+	}
+
+	return "", false
+}
+
+// PrincipalFromURI is a CertificatePrincipalFunc that uses the first URI subject
+// alternative name.
+func PrincipalFromURI(c *x509.Certificate) (string, bool) {
+	if len(c.URIs) > 0 {
+		return c.URIs[0].String(), true
+	}
+
+	return "", false
+}
+
+// PrincipalFromEmail is a CertificatePrincipalFunc that uses the first email subject
+// alternative name.
+func PrincipalFromEmail(c *x509.Certificate) (string, bool) {
+	if len(c.EmailAddresses) > 0 {
+		return c.EmailAddresses[0], true
+	}
+
+	return "", false
+}
+
+// PrincipalFromSPIFFEID is a CertificatePrincipalFunc that uses the SPIFFE ID of the
+// certificate, i.e. the first URI subject alternative name with the spiffe scheme
+// and a trust domain.
+func PrincipalFromSPIFFEID(c *x509.Certificate) (string, bool) {
+	for _, u := range c.URIs {
+		if u.Scheme == SPIFFEScheme && len(u.Host) > 0 {
+			return u.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// CertificateCapabilitiesFunc produces capabilities for a client certificate.  The
+// principal is the one extracted from the certificate.
+type CertificateCapabilitiesFunc func(c *x509.Certificate, principal string) []string
+
+// CapabilitiesFromMap returns a CertificateCapabilitiesFunc that looks up capabilities
+// by principal in a mapping table.  The map is copied, so subsequent changes to the
+// given map do not affect the returned closure.
+func CapabilitiesFromMap(m map[string][]string) CertificateCapabilitiesFunc {
+	table := make(map[string][]string, len(m))
+	for principal, caps := range m {
+		table[principal] = slices.Clone(caps)
+	}
+
+	return func(_ *x509.Certificate, principal string) []string {
+		return table[principal]
+	}
+}
+
+// CapabilitiesFromExtension returns a CertificateCapabilitiesFunc that reads
+// capabilities from the certificate extension with the given OID.  The extension's
+// value must be a DER-encoded SEQUENCE OF UTF8String.  Certificates without the
+// extension, or with an extension that cannot be decoded, have no capabilities
+// from this closure.
+func CapabilitiesFromExtension(oid asn1.ObjectIdentifier) CertificateCapabilitiesFunc {
+	oid = slices.Clone(oid)
+	return func(c *x509.Certificate, _ string) (caps []string) {
+		for _, ext := range c.Extensions {
+			if ext.Id.Equal(oid) {
+				if rest, err := asn1.Unmarshal(ext.Value, &caps); err != nil || len(rest) > 0 {
+					caps = nil
+				}
+
+				break
+			}
+		}
+
+		return
+	}
+}
+
+// CertificateTokenParserOption is a configurable option for a CertificateTokenParser.
+type CertificateTokenParserOption interface {
+	apply(*CertificateTokenParser) error
+}
+
+type certificateTokenParserOptionFunc func(*CertificateTokenParser) error
+
+func (ctpof certificateTokenParserOptionFunc) apply(ctp *CertificateTokenParser) error {
+	return ctpof(ctp)
+}
+
+// WithCertificatePrincipal sets the strategies used to extract a principal from the
+// leaf certificate.  Multiple invocations of this option are cumulative.  Each
+// strategy is tried in order, and the first principal found is used.  If this option
+// is not supplied, PrincipalFromCommonName is used.
+func WithCertificatePrincipal(pfs ...CertificatePrincipalFunc) CertificateTokenParserOption {
+	return certificateTokenParserOptionFunc(func(ctp *CertificateTokenParser) error {
+		for _, pf := range pfs {
+			if pf == nil {
+				return ErrInvalidCertificateConfig
+			}
+		}
+
+		ctp.principals = append(ctp.principals, pfs...)
+		return nil
+	})
+}
+
+// WithCertificateCapabilities sets the strategies used to produce capabilities for a
+// certificate.  Multiple invocations of this option are cumulative.  A token's
+// capabilities are the union of the capabilities from each strategy, in order.
+func WithCertificateCapabilities(cfs ...CertificateCapabilitiesFunc) CertificateTokenParserOption {
+	return certificateTokenParserOptionFunc(func(ctp *CertificateTokenParser) error {
+		for _, cf := range cfs {
+			if cf == nil {
+				return ErrInvalidCertificateConfig
+			}
+		}
+
+		ctp.capabilities = append(ctp.capabilities, cfs...)
+		return nil
+	})
+}
+
+// WithCertificateVerification enables verification of the client's certificate chain
+// against the given pool of roots.  Any intermediate certificates presented by the
+// client are used to build the chain, which must be valid for client authentication.
+// This is useful when the TLS server is configured to request, but not verify, client
+// certificates, or when a handler requires a stricter set of roots than the server.
+//
+// If this option is not supplied, the parser relies on the chains verified by the
+// TLS server, i.e. tls.ConnectionState.VerifiedChains.
+func WithCertificateVerification(roots *x509.CertPool) CertificateTokenParserOption {
+	return certificateTokenParserOptionFunc(func(ctp *CertificateTokenParser) error {
+		if roots == nil {
+			return ErrInvalidCertificateConfig
+		}
+
+		ctp.roots = roots
+		return nil
+	})
+}
+
+// CertificateTokenParser is a bascule.TokenParser that produces tokens from the TLS
+// client certificates of an HTTP request.  Tokens produced by this parser implement
+// CertificateToken, bascule.CapabilitiesAccessor, bascule.ExpirationAccessor, and
+// bascule.NotBeforeAccessor.
+type CertificateTokenParser struct {
+	principals   []CertificatePrincipalFunc
+	capabilities []CertificateCapabilitiesFunc
+	roots        *x509.CertPool
+}
+
+// NewCertificateTokenParser constructs a CertificateTokenParser from a set of options.
+func NewCertificateTokenParser(opts ...CertificateTokenParserOption) (*CertificateTokenParser, error) {
+	ctp := new(CertificateTokenParser)
+	for _, o := range opts {
+		if err := o.apply(ctp); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ctp.principals) == 0 {
+		ctp.principals = []CertificatePrincipalFunc{PrincipalFromCommonName}
+	}
+
+	return ctp, nil
+}
+
+// Parse creates a token from the request's peer certificates.
+//
+// If the request was not made over TLS or the client presented no certificates, this
+// method returns bascule.ErrMissingCredentials.
+//
+// The certificates must have been verified, either by this parser when
+// WithCertificateVerification is used or by the TLS server otherwise.  This parser
+// fails closed:  if no verified chain is available, as is the case with
+// tls.RequestClientCert or tls.RequireAnyClientCert, the returned error has
+// bascule.ErrBadCredentials in its chain.  The same is true if no principal can
+// be extracted from the leaf certificate.
+func (ctp *CertificateTokenParser) Parse(_ context.Context, source *http.Request) (bascule.Token, error) {
+	if source.TLS == nil || len(source.TLS.PeerCertificates) == 0 {
+		return nil, bascule.ErrMissingCredentials
+	}
+
+	chain, err := ctp.verifiedChain(source.TLS.PeerCertificates, source.TLS.VerifiedChains)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", bascule.ErrBadCredentials, err)
+	}
+
+	leaf := chain[0]
+
+	ct := &certificateToken{
+		chain: chain,
+	}
+
+	var found bool
+	for i := 0; !found && i < len(ctp.principals); i++ {
+		ct.principal, found = ctp.principals[i](leaf)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: no principal in client certificate", bascule.ErrBadCredentials)
+	}
+
+	for _, cf := range ctp.capabilities {
+		for _, c := range cf(leaf, ct.principal) {
+			if !slices.Contains(ct.capabilities, c) {
+				ct.capabilities = append(ct.capabilities, c)
+			}
+		}
+	}
+
+	return ct, nil
+}
+
+// verifiedChain returns the chain that tokens are built from.  If verification is
+// enabled, the peer certificates are verified against the configured roots.  Otherwise,
+// the first chain verified by the TLS server is used.
+func (ctp *CertificateTokenParser) verifiedChain(peers []*x509.Certificate, verified [][]*x509.Certificate) ([]*x509.Certificate, error) {
+	if ctp.roots == nil {
+		if len(verified) == 0 || len(verified[0]) == 0 {
+			return nil, errUnverifiedCertificate
+		}
+
+		return slices.Clone(verified[0]), nil
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range peers[1:] {
+		intermediates.AddCert(c)
+	}
+
+	chains, err := peers[0].Verify(x509.VerifyOptions{
+		Roots:         ctp.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return chains[0], nil
+}
@@ -0,0 +1,445 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+// capabilitiesOID is the certificate extension used to test CapabilitiesFromExtension.
+var capabilitiesOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+
+// testCertificate is a generated certificate together with its private key.
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+type CertificateTestSuite struct {
+	TestSuite
+
+	serial       int64
+	root         testCertificate
+	intermediate testCertificate
+	otherRoot    testCertificate
+}
+
+// newCertificate creates a certificate from a template.  If parent is nil, the
+// certificate is self-signed.
+func (suite *CertificateTestSuite) newCertificate(template *x509.Certificate, parent *testCertificate) (tc testCertificate) {
+	var err error
+	tc.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	suite.serial++
+	template.SerialNumber = big.NewInt(suite.serial)
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+
+	signerCert, signerKey := template, tc.key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &tc.key.PublicKey, signerKey)
+	suite.Require().NoError(err)
+
+	tc.cert, err = x509.ParseCertificate(der)
+	suite.Require().NoError(err)
+	return
+}
+
+func (suite *CertificateTestSuite) newCA(name string, parent *testCertificate) testCertificate {
+	return suite.newCertificate(
+		&x509.Certificate{
+			Subject:               pkix.Name{CommonName: name},
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		},
+		parent,
+	)
+}
+
+// newClient creates a client certificate signed by the intermediate CA.
+func (suite *CertificateTestSuite) newClient(template *x509.Certificate) testCertificate {
+	if template.ExtKeyUsage == nil {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	return suite.newCertificate(template, &suite.intermediate)
+}
+
+func (suite *CertificateTestSuite) SetupSuite() {
+	suite.root = suite.newCA("root", nil)
+	suite.intermediate = suite.newCA("intermediate", &suite.root)
+	suite.otherRoot = suite.newCA("other", nil)
+}
+
+func (suite *CertificateTestSuite) newCertificateTokenParser(opts ...CertificateTokenParserOption) *CertificateTokenParser {
+	ctp, err := NewCertificateTokenParser(opts...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(ctp)
+	return ctp
+}
+
+// newTLSRequest creates a request that appears to have arrived over TLS with the given
+// chain.  The TLS server did not verify the chain.
+func (suite *CertificateTestSuite) newTLSRequest(chain ...*x509.Certificate) *http.Request {
+	request := suite.newRequest()
+	request.TLS = &tls.ConnectionState{
+		PeerCertificates: chain,
+	}
+
+	return request
+}
+
+// newVerifiedRequest creates a request that appears to have arrived over TLS with
+// a client certificate that the TLS server verified.
+func (suite *CertificateTestSuite) newVerifiedRequest(client *x509.Certificate) *http.Request {
+	request := suite.newTLSRequest(client, suite.intermediate.cert)
+	request.TLS.VerifiedChains = [][]*x509.Certificate{
+		{client, suite.intermediate.cert, suite.root.cert},
+	}
+
+	return request
+}
+
+// parse sends a request with the given client certificates to a TLS test server,
+// then returns the results of parsing that request on the server.  The server
+// trusts suite.root for client certificates.
+func (suite *CertificateTestSuite) parse(ctp *CertificateTokenParser, clientAuth tls.ClientAuthType, client *testCertificate) (token bascule.Token, err error) {
+	server := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			token, err = ctp.Parse(request.Context(), request)
+			response.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(suite.root.cert)
+	server.TLS = &tls.Config{
+		ClientAuth: clientAuth,
+		ClientCAs:  clientCAs,
+	}
+
+	server.StartTLS()
+	defer server.Close()
+
+	httpClient := server.Client()
+	if client != nil {
+		httpClient.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{
+			{
+				Certificate: [][]byte{client.cert.Raw, suite.intermediate.cert.Raw},
+				PrivateKey:  client.key,
+			},
+		}
+	}
+
+	response, clientErr := httpClient.Get(server.URL)
+	suite.Require().NoError(clientErr)
+	response.Body.Close()
+	suite.Equal(http.StatusOK, response.StatusCode)
+	return
+}
+
+func (suite *CertificateTestSuite) TestInvalidConfig() {
+	testCases := []struct {
+		name   string
+		option CertificateTokenParserOption
+	}{
+		{name: "NilPrincipal", option: WithCertificatePrincipal(PrincipalFromEmail, nil)},
+		{name: "NilCapabilities", option: WithCertificateCapabilities(nil)},
+		{name: "NilRoots", option: WithCertificateVerification(nil)},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			ctp, err := NewCertificateTokenParser(testCase.option)
+			suite.ErrorIs(err, ErrInvalidCertificateConfig)
+			suite.Nil(ctp)
+		})
+	}
+}
+
+func (suite *CertificateTestSuite) TestMissing() {
+	ctp := suite.newCertificateTokenParser()
+
+	suite.Run("NoTLS", func() {
+		token, err := ctp.Parse(context.Background(), suite.newRequest())
+		suite.ErrorIs(err, bascule.ErrMissingCredentials)
+		suite.Nil(token)
+	})
+
+	suite.Run("NoCertificate", func() {
+		token, err := suite.parse(ctp, tls.VerifyClientCertIfGiven, nil)
+		suite.ErrorIs(err, bascule.ErrMissingCredentials)
+		suite.Nil(token)
+	})
+}
+
+func (suite *CertificateTestSuite) TestUnverified() {
+	var (
+		ctp        = suite.newCertificateTokenParser()
+		selfSigned = suite.newCertificate(
+			&x509.Certificate{
+				Subject:     pkix.Name{CommonName: "admin"},
+				KeyUsage:    x509.KeyUsageDigitalSignature,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			},
+			nil,
+		)
+	)
+
+	suite.Run("SelfSigned", func() {
+		token, err := suite.parse(ctp, tls.RequestClientCert, &selfSigned)
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.Nil(token)
+	})
+
+	suite.Run("RequireAny", func() {
+		token, err := suite.parse(ctp, tls.RequireAnyClientCert, &selfSigned)
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.Nil(token)
+	})
+
+	suite.Run("NoVerifiedChains", func() {
+		client := suite.newClient(&x509.Certificate{Subject: pkix.Name{CommonName: "device-123"}})
+		token, err := ctp.Parse(context.Background(), suite.newTLSRequest(client.cert, suite.intermediate.cert))
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.Nil(token)
+	})
+}
+
+func (suite *CertificateTestSuite) TestToken() {
+	var (
+		spiffeID, _ = url.Parse("spiffe://example.org/device/123")
+
+		client = suite.newClient(&x509.Certificate{
+			Subject:        pkix.Name{CommonName: "device-123", Organization: []string{"Example"}},
+			DNSNames:       []string{"device-123.example.org"},
+			EmailAddresses: []string{"device-123@example.org"},
+			URIs:           []*url.URL{spiffeID},
+		})
+
+		token, err = suite.parse(suite.newCertificateTokenParser(), tls.VerifyClientCertIfGiven, &client)
+	)
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(token)
+	suite.Equal("device-123", token.Principal())
+
+	var ct CertificateToken
+	suite.Require().True(bascule.TokenAs(token, &ct))
+	suite.True(client.cert.Equal(ct.Certificate()))
+	suite.Require().Len(ct.Chain(), 3)
+	suite.True(suite.intermediate.cert.Equal(ct.Chain()[1]))
+	suite.True(suite.root.cert.Equal(ct.Chain()[2]))
+	suite.Equal("device-123", ct.Subject().CommonName)
+	suite.Equal([]string{"Example"}, ct.Subject().Organization)
+	suite.Equal([]string{"device-123.example.org"}, ct.DNSNames())
+	suite.Equal([]string{"device-123@example.org"}, ct.EmailAddresses())
+	suite.Equal([]*url.URL{spiffeID}, ct.URIs())
+
+	digest := sha256.Sum256(client.cert.Raw)
+	suite.Equal(hex.EncodeToString(digest[:]), ct.Fingerprint())
+
+	exp, ok := bascule.GetExpiration(token)
+	suite.True(ok)
+	suite.Equal(client.cert.NotAfter, exp)
+
+	nbf, ok := bascule.GetNotBefore(token)
+	suite.True(ok)
+	suite.Equal(client.cert.NotBefore, nbf)
+
+	caps, ok := bascule.GetCapabilities(token)
+	suite.True(ok)
+	suite.Empty(caps)
+}
+
+func (suite *CertificateTestSuite) TestPrincipal() {
+	var (
+		website, _  = url.Parse("https://example.org/device/123")
+		spiffeID, _ = url.Parse("spiffe://example.org/device/123")
+		noDomain, _ = url.Parse("spiffe:device")
+
+		full = suite.newClient(&x509.Certificate{
+			Subject:        pkix.Name{CommonName: "device-123"},
+			DNSNames:       []string{"device-123.example.org", "other.example.org"},
+			EmailAddresses: []string{"device-123@example.org"},
+			URIs:           []*url.URL{website, noDomain, spiffeID},
+		})
+
+		empty = suite.newClient(&x509.Certificate{})
+	)
+
+	testCases := []struct {
+		name       string
+		principals []CertificatePrincipalFunc
+		expected   string
+	}{
+		{name: "CommonName", principals: []CertificatePrincipalFunc{PrincipalFromCommonName}, expected: "device-123"},
+		{name: "DNSName", principals: []CertificatePrincipalFunc{PrincipalFromDNSName}, expected: "device-123.example.org"},
+		{name: "URI", principals: []CertificatePrincipalFunc{PrincipalFromURI}, expected: website.String()},
+		{name: "Email", principals: []CertificatePrincipalFunc{PrincipalFromEmail}, expected: "device-123@example.org"},
+		{name: "SPIFFEID", principals: []CertificatePrincipalFunc{PrincipalFromSPIFFEID}, expected: spiffeID.String()},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			ctp := suite.newCertificateTokenParser(WithCertificatePrincipal(testCase.principals...))
+
+			token, err := ctp.Parse(context.Background(), suite.newVerifiedRequest(full.cert))
+			suite.Require().NoError(err)
+			suite.Equal(testCase.expected, token.Principal())
+
+			token, err = ctp.Parse(context.Background(), suite.newVerifiedRequest(empty.cert))
+			suite.ErrorIs(err, bascule.ErrBadCredentials)
+			suite.Nil(token)
+		})
+	}
+
+	suite.Run("Fallback", func() {
+		ctp := suite.newCertificateTokenParser(
+			WithCertificatePrincipal(PrincipalFromSPIFFEID),
+			WithCertificatePrincipal(PrincipalFromCommonName),
+		)
+
+		token, err := ctp.Parse(context.Background(), suite.newVerifiedRequest(full.cert))
+		suite.Require().NoError(err)
+		suite.Equal(spiffeID.String(), token.Principal())
+
+		noSPIFFE := suite.newClient(&x509.Certificate{
+			Subject: pkix.Name{CommonName: "device-456"},
+			URIs:    []*url.URL{website, noDomain},
+		})
+
+		token, err = ctp.Parse(context.Background(), suite.newVerifiedRequest(noSPIFFE.cert))
+		suite.Require().NoError(err)
+		suite.Equal("device-456", token.Principal())
+	})
+}
+
+func (suite *CertificateTestSuite) TestCapabilities() {
+	extensionValue, err := asn1.Marshal([]string{"device:read", "device:write"})
+	suite.Require().NoError(err)
+
+	var (
+		withExtension = suite.newClient(&x509.Certificate{
+			Subject: pkix.Name{CommonName: "device-123"},
+			ExtraExtensions: []pkix.Extension{
+				{Id: capabilitiesOID, Value: extensionValue},
+			},
+		})
+
+		badExtension = suite.newClient(&x509.Certificate{
+			Subject: pkix.Name{CommonName: "device-456"},
+			ExtraExtensions: []pkix.Extension{
+				{Id: capabilitiesOID, Value: []byte("not DER")},
+			},
+		})
+
+		mapping = map[string][]string{
+			"device-123": {"device:write", "device:admin"},
+			"device-456": {"device:read"},
+		}
+
+		ctp = suite.newCertificateTokenParser(
+			WithCertificateCapabilities(CapabilitiesFromExtension(capabilitiesOID)),
+			WithCertificateCapabilities(CapabilitiesFromMap(mapping)),
+		)
+	)
+
+	// changes to the mapping must not affect the parser
+	mapping["device-456"] = []string{"device:admin"}
+
+	token, err := ctp.Parse(context.Background(), suite.newVerifiedRequest(withExtension.cert))
+	suite.Require().NoError(err)
+	caps, ok := bascule.GetCapabilities(token)
+	suite.True(ok)
+	suite.Equal([]string{"device:read", "device:write", "device:admin"}, caps)
+
+	token, err = ctp.Parse(context.Background(), suite.newVerifiedRequest(badExtension.cert))
+	suite.Require().NoError(err)
+	caps, ok = bascule.GetCapabilities(token)
+	suite.True(ok)
+	suite.Equal([]string{"device:read"}, caps)
+}
+
+func (suite *CertificateTestSuite) TestVerification() {
+	var (
+		roots = x509.NewCertPool()
+		other = x509.NewCertPool()
+
+		client    = suite.newClient(&x509.Certificate{Subject: pkix.Name{CommonName: "device-123"}})
+		serverUse = suite.newClient(&x509.Certificate{
+			Subject:     pkix.Name{CommonName: "server"},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+	)
+
+	roots.AddCert(suite.root.cert)
+	other.AddCert(suite.otherRoot.cert)
+
+	suite.Run("Success", func() {
+		// the server does not verify, so the parser must
+		token, err := suite.parse(suite.newCertificateTokenParser(WithCertificateVerification(roots)), tls.RequestClientCert, &client)
+		suite.Require().NoError(err)
+		suite.Equal("device-123", token.Principal())
+
+		var ct CertificateToken
+		suite.Require().True(bascule.TokenAs(token, &ct))
+		suite.Require().Len(ct.Chain(), 3)
+		suite.True(suite.root.cert.Equal(ct.Chain()[2]))
+		suite.Equal("device-123", token.Principal())
+	})
+
+	suite.Run("UnknownRoot", func() {
+		token, err := suite.parse(suite.newCertificateTokenParser(WithCertificateVerification(other)), tls.RequestClientCert, &client)
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.Nil(token)
+	})
+
+	suite.Run("MissingIntermediate", func() {
+		ctp := suite.newCertificateTokenParser(WithCertificateVerification(roots))
+		token, err := ctp.Parse(context.Background(), suite.newTLSRequest(client.cert))
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.Nil(token)
+	})
+
+	suite.Run("NotClientAuth", func() {
+		ctp := suite.newCertificateTokenParser(WithCertificateVerification(roots))
+		token, err := ctp.Parse(context.Background(), suite.newTLSRequest(serverUse.cert, suite.intermediate.cert))
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+		suite.Nil(token)
+	})
+}
+
+func TestCertificate(t *testing.T) {
+	suite.Run(t, new(CertificateTestSuite))
+}
@@ -6,6 +6,7 @@ package basculehttp
 import (
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 )
 
@@ -196,6 +197,26 @@ func (cp *ChallengeParameters) String() string {
 	return o.String()
 }
 
+// merge returns a distinct copy of these parameters with the parameters from
+// other added.  Parameters in other take precedence, except that an existing
+// realm is never replaced.
+func (cp *ChallengeParameters) merge(other *ChallengeParameters) (merged ChallengeParameters) {
+	merged.realm = cp.realm
+	if len(merged.realm) == 0 {
+		merged.realm = other.realm
+	}
+
+	for i := 0; i < len(cp.names); i++ {
+		merged.unsafeSet(cp.names[i], cp.values[i])
+	}
+
+	for i := 0; i < len(other.names); i++ {
+		merged.unsafeSet(other.names[i], other.values[i])
+	}
+
+	return
+}
+
 // NewChallengeParameters creates a ChallengeParameters from a sequence of name/value pairs.
 // The strings are expected to be in name1, value1, name2, value2, ..., nameN, valueN  sequence.
 // If the number of strings is odd, this method returns an error.  If any duplicate names
@@ -274,6 +295,27 @@ func (chs Challenges) Append(ch ...Challenge) Challenges {
 	return append(chs, ch...)
 }
 
+// merge returns a distinct set of challenges that combines this set with more.
+// A challenge in more whose scheme matches a challenge in this set, ignoring case,
+// has its parameters merged into that challenge.  Any other challenge in more is
+// appended.
+func (chs Challenges) merge(more Challenges) Challenges {
+	merged := slices.Clone(chs)
+	for _, ch := range more {
+		i := slices.IndexFunc(merged, func(existing Challenge) bool {
+			return existing.Scheme.lower() == ch.Scheme.lower()
+		})
+
+		if i < 0 {
+			merged = append(merged, ch)
+		} else {
+			merged[i].Parameters = merged[i].Parameters.merge(&ch.Parameters)
+		}
+	}
+
+	return merged
+}
+
 // WriteHeader write one WWWAuthenticateHeader for each challenge in this
 // set.
 //
@@ -306,3 +348,33 @@ func (chs Challenges) WriteHeaderCustom(dst http.Header, name string) error {
 
 	return nil
 }
+
+// ChallengeError is an error that carries the challenges to be sent with a
+// StatusUnauthorized response.  Token parsers may return this error to request
+// challenges specific to their scheme, e.g. an RFC 6750 error code for Bearer tokens.
+//
+// When the Middleware writes a StatusUnauthorized response, the challenges from any
+// ChallengeError in the error's chain are written in addition to those configured
+// via WithChallenges.  For a scheme that appears in both, the configured challenge is
+// written with this error's parameters added to it.
+type ChallengeError struct {
+	// Challenges are the challenges associated with this error.
+	Challenges Challenges
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Unwrap returns the underlying error.
+func (ce *ChallengeError) Unwrap() error {
+	return ce.Err
+}
+
+// Error returns the underlying error's text.
+func (ce *ChallengeError) Error() string {
+	if ce.Err == nil {
+		return "authentication challenge"
+	}
+
+	return ce.Err.Error()
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
 )
 
 type ChallengeTestSuite struct {
@@ -327,9 +328,74 @@ func (suite *ChallengeTestSuite) testChallengesInvalid() {
 	}
 }
 
+func (suite *ChallengeTestSuite) testChallengesMerge() {
+	var (
+		configured = Challenges{
+			NewBasicChallenge("basic@server.com", true),
+			Challenge{
+				Scheme:     SchemeBearer,
+				Parameters: suite.newValidParameters(RealmParameter, "bearer@server.com", "scope", "read"),
+			},
+		}
+
+		fromError = Challenges{
+			Challenge{
+				Scheme:     Scheme("bearer"),
+				Parameters: suite.newValidParameters(RealmParameter, "ignored", BearerErrorParameter, BearerInvalidToken),
+			},
+			Challenge{
+				Scheme: Scheme("Custom"),
+			},
+		}
+
+		merged = configured.merge(fromError)
+		header = make(http.Header)
+	)
+
+	suite.NoError(merged.WriteHeader(header))
+	suite.Equal(
+		[]string{
+			`Basic realm="basic@server.com", charset="UTF-8"`,
+			`Bearer realm="bearer@server.com", scope="read", error="invalid_token"`,
+			`Custom`,
+		},
+		header.Values(WWWAuthenticateHeader),
+	)
+
+	// the original challenges must not be modified
+	header = make(http.Header)
+	suite.NoError(configured.WriteHeader(header))
+	suite.Equal(
+		[]string{
+			`Basic realm="basic@server.com", charset="UTF-8"`,
+			`Bearer realm="bearer@server.com", scope="read"`,
+		},
+		header.Values(WWWAuthenticateHeader),
+	)
+}
+
 func (suite *ChallengeTestSuite) TestChallenges() {
 	suite.Run("Valid", suite.testChallengesValid)
 	suite.Run("Invalid", suite.testChallengesInvalid)
+	suite.Run("Merge", suite.testChallengesMerge)
+}
+
+func (suite *ChallengeTestSuite) TestChallengeError() {
+	suite.Run("NoError", func() {
+		ce := &ChallengeError{}
+		suite.Equal("authentication challenge", ce.Error())
+		suite.NoError(ce.Unwrap())
+	})
+
+	suite.Run("Error", func() {
+		ce := &ChallengeError{
+			Challenges: Challenges{NewBearerChallenge("")},
+			Err:        bascule.ErrMissingCredentials,
+		}
+
+		suite.Equal(bascule.ErrMissingCredentials.Error(), ce.Error())
+		suite.ErrorIs(ce, bascule.ErrMissingCredentials)
+	})
 }
 
 func TestChallenge(t *testing.T) {
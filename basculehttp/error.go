@@ -24,20 +24,34 @@ type ErrorStatusCoder func(request *http.Request, err error) int
 // (2) If any error in the chain provides a 'StatusCode() int' method, the result
 // from that method is returned.
 //
-// (3) If err has bascule.ErrMissingCredentials in its chain, this function returns
+// (3) If err has bascule.ErrPanic in its chain, this function returns
+// http.StatusInternalServerError.
+//
+// (4) If err has bascule.ErrLockedOut in its chain, this function returns
+// http.StatusTooManyRequests.
+//
+// (5) If err has bascule.ErrMissingCredentials in its chain, this function returns
 // http.StatusUnauthorized.
 //
-// (3) If err has bascule.ErrBadCredentials in its chain, this function returns
+// (6) If err has bascule.ErrBadCredentials in its chain, this function returns
 // http.StatusUnauthorized.
 //
-// (4) If err has bascule.ErrUnauthorized in its chain, this function returns
+// (7) If err has bascule.ErrTokenExpired, bascule.ErrTokenNotYetValid, or
+// bascule.ErrRevokedCredentials in its chain, this function returns http.StatusUnauthorized.
+//
+// (8) If err has bascule.ErrUnauthorized in its chain, this function returns
 // http.StatusForbidden.
 //
-// (5) If err has bascule.ErrInvalidCredentials in its chain, this function returns
+// (9) If err has bascule.ErrInvalidCredentials in its chain, this function returns
 // http.StatusBadRequest.
 //
-// (6) Otherwise, this method returns 0 to indicate that it doesn't know how to
+// (10) Otherwise, this method returns 0 to indicate that it doesn't know how to
 // produce a status code from the error.
+//
+// Token parsers distinguish malformed credentials from credentials that fail
+// verification by way of these sentinels.  For example, basculejwt reports a value
+// that isn't a JWT with bascule.ErrInvalidCredentials, resulting in a 400, and a JWT
+// with an invalid signature with bascule.ErrBadCredentials, resulting in a 401.
 func DefaultErrorStatusCoder(_ *http.Request, err error) int {
 	type statusCoder interface {
 		StatusCode() int
@@ -52,12 +66,27 @@ func DefaultErrorStatusCoder(_ *http.Request, err error) int {
 	case errors.As(err, &sc):
 		return sc.StatusCode()
 
+	case errors.Is(err, bascule.ErrPanic):
+		return http.StatusInternalServerError
+
+	case errors.Is(err, bascule.ErrLockedOut):
+		return http.StatusTooManyRequests
+
 	case errors.Is(err, bascule.ErrMissingCredentials):
 		return http.StatusUnauthorized
 
 	case errors.Is(err, bascule.ErrBadCredentials):
 		return http.StatusUnauthorized
 
+	case errors.Is(err, bascule.ErrTokenExpired):
+		return http.StatusUnauthorized
+
+	case errors.Is(err, bascule.ErrTokenNotYetValid):
+		return http.StatusUnauthorized
+
+	case errors.Is(err, bascule.ErrRevokedCredentials):
+		return http.StatusUnauthorized
+
 	case errors.Is(err, bascule.ErrUnauthorized):
 		return http.StatusForbidden
 
@@ -80,6 +109,15 @@ func DefaultErrorMarshaler(_ *http.Request, err error) (contentType string, cont
 	return
 }
 
+// SafeErrorMarshaler returns a plaintext, client-facing description of the error
+// as given by bascule.SafeMessage.  Unlike DefaultErrorMarshaler, this strategy never
+// exposes the text of internal errors.
+func SafeErrorMarshaler(_ *http.Request, err error) (contentType string, content []byte, marshalErr error) {
+	contentType = "text/plain; charset=utf-8"
+	content = []byte(bascule.SafeMessage(err))
+	return
+}
+
 type statusCodeError struct {
 	error
 	statusCode int
@@ -5,12 +5,16 @@ package basculehttp
 
 import (
 	"errors"
+	"fmt"
 	"mime"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/suite"
 	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculejwt"
 )
 
 type ErrorTestSuite struct {
@@ -24,6 +28,20 @@ func (suite *ErrorTestSuite) TestDefaultErrorStatusCoder() {
 		)
 	})
 
+	suite.Run("ErrPanic", func() {
+		suite.Equal(
+			http.StatusInternalServerError,
+			DefaultErrorStatusCoder(nil, &bascule.PanicError{Component: "test", Value: "expected"}),
+		)
+	})
+
+	suite.Run("ErrLockedOut", func() {
+		suite.Equal(
+			http.StatusTooManyRequests,
+			DefaultErrorStatusCoder(nil, &bascule.LockoutError{RetryAfter: time.Minute}),
+		)
+	})
+
 	suite.Run("ErrMissingCredentials", func() {
 		suite.Equal(
 			http.StatusUnauthorized,
@@ -38,6 +56,27 @@ func (suite *ErrorTestSuite) TestDefaultErrorStatusCoder() {
 		)
 	})
 
+	suite.Run("ErrTokenExpired", func() {
+		suite.Equal(
+			http.StatusUnauthorized,
+			DefaultErrorStatusCoder(nil, bascule.ErrTokenExpired),
+		)
+	})
+
+	suite.Run("ErrTokenNotYetValid", func() {
+		suite.Equal(
+			http.StatusUnauthorized,
+			DefaultErrorStatusCoder(nil, bascule.ErrTokenNotYetValid),
+		)
+	})
+
+	suite.Run("ErrRevokedCredentials", func() {
+		suite.Equal(
+			http.StatusUnauthorized,
+			DefaultErrorStatusCoder(nil, bascule.ErrRevokedCredentials),
+		)
+	})
+
 	suite.Run("ErrUnauthorized", func() {
 		suite.Equal(
 			http.StatusForbidden,
@@ -52,6 +91,23 @@ func (suite *ErrorTestSuite) TestDefaultErrorStatusCoder() {
 		)
 	})
 
+	suite.Run("JWT", func() {
+		suite.Equal(
+			http.StatusBadRequest,
+			DefaultErrorStatusCoder(nil, basculejwt.ClassifyError(jwt.ErrInvalidJWT())),
+		)
+
+		suite.Equal(
+			http.StatusUnauthorized,
+			DefaultErrorStatusCoder(nil, basculejwt.ClassifyError(jwt.ErrInvalidAudience())),
+		)
+
+		suite.Equal(
+			http.StatusUnauthorized,
+			DefaultErrorStatusCoder(nil, basculejwt.ClassifyError(jwt.ErrTokenExpired())),
+		)
+	})
+
 	suite.Run("StatusCoder", func() {
 		suite.Equal(
 			317,
@@ -79,6 +135,23 @@ func (suite *ErrorTestSuite) TestDefaultErrorStatusCoder() {
 	})
 }
 
+func (suite *ErrorTestSuite) TestSafeErrorMarshaler() {
+	contentType, content, marshalErr := SafeErrorMarshaler(
+		nil,
+		&bascule.AuthError{
+			Stage: bascule.StageValidate,
+			Err:   fmt.Errorf("internal detail: %w", bascule.ErrBadCredentials),
+		},
+	)
+
+	suite.Require().NoError(marshalErr)
+	suite.Equal("bad credentials", string(content))
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	suite.Require().NoError(err)
+	suite.Equal("text/plain", mediaType)
+}
+
 func (suite *ErrorTestSuite) TestDefaultErrorMarshaler() {
 	contentType, content, marshalErr := DefaultErrorMarshaler(
 		nil,
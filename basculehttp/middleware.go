@@ -4,6 +4,7 @@
 package basculehttp
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -12,6 +13,12 @@ import (
 	"go.uber.org/multierr"
 )
 
+const (
+	// RetryAfterHeader is the HTTP header that tells a client how many seconds
+	// to wait before retrying a request.
+	RetryAfterHeader = "Retry-After"
+)
+
 var (
 	// ErrNoAuthenticator is returned by NewMiddleware to indicate that an Authorizer
 	// was configured without an Authenticator.
@@ -75,6 +82,9 @@ func UseAuthorizer(authorizer *bascule.Authorizer[*http.Request], err error) Mid
 // WithChallenges adds WWW-Authenticate challenges to be used when a StatusUnauthorized is
 // detected.  Multiple invocations of this option are cumulative.  Each challenge results
 // in a separate WWW-Authenticate header, in the order specified by this option.
+//
+// Challenges from the authenticator's token parsers, such as the Bearer challenge of an
+// AuthorizationParser configured with WithBearer, are added automatically after these.
 func WithChallenges(ch ...Challenge) MiddlewareOption {
 	return middlewareOptionFunc(func(m *Middleware) error {
 		m.challenges = m.challenges.Append(ch...)
@@ -100,6 +110,57 @@ func WithErrorMarshaler(em ErrorMarshaler) MiddlewareOption {
 	})
 }
 
+// WithOptionalAuthentication allows requests that present no credentials to proceed
+// as anonymous callers.  When the Authenticator fails with bascule.ErrMissingCredentials,
+// the request continues with bascule.AnonymousToken in its context instead of being
+// rejected.  Any other authentication failure, such as invalid or bad credentials,
+// is still rejected.  This includes aggregate failures, e.g. from TokenParsers.All,
+// that mix missing credentials with any other error.  See bascule.OnlyMissingCredentials.
+//
+// If an Authorizer is configured, it is invoked with the anonymous token.  Use
+// bascule.RequireAuthenticated or bascule.IfAuthenticated to distinguish anonymous
+// callers from authenticated ones.
+func WithOptionalAuthentication() MiddlewareOption {
+	return middlewareOptionFunc(func(m *Middleware) error {
+		m.optional = true
+		return nil
+	})
+}
+
+// Tracer is a hook around the authentication and authorization workflows, typically
+// used to produce trace spans.  Each method must invoke the supplied closure exactly
+// once, passing a context derived from ctx, and return that closure's results.
+//
+// A *basculeotel.Tracer implements this interface.  Defining the hook here keeps
+// OpenTelemetry out of the dependencies of this package.
+type Tracer interface {
+	// TraceAuthenticate wraps authentication of the given request.
+	TraceAuthenticate(ctx context.Context, request any, authenticate func(context.Context) (bascule.Token, error)) (bascule.Token, error)
+
+	// TraceAuthorize wraps authorization of the given request for a token.
+	TraceAuthorize(ctx context.Context, request any, token bascule.Token, authorize func(context.Context) error) error
+}
+
+// WithTracing sets the Tracer used around the authentication and authorization
+// workflows, e.g. a *basculeotel.Tracer.  A nil Tracer disables tracing.
+func WithTracing(t Tracer) MiddlewareOption {
+	return UseTracing(t, nil)
+}
+
+// UseTracing is a variant of WithTracing that allows a caller to nest function
+// calls a little easier.  The output of basculeotel.NewTracer can be passed
+// directly to this option.
+func UseTracing(t Tracer, err error) MiddlewareOption {
+	return middlewareOptionFunc(func(m *Middleware) error {
+		if err != nil {
+			return err
+		}
+
+		m.tracer = t
+		return nil
+	})
+}
+
 // Middleware is an immutable HTTP workflow that can decorate multiple handlers.
 //
 // A Middleware can have either or both of an Authenticator, which creates
@@ -118,6 +179,9 @@ func WithErrorMarshaler(em ErrorMarshaler) MiddlewareOption {
 // NewMiddleware returns an error.  An authenticator is required in order to
 // create tokens.
 //
+// If WithOptionalAuthentication is used, a request without credentials is
+// treated as an anonymous caller rather than being rejected.
+//
 // Finally, if neither an authenticator or an authorizer is supplied,
 // then this Middleware is a noop.  Any attempt to decorate handlers will
 // result in those handlers being returned as is.  This allows a Middleware
@@ -126,6 +190,8 @@ type Middleware struct {
 	authenticator *bascule.Authenticator[*http.Request]
 	authorizer    *bascule.Authorizer[*http.Request]
 	challenges    Challenges
+	tracer        Tracer
+	optional      bool
 
 	errorStatusCoder ErrorStatusCoder
 	errorMarshaler   ErrorMarshaler
@@ -162,11 +228,38 @@ func NewMiddleware(opts ...MiddlewareOption) (m *Middleware, err error) {
 		if m.errorMarshaler == nil {
 			m.errorMarshaler = DefaultErrorMarshaler
 		}
+
+		if m.authenticator != nil {
+			for _, tp := range m.authenticator.TokenParsers() {
+				m.challenges = m.challenges.merge(parserChallenges(tp))
+			}
+		}
 	}
 
 	return
 }
 
+// parserChallenges returns the challenges of a token parser that has a 'Challenges() Challenges'
+// method, such as an *AuthorizationParser.  Decorated parsers are unwrapped to find this method.
+func parserChallenges(tp bascule.TokenParser[*http.Request]) Challenges {
+	for tp != nil {
+		switch p := tp.(type) {
+		case interface{ Challenges() Challenges }:
+			return p.Challenges()
+
+		case interface {
+			Unwrap() bascule.TokenParser[*http.Request]
+		}:
+			tp = p.Unwrap()
+
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // Then produces an http.Handler that uses this Middleware's workflow to protected
 // a given handler.
 func (m *Middleware) Then(protected http.Handler) http.Handler {
@@ -207,10 +300,13 @@ func (m *Middleware) writeRawError(response http.ResponseWriter, err error) {
 }
 
 // writeWorkflowError handles writing an error that came from the bascule workflow to an HTTP request.
-// This will include writing any HTTP challenges if a 401 status is detected.
+// This will include writing any HTTP challenges if a 401 status is detected, including
+// challenges from any *ChallengeError in the error's chain.
 //
 // The defaultCode is used as the response status code if the given error does not supply a StatusCode method.
 //
+// If the error is a *bascule.LockoutError, a Retry-After header is written.
+//
 // If the error supports JSON or text marshaling, that is used for the response body.  Otherwise, a text/plain
 // response with the Error() method's text is used.
 func (m *Middleware) writeWorkflowError(response http.ResponseWriter, request *http.Request, defaultCode int, err error) {
@@ -226,7 +322,18 @@ func (m *Middleware) writeWorkflowError(response http.ResponseWriter, request *h
 	)
 
 	if statusCode == http.StatusUnauthorized {
-		writeErr = m.challenges.WriteHeader(response.Header())
+		challenges := m.challenges
+		var ce *ChallengeError
+		if errors.As(err, &ce) {
+			challenges = challenges.merge(ce.Challenges)
+		}
+
+		writeErr = challenges.WriteHeader(response.Header())
+	}
+
+	var le *bascule.LockoutError
+	if errors.As(err, &le) {
+		response.Header().Set(RetryAfterHeader, strconv.FormatInt(le.RetryAfterSeconds(), 10))
 	}
 
 	if writeErr == nil {
@@ -243,6 +350,28 @@ func (m *Middleware) writeWorkflowError(response http.ResponseWriter, request *h
 	}
 }
 
+// authenticate executes the authentication workflow, with tracing if configured.
+func (m *Middleware) authenticate(ctx context.Context, request *http.Request) (bascule.Token, error) {
+	if m.tracer != nil {
+		return m.tracer.TraceAuthenticate(ctx, request, func(ctx context.Context) (bascule.Token, error) {
+			return m.authenticator.Authenticate(ctx, request)
+		})
+	}
+
+	return m.authenticator.Authenticate(ctx, request)
+}
+
+// authorize executes the authorization workflow, with tracing if configured.
+func (m *Middleware) authorize(ctx context.Context, request *http.Request, token bascule.Token) error {
+	if m.tracer != nil {
+		return m.tracer.TraceAuthorize(ctx, request, token, func(ctx context.Context) error {
+			return m.authorizer.Authorize(ctx, request, token)
+		})
+	}
+
+	return m.authorizer.Authorize(ctx, request, token)
+}
+
 // frontDoor is the internal handler implementation that protects a handler
 // using the bascule workflow.
 type frontDoor struct {
@@ -256,7 +385,11 @@ func (fd *frontDoor) ServeHTTP(response http.ResponseWriter, request *http.Reque
 
 	// an authenticator is is required if we are decorating
 	// if the authenticator was nil, a frontDoor won't get created
-	token, err := fd.authenticator.Authenticate(ctx, request)
+	token, err := fd.authenticate(ctx, request)
+	if err != nil && fd.optional && bascule.OnlyMissingCredentials(err) {
+		token, err = bascule.AnonymousToken{}, nil
+	}
+
 	if err != nil {
 		// by default, failing to parse a token is a malformed request
 		fd.writeWorkflowError(response, request, http.StatusBadRequest, err)
@@ -267,7 +400,7 @@ func (fd *frontDoor) ServeHTTP(response http.ResponseWriter, request *http.Reque
 
 	// the authorizer is optional
 	if fd.authorizer != nil {
-		err = fd.authorizer.Authorize(ctx, request, token)
+		err = fd.authorize(ctx, request, token)
 		if err != nil {
 			fd.writeWorkflowError(response, request, http.StatusForbidden, err)
 			return
@@ -10,9 +10,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/suite"
 	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculeotel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type MiddlewareTestSuite struct {
@@ -292,7 +299,7 @@ func (suite *MiddlewareTestSuite) testBasicAuthChallenge() {
 					bascule.WithAuthenticateListenerFuncs(
 						func(e bascule.AuthenticateEvent[*http.Request]) {
 							suite.assertBasicAuthRequest(e.Source)
-							suite.ErrorIs(bascule.ErrMissingCredentials, e.Err)
+							suite.ErrorIs(e.Err, bascule.ErrMissingCredentials)
 							suite.Nil(e.Token)
 							authenticateEvent = true
 						},
@@ -380,11 +387,384 @@ func (suite *MiddlewareTestSuite) testBasicAuthAuthorizerError() {
 	suite.Equal(expectedErr.Error(), response.Body.String())
 }
 
+func (suite *MiddlewareTestSuite) testBasicAuthLockedOut() {
+	var (
+		m = suite.newMiddleware(
+			WithAuthenticator(
+				suite.newAuthenticator(
+					bascule.WithTokenParsers(
+						suite.newAuthorizationParser(WithBasic()),
+					),
+					bascule.WithValidators(
+						bascule.AsValidator[*http.Request](func(bascule.Token) error {
+							return &bascule.LockoutError{RetryAfter: 90 * time.Second}
+						}),
+					),
+				),
+			),
+			WithChallenges(Challenge{Scheme: SchemeBasic}),
+		)
+
+		response = httptest.NewRecorder()
+		request  = suite.newBasicAuthRequest()
+
+		h = m.ThenFunc(suite.serveHTTPNoCall)
+	)
+
+	h.ServeHTTP(response, request)
+	suite.Equal(http.StatusTooManyRequests, response.Code)
+	suite.Equal("90", response.Header().Get(RetryAfterHeader))
+	suite.Empty(response.Header().Get(WWWAuthenticateHeader))
+}
+
+func (suite *MiddlewareTestSuite) testBasicAuthPanic() {
+	var (
+		panics []*bascule.PanicError
+
+		m = suite.newMiddleware(
+			WithAuthenticator(
+				suite.newAuthenticator(
+					bascule.WithTokenParsers(
+						suite.newAuthorizationParser(WithBasic()),
+					),
+					bascule.WithValidators(
+						bascule.AsValidator[*http.Request](func(bascule.Token) error {
+							panic("expected")
+						}),
+					),
+					bascule.WithAuthenticatePanicRecovery[*http.Request](func(pe *bascule.PanicError) {
+						panics = append(panics, pe)
+					}),
+				),
+			),
+			WithChallenges(Challenge{Scheme: SchemeBasic}),
+		)
+
+		response = httptest.NewRecorder()
+		request  = suite.newBasicAuthRequest()
+
+		h = m.ThenFunc(suite.serveHTTPNoCall)
+	)
+
+	suite.NotPanics(func() {
+		h.ServeHTTP(response, request)
+	})
+
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.Len(panics, 1)
+}
+
 func (suite *MiddlewareTestSuite) TestBasicAuth() {
 	suite.Run("Success", suite.testBasicAuthSuccess)
 	suite.Run("Challenge", suite.testBasicAuthChallenge)
 	suite.Run("Invalid", suite.testBasicAuthInvalid)
 	suite.Run("AuthorizerError", suite.testBasicAuthAuthorizerError)
+	suite.Run("LockedOut", suite.testBasicAuthLockedOut)
+	suite.Run("Panic", suite.testBasicAuthPanic)
+}
+
+// newOptionalMiddleware creates a Middleware with optional basic authentication.
+func (suite *MiddlewareTestSuite) newOptionalMiddleware(opts ...MiddlewareOption) *Middleware {
+	return suite.newMiddleware(
+		append(
+			[]MiddlewareOption{
+				WithAuthenticator(
+					suite.newAuthenticator(
+						bascule.WithTokenParsers(
+							suite.newAuthorizationParser(WithBasic()),
+						),
+					),
+				),
+				WithOptionalAuthentication(),
+				WithChallenges(Challenge{Scheme: SchemeBasic}),
+			},
+			opts...,
+		)...,
+	)
+}
+
+func (suite *MiddlewareTestSuite) testOptionalAuthenticationAnonymous() {
+	var (
+		called bool
+
+		m = suite.newOptionalMiddleware(
+			WithAuthorizer(
+				suite.newAuthorizer(
+					bascule.WithApprovers(
+						bascule.IfAuthenticated[*http.Request](
+							bascule.ApproverFunc[*http.Request](func(context.Context, *http.Request, bascule.Token) error {
+								suite.Fail("approvers should not be called for anonymous callers")
+								return nil
+							}),
+						),
+					),
+				),
+			),
+		)
+
+		response = httptest.NewRecorder()
+		request  = suite.newRequest()
+
+		h = m.ThenFunc(func(response http.ResponseWriter, request *http.Request) {
+			token, ok := bascule.GetFrom(request)
+			suite.Require().True(ok)
+			suite.True(bascule.IsAnonymous(token))
+			suite.Equal(bascule.AnonymousPrincipal, token.Principal())
+			called = true
+			suite.serveHTTPFunc(response, request)
+		})
+	)
+
+	h.ServeHTTP(response, request)
+	suite.assertNormalResponse(response)
+	suite.True(called)
+}
+
+func (suite *MiddlewareTestSuite) testOptionalAuthenticationAuthenticated() {
+	var (
+		m = suite.newOptionalMiddleware()
+
+		response = httptest.NewRecorder()
+		request  = suite.newBasicAuthRequest()
+
+		h = m.ThenFunc(func(response http.ResponseWriter, request *http.Request) {
+			token, ok := bascule.GetFrom(request)
+			suite.Require().True(ok)
+			suite.False(bascule.IsAnonymous(token))
+			suite.assertBasicToken(token)
+			suite.serveHTTPFunc(response, request)
+		})
+	)
+
+	h.ServeHTTP(response, request)
+	suite.assertNormalResponse(response)
+}
+
+func (suite *MiddlewareTestSuite) testOptionalAuthenticationInvalid() {
+	var (
+		m = suite.newOptionalMiddleware()
+
+		response = httptest.NewRecorder()
+		request  = suite.newRequest()
+
+		h = m.ThenFunc(suite.serveHTTPNoCall)
+	)
+
+	request.Header.Set("Authorization", "Basic this is most definitely not a valid basic auth string")
+	h.ServeHTTP(response, request)
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *MiddlewareTestSuite) testOptionalAuthenticationMixed() {
+	var (
+		m = suite.newMiddleware(
+			WithOptionalAuthentication(),
+			WithAuthenticator(
+				suite.newAuthenticator(
+					bascule.WithTokenParsers(
+						bascule.TokenParsers[*http.Request]{
+							bascule.AsTokenParser[*http.Request](func(*http.Request) (bascule.Token, error) {
+								return nil, bascule.ErrMissingCredentials
+							}),
+							bascule.AsTokenParser[*http.Request](func(*http.Request) (bascule.Token, error) {
+								return nil, bascule.ErrBadCredentials
+							}),
+						}.All(),
+					),
+				),
+			),
+		)
+
+		response = httptest.NewRecorder()
+		request  = suite.newRequest()
+
+		h = m.ThenFunc(suite.serveHTTPNoCall)
+	)
+
+	h.ServeHTTP(response, request)
+	suite.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func (suite *MiddlewareTestSuite) testOptionalAuthenticationRequired() {
+	var (
+		m = suite.newOptionalMiddleware(
+			WithAuthorizer(
+				suite.newAuthorizer(
+					bascule.WithApprovers(
+						bascule.RequireAuthenticated[*http.Request](),
+					),
+				),
+			),
+		)
+
+		response = httptest.NewRecorder()
+		request  = suite.newRequest()
+
+		h = m.ThenFunc(suite.serveHTTPNoCall)
+	)
+
+	h.ServeHTTP(response, request)
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Equal(string(SchemeBasic), response.Header().Get(WWWAuthenticateHeader))
+}
+
+func (suite *MiddlewareTestSuite) TestOptionalAuthentication() {
+	suite.Run("Anonymous", suite.testOptionalAuthenticationAnonymous)
+	suite.Run("Authenticated", suite.testOptionalAuthenticationAuthenticated)
+	suite.Run("Invalid", suite.testOptionalAuthenticationInvalid)
+	suite.Run("Mixed", suite.testOptionalAuthenticationMixed)
+	suite.Run("Required", suite.testOptionalAuthenticationRequired)
+}
+
+// newBearerMiddleware creates a Middleware with JWT bearer authentication.
+func (suite *MiddlewareTestSuite) newBearerMiddleware(opts ...MiddlewareOption) *Middleware {
+	return suite.newMiddleware(
+		append(
+			[]MiddlewareOption{
+				WithAuthenticator(
+					suite.newAuthenticator(
+						bascule.WithTokenParsers(
+							suite.newAuthorizationParser(WithBearer(jwt.WithKey(jwa.HS256, bearerKey))),
+						),
+					),
+				),
+			},
+			opts...,
+		)...,
+	)
+}
+
+func (suite *MiddlewareTestSuite) TestBearer() {
+	suite.Run("Success", func() {
+		response := httptest.NewRecorder()
+		suite.newBearerMiddleware().
+			ThenFunc(suite.serveHTTPFunc).
+			ServeHTTP(response, suite.newBearerRequest(suite.signJWT(bearerKey)))
+
+		suite.assertNormalResponse(response)
+	})
+
+	suite.Run("Missing", func() {
+		response := httptest.NewRecorder()
+		suite.newBearerMiddleware().
+			ThenFunc(suite.serveHTTPNoCall).
+			ServeHTTP(response, suite.newRequest())
+
+		suite.Equal(http.StatusUnauthorized, response.Code)
+		suite.Equal([]string{"Bearer"}, response.Header().Values(WWWAuthenticateHeader))
+	})
+
+	suite.Run("BadSignature", func() {
+		challenge := Challenge{Scheme: SchemeBearer}
+		suite.Require().NoError(challenge.Parameters.SetRealm("test"))
+
+		response := httptest.NewRecorder()
+		suite.newBearerMiddleware(WithChallenges(challenge)).
+			ThenFunc(suite.serveHTTPNoCall).
+			ServeHTTP(response, suite.newBearerRequest(suite.signJWT([]byte("wrong key"))))
+
+		suite.Equal(http.StatusUnauthorized, response.Code)
+		suite.Equal(
+			[]string{`Bearer realm="test", error="invalid_token"`},
+			response.Header().Values(WWWAuthenticateHeader),
+		)
+	})
+
+	suite.Run("ValidatorFails", func() {
+		response := httptest.NewRecorder()
+		suite.newMiddleware(
+			WithAuthenticator(
+				suite.newAuthenticator(
+					bascule.WithTokenParsers(
+						suite.newAuthorizationParser(WithBearer(jwt.WithKey(jwa.HS256, bearerKey))),
+					),
+					bascule.WithValidators(
+						bascule.AsValidator[*http.Request](func(bascule.Token) error {
+							return bascule.ErrBadCredentials
+						}),
+					),
+					bascule.WithAuthenticatePanicRecovery[*http.Request](nil),
+				),
+			),
+		).
+			ThenFunc(suite.serveHTTPNoCall).
+			ServeHTTP(response, suite.newBearerRequest(suite.signJWT(bearerKey)))
+
+		suite.Equal(http.StatusUnauthorized, response.Code)
+		suite.Equal([]string{"Bearer"}, response.Header().Values(WWWAuthenticateHeader))
+	})
+
+	suite.Run("MalformedJWT", func() {
+		response := httptest.NewRecorder()
+		suite.newBearerMiddleware().
+			ThenFunc(suite.serveHTTPNoCall).
+			ServeHTTP(response, suite.newBearerRequest("abc.def"))
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+		suite.Empty(response.Header().Values(WWWAuthenticateHeader))
+	})
+
+	suite.Run("MalformedToken68", func() {
+		response := httptest.NewRecorder()
+		suite.newBearerMiddleware().
+			ThenFunc(suite.serveHTTPNoCall).
+			ServeHTTP(response, suite.newBearerRequest("abc,def"))
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+		suite.Empty(response.Header().Values(WWWAuthenticateHeader))
+	})
+}
+
+func (suite *MiddlewareTestSuite) TestWithTracing() {
+	var (
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		m = suite.newMiddleware(
+			WithAuthenticator(
+				suite.newAuthenticator(
+					bascule.WithTokenParsers(
+						suite.newAuthorizationParser(WithBasic()),
+					),
+				),
+			),
+			WithAuthorizer(
+				suite.newAuthorizer(
+					bascule.WithApproverFuncs(
+						func(context.Context, *http.Request, bascule.Token) error {
+							return nil
+						},
+					),
+				),
+			),
+			UseTracing(basculeotel.NewTracer(basculeotel.WithTracerProvider(provider))),
+		)
+
+		response = httptest.NewRecorder()
+		request  = suite.newBasicAuthRequest()
+
+		h = m.ThenFunc(func(response http.ResponseWriter, request *http.Request) {
+			// the handler must not be running within a bascule span
+			suite.False(trace.SpanFromContext(request.Context()).SpanContext().IsValid())
+			suite.serveHTTPFunc(response, request)
+		})
+	)
+
+	defer provider.Shutdown(context.Background())
+	h.ServeHTTP(response, request)
+	suite.assertNormalResponse(response)
+
+	spans := recorder.Ended()
+	suite.Require().Len(spans, 2)
+	suite.Equal(basculeotel.SpanAuthenticate, spans[0].Name())
+	suite.Equal(basculeotel.SpanAuthorize, spans[1].Name())
+}
+
+func (suite *MiddlewareTestSuite) TestUseTracingError() {
+	expectedErr := errors.New("expected")
+	m, err := NewMiddleware(UseTracing(nil, expectedErr))
+	suite.ErrorIs(err, expectedErr)
+	suite.Nil(m)
 }
 
 func TestMiddleware(t *testing.T) {
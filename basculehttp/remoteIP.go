@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"net"
+	"net/http"
+)
+
+// RemoteIP returns the IP address of the client that sent a request, as given
+// by the request's RemoteAddr.  Forwarding headers such as X-Forwarded-For are
+// deliberately ignored, since clients can forge them.  Servers behind a trusted
+// proxy should supply their own closure that understands that proxy.
+//
+// This function can be used as a basculehash.SourceKeyFunc to track failed
+// authentication attempts per client.  If RemoteAddr does not contain a valid
+// IP address, this function returns false.
+func RemoteIP(request *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", false
+	}
+
+	return ip.String(), true
+}
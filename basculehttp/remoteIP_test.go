@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RemoteIPTestSuite struct {
+	TestSuite
+}
+
+func (suite *RemoteIPTestSuite) TestRemoteIP() {
+	testCases := []struct {
+		remoteAddr string
+		expected   string
+		ok         bool
+	}{
+		{remoteAddr: "192.0.2.1:1234", expected: "192.0.2.1", ok: true},
+		{remoteAddr: "192.0.2.1", expected: "192.0.2.1", ok: true},
+		{remoteAddr: "[2001:db8::1]:443", expected: "2001:db8::1", ok: true},
+		{remoteAddr: "2001:db8::1", expected: "2001:db8::1", ok: true},
+		{remoteAddr: "", ok: false},
+		{remoteAddr: "example.com:80", ok: false},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.remoteAddr, func() {
+			request := suite.newRequest()
+			request.RemoteAddr = testCase.remoteAddr
+
+			actual, ok := RemoteIP(request)
+			suite.Equal(testCase.ok, ok)
+			suite.Equal(testCase.expected, actual)
+		})
+	}
+}
+
+func TestRemoteIP(t *testing.T) {
+	suite.Run(t, new(RemoteIPTestSuite))
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// RevocationKeyParameter is the query parameter that holds the key
+	// administered by a RevocationHandler.
+	RevocationKeyParameter = "key"
+
+	// RevocationTTLParameter is the optional query parameter that holds the TTL
+	// of a revocation, in the format accepted by time.ParseDuration.  If omitted,
+	// the revocation never lapses.
+	RevocationTTLParameter = "ttl"
+)
+
+// revocationMethods are the HTTP methods supported by a RevocationHandler.
+var revocationMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+}
+
+// RevocationHandler is an administrative http.Handler for a bascule.RevocationEditor.
+// The key is always supplied with the RevocationKeyParameter query parameter, in the
+// kind-qualified form produced by bascule.RevocationKey, e.g. "principal:joe".
+//
+//   - GET and HEAD respond with http.StatusNoContent if the key is revoked, and
+//     http.StatusNotFound otherwise.
+//   - PUT and POST revoke the key, using the optional RevocationTTLParameter.
+//   - DELETE unrevokes the key.
+//
+// Successful changes respond with http.StatusNoContent.  A missing key or an invalid
+// TTL results in http.StatusBadRequest, and an error from the editor results in
+// http.StatusInternalServerError.
+//
+// This handler performs no access control of its own.  It should always be protected,
+// e.g. by a Middleware with an Authorizer that only allows administrators.
+//
+// If authentication results are cached, wrap the editor with bascule.InvalidateOnRevoke
+// so that revoked tokens are removed from the cache.
+type RevocationHandler struct {
+	editor bascule.RevocationEditor
+}
+
+// NewRevocationHandler creates a RevocationHandler for the given editor.
+func NewRevocationHandler(editor bascule.RevocationEditor) (*RevocationHandler, error) {
+	if editor == nil {
+		return nil, bascule.ErrInvalidRevocationConfig
+	}
+
+	return &RevocationHandler{
+		editor: editor,
+	}, nil
+}
+
+// ServeHTTP administers the key identified by the request.
+func (rh *RevocationHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	key := query.Get(RevocationKeyParameter)
+	if len(key) == 0 {
+		http.Error(response, "missing "+RevocationKeyParameter+" parameter", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx = request.Context()
+		err error
+	)
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead:
+		var revoked bool
+		revoked, err = rh.editor.IsRevoked(ctx, key)
+		if err == nil && !revoked {
+			response.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+	case http.MethodPut, http.MethodPost:
+		var ttl time.Duration
+		if v := query.Get(RevocationTTLParameter); len(v) > 0 {
+			ttl, err = time.ParseDuration(v)
+			if err != nil {
+				http.Error(response, "invalid "+RevocationTTLParameter+" parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		err = rh.editor.Revoke(ctx, key, ttl)
+
+	case http.MethodDelete:
+		err = rh.editor.Unrevoke(ctx, key)
+
+	default:
+		response.Header().Set("Allow", strings.Join(revocationMethods, ", "))
+		response.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case errors.Is(err, bascule.ErrInvalidRevocation):
+		http.Error(response, err.Error(), http.StatusBadRequest)
+
+	case err != nil:
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+
+	default:
+		response.WriteHeader(http.StatusNoContent)
+	}
+}
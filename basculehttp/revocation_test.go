@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculehttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+// failingRevocationEditor is a bascule.RevocationEditor whose methods always fail.
+type failingRevocationEditor struct {
+	err error
+}
+
+func (fre failingRevocationEditor) IsRevoked(context.Context, string) (bool, error) {
+	return false, fre.err
+}
+
+func (fre failingRevocationEditor) Revoke(context.Context, string, time.Duration) error {
+	return fre.err
+}
+
+func (fre failingRevocationEditor) Unrevoke(context.Context, string) error {
+	return fre.err
+}
+
+type RevocationTestSuite struct {
+	TestSuite
+
+	store *bascule.MemoryRevocationStore
+}
+
+func (suite *RevocationTestSuite) SetupTest() {
+	var err error
+	suite.store, err = bascule.NewMemoryRevocationStore()
+	suite.Require().NoError(err)
+}
+
+func (suite *RevocationTestSuite) newRevocationHandler(editor bascule.RevocationEditor) *RevocationHandler {
+	rh, err := NewRevocationHandler(editor)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(rh)
+	return rh
+}
+
+// serve sends a request for the given key, with optional extra query parameters, to a handler.
+func (suite *RevocationTestSuite) serve(h http.Handler, method, key string, extra ...string) *httptest.ResponseRecorder {
+	query := url.Values{}
+	if len(key) > 0 {
+		query.Set(RevocationKeyParameter, key)
+	}
+
+	for i := 0; i+1 < len(extra); i += 2 {
+		query.Set(extra[i], extra[i+1])
+	}
+
+	response := httptest.NewRecorder()
+	h.ServeHTTP(response, httptest.NewRequest(method, "/revocations?"+query.Encode(), nil))
+	return response
+}
+
+func (suite *RevocationTestSuite) assertRevoked(key string, expected bool) {
+	revoked, err := suite.store.IsRevoked(context.Background(), key)
+	suite.Require().NoError(err)
+	suite.Equal(expected, revoked)
+}
+
+func (suite *RevocationTestSuite) TestNewRevocationHandler() {
+	rh, err := NewRevocationHandler(nil)
+	suite.ErrorIs(err, bascule.ErrInvalidRevocationConfig)
+	suite.Nil(rh)
+}
+
+func (suite *RevocationTestSuite) TestMissingKey() {
+	rh := suite.newRevocationHandler(suite.store)
+	response := suite.serve(rh, http.MethodPut, "")
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *RevocationTestSuite) TestLifecycle() {
+	rh := suite.newRevocationHandler(suite.store)
+
+	suite.Equal(http.StatusNotFound, suite.serve(rh, http.MethodGet, "joe").Code)
+
+	suite.Equal(http.StatusNoContent, suite.serve(rh, http.MethodPut, "joe").Code)
+	suite.assertRevoked("joe", true)
+	suite.Equal(http.StatusNoContent, suite.serve(rh, http.MethodGet, "joe").Code)
+	suite.Equal(http.StatusNoContent, suite.serve(rh, http.MethodHead, "joe").Code)
+
+	suite.Equal(http.StatusNoContent, suite.serve(rh, http.MethodPost, "jane", RevocationTTLParameter, "1h").Code)
+	suite.assertRevoked("jane", true)
+
+	suite.Equal(http.StatusNoContent, suite.serve(rh, http.MethodDelete, "joe").Code)
+	suite.assertRevoked("joe", false)
+	suite.Equal(http.StatusNotFound, suite.serve(rh, http.MethodGet, "joe").Code)
+}
+
+func (suite *RevocationTestSuite) TestInvalidTTL() {
+	rh := suite.newRevocationHandler(suite.store)
+
+	suite.Equal(http.StatusBadRequest, suite.serve(rh, http.MethodPut, "joe", RevocationTTLParameter, "not a duration").Code)
+	suite.Equal(http.StatusBadRequest, suite.serve(rh, http.MethodPut, "joe", RevocationTTLParameter, "-1h").Code)
+	suite.assertRevoked("joe", false)
+}
+
+func (suite *RevocationTestSuite) TestMethodNotAllowed() {
+	rh := suite.newRevocationHandler(suite.store)
+	response := suite.serve(rh, http.MethodPatch, "joe")
+	suite.Equal(http.StatusMethodNotAllowed, response.Code)
+	suite.Equal("GET, HEAD, PUT, POST, DELETE", response.Header().Get("Allow"))
+}
+
+func (suite *RevocationTestSuite) TestEditorError() {
+	rh := suite.newRevocationHandler(failingRevocationEditor{err: errors.New("expected")})
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		suite.Run(method, func() {
+			suite.Equal(http.StatusInternalServerError, suite.serve(rh, method, "joe").Code)
+		})
+	}
+}
+
+func (suite *RevocationTestSuite) TestMiddleware() {
+	v, err := bascule.NewRevocationValidator[*http.Request](suite.store)
+	suite.Require().NoError(err)
+
+	m, err := NewMiddleware(
+		UseAuthenticator(
+			NewAuthenticator(
+				bascule.WithTokenParsers(
+					suite.newAuthorizationParser(WithBasic()),
+				),
+				bascule.WithValidators(v),
+			),
+		),
+		WithChallenges(Challenge{Scheme: SchemeBasic}),
+	)
+
+	suite.Require().NoError(err)
+
+	h := m.ThenFunc(func(response http.ResponseWriter, _ *http.Request) {
+		response.WriteHeader(http.StatusOK)
+	})
+
+	response := httptest.NewRecorder()
+	h.ServeHTTP(response, suite.newBasicAuthRequest())
+	suite.Equal(http.StatusOK, response.Code)
+
+	suite.Require().NoError(suite.store.Revoke(context.Background(), bascule.RevocationKey(bascule.PrincipalRevocationKind, expectedPrincipal), 0))
+	response = httptest.NewRecorder()
+	h.ServeHTTP(response, suite.newBasicAuthRequest())
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Equal(string(SchemeBasic), response.Header().Get(WWWAuthenticateHeader))
+}
+
+func TestRevocation(t *testing.T) {
+	suite.Run(t, new(RevocationTestSuite))
+}
@@ -6,7 +6,10 @@ package basculehttp
 import (
 	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/suite"
 	"github.com/xmidt-org/bascule"
 )
@@ -16,6 +19,11 @@ const (
 	expectedPassword  = "test_password"
 )
 
+var (
+	// bearerKey is the HS256 key used to sign and verify test JWTs.
+	bearerKey = []byte("test bearer key")
+)
+
 // TestSuite is a common suite that exposes some useful behaviors.
 type TestSuite struct {
 	suite.Suite
@@ -54,6 +62,27 @@ func (suite *TestSuite) assertBasicToken(token bascule.Token) {
 	suite.Equal(expectedPassword, token.(BasicToken).Password())
 }
 
+// signJWT creates a compact JWT for expectedPrincipal, signed with the given key.
+func (suite *TestSuite) signJWT(key []byte) string {
+	built, err := jwt.NewBuilder().
+		Subject(expectedPrincipal).
+		Expiration(time.Now().Add(time.Hour)).
+		Build()
+
+	suite.Require().NoError(err)
+
+	signed, err := jwt.Sign(built, jwt.WithKey(jwa.HS256, key))
+	suite.Require().NoError(err)
+	return string(signed)
+}
+
+// newBearerRequest creates a new test request with the given bearer token.
+func (suite *TestSuite) newBearerRequest(token string) *http.Request {
+	request := suite.newRequest()
+	request.Header.Set(DefaultAuthorizationHeader, string(SchemeBearer)+" "+token)
+	return request
+}
+
 // newAuthorizationParser creates an AuthorizationParser that is expected to be valid.
 // Assertions as to validity are made prior to returning.
 func (suite *TestSuite) newAuthorizationParser(opts ...AuthorizationParserOption) *AuthorizationParser {
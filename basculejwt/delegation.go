@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculejwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// ActorKey is the JWT claims key for the RFC 8693 actor claim.
+	ActorKey = "act"
+
+	// DefaultMaxActorDepth is the default limit on the number of nested actor claims.
+	DefaultMaxActorDepth = 8
+)
+
+var (
+	// ErrInvalidDelegationConfig indicates that a delegation validator option was
+	// given an invalid value.
+	ErrInvalidDelegationConfig = errors.New("invalid delegation validator configuration")
+)
+
+// DelegationValidatorOption is a configurable option for a delegation validator.
+type DelegationValidatorOption interface {
+	apply(*delegationValidatorConfig) error
+}
+
+type delegationValidatorOptionFunc func(*delegationValidatorConfig) error
+
+func (dvof delegationValidatorOptionFunc) apply(c *delegationValidatorConfig) error { return dvof(c) }
+
+// WithMaxActorDepth sets the maximum number of nested actor claims that will be
+// accepted.  A JWT with a deeper actor chain is rejected with bascule.ErrInvalidCredentials.
+// If this option is not supplied, DefaultMaxActorDepth is used.
+func WithMaxActorDepth(maxDepth int) DelegationValidatorOption {
+	return delegationValidatorOptionFunc(func(c *delegationValidatorConfig) error {
+		if maxDepth < 1 {
+			return ErrInvalidDelegationConfig
+		}
+
+		c.maxDepth = maxDepth
+		return nil
+	})
+}
+
+type delegationValidatorConfig struct {
+	maxDepth int
+}
+
+type delegationValidator[S any] struct {
+	maxDepth int
+}
+
+// NewDelegationValidator creates a bascule.Validator that converts a JWT with an RFC 8693
+// act claim into a bascule.DelegationToken.  The returned token's principal is still the
+// JWT's subject, and it unwraps to the original token, so bascule.TokenAs continues to
+// find the JWT's Claims.
+//
+// Tokens without a JWT, JWTs without an act claim, and tokens that already carry an
+// actor chain are left unchanged.  A malformed act claim is rejected with
+// bascule.ErrInvalidCredentials.
+func NewDelegationValidator[S any](opts ...DelegationValidatorOption) (bascule.Validator[S], error) {
+	c := delegationValidatorConfig{
+		maxDepth: DefaultMaxActorDepth,
+	}
+
+	for _, o := range opts {
+		if err := o.apply(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	return &delegationValidator[S]{
+		maxDepth: c.maxDepth,
+	}, nil
+}
+
+func (dv *delegationValidator[S]) Validate(_ context.Context, _ S, t bascule.Token) (bascule.Token, error) {
+	if _, delegated := bascule.GetActors(t); delegated {
+		return nil, nil
+	}
+
+	var jt *token
+	if !bascule.TokenAs(t, &jt) {
+		return nil, nil
+	}
+
+	act, ok := jt.jwt.Get(ActorKey)
+	if !ok {
+		return nil, nil
+	}
+
+	actors, err := dv.parseActors(act)
+	if err != nil {
+		return nil, err
+	}
+
+	return bascule.NewDelegationToken(t, actors...), nil
+}
+
+// parseActors flattens a nested act claim into an actor chain, beginning with
+// the current actor.
+func (dv *delegationValidator[S]) parseActors(act any) (actors []bascule.Actor, err error) {
+	for act != nil {
+		if len(actors) == dv.maxDepth {
+			return nil, fmt.Errorf("%w: actor chain exceeds %d", bascule.ErrInvalidCredentials, dv.maxDepth)
+		}
+
+		claim, ok := act.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s claim must be an object", bascule.ErrInvalidCredentials, ActorKey)
+		}
+
+		var a bascule.Actor
+		a.Subject, _ = claim["sub"].(string)
+		if len(a.Subject) == 0 {
+			return nil, fmt.Errorf("%w: %s claim requires a sub", bascule.ErrInvalidCredentials, ActorKey)
+		}
+
+		a.Issuer, _ = claim["iss"].(string)
+		actors = append(actors, a)
+		act = claim[ActorKey]
+	}
+
+	return
+}
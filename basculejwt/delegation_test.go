@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculejwt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type DelegationTestSuite struct {
+	suite.Suite
+
+	key []byte
+}
+
+func (suite *DelegationTestSuite) SetupSuite() {
+	suite.key = []byte("a test key that is long enough for HS256")
+}
+
+// parse signs a JWT with the given act claim, then parses it as a bascule token.
+func (suite *DelegationTestSuite) parse(act any) bascule.Token {
+	b := jwt.NewBuilder().Subject("customer").Claim(CapabilitiesKey, []string{"doc:read"})
+	if act != nil {
+		b = b.Claim(ActorKey, act)
+	}
+
+	unsigned, err := b.Build()
+	suite.Require().NoError(err)
+
+	signed, err := jwt.Sign(unsigned, jwt.WithKey(jwa.HS256, suite.key))
+	suite.Require().NoError(err)
+
+	tp, err := NewTokenParser(jwt.WithKey(jwa.HS256, suite.key))
+	suite.Require().NoError(err)
+
+	t, err := tp.Parse(context.Background(), string(signed))
+	suite.Require().NoError(err)
+	return t
+}
+
+func (suite *DelegationTestSuite) newValidator(opts ...DelegationValidatorOption) bascule.Validator[string] {
+	v, err := NewDelegationValidator[string](opts...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(v)
+	return v
+}
+
+func (suite *DelegationTestSuite) TestInvalidConfig() {
+	v, err := NewDelegationValidator[string](WithMaxActorDepth(0))
+	suite.ErrorIs(err, ErrInvalidDelegationConfig)
+	suite.Nil(v)
+}
+
+func (suite *DelegationTestSuite) TestNoActor() {
+	next, err := suite.newValidator().Validate(context.Background(), "source", suite.parse(nil))
+	suite.NoError(err)
+	suite.Nil(next)
+}
+
+func (suite *DelegationTestSuite) TestNotJWT() {
+	next, err := suite.newValidator().Validate(context.Background(), "source", bascule.StubToken("customer"))
+	suite.NoError(err)
+	suite.Nil(next)
+}
+
+func (suite *DelegationTestSuite) TestActorChain() {
+	v := suite.newValidator()
+	original := suite.parse(map[string]any{
+		"sub": "support-tool",
+		"act": map[string]any{
+			"sub": "admin",
+			"iss": "https://corp.example.com",
+		},
+	})
+
+	next, err := v.Validate(context.Background(), "source", original)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(next)
+
+	suite.Equal("customer", next.Principal())
+	actors, ok := bascule.GetActors(next)
+	suite.True(ok)
+	suite.Equal(
+		[]bascule.Actor{
+			{Subject: "support-tool"},
+			{Subject: "admin", Issuer: "https://corp.example.com"},
+		},
+		actors,
+	)
+
+	var claims Claims
+	suite.Require().True(bascule.TokenAs(next, &claims))
+	suite.Equal("customer", claims.Subject())
+
+	// validating again must not nest delegation tokens
+	again, err := v.Validate(context.Background(), "source", next)
+	suite.NoError(err)
+	suite.Nil(again)
+}
+
+func (suite *DelegationTestSuite) TestMalformed() {
+	testCases := []struct {
+		name string
+		act  any
+		opts []DelegationValidatorOption
+	}{
+		{
+			name: "NotAnObject",
+			act:  "support-tool",
+		},
+		{
+			name: "MissingSubject",
+			act:  map[string]any{"iss": "https://corp.example.com"},
+		},
+		{
+			name: "NestedMissingSubject",
+			act: map[string]any{
+				"sub": "support-tool",
+				"act": map[string]any{"iss": "https://corp.example.com"},
+			},
+		},
+		{
+			name: "TooDeep",
+			act: map[string]any{
+				"sub": "first",
+				"act": map[string]any{
+					"sub": "second",
+				},
+			},
+			opts: []DelegationValidatorOption{WithMaxActorDepth(1)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			next, err := suite.newValidator(testCase.opts...).Validate(
+				context.Background(),
+				"source",
+				suite.parse(testCase.act),
+			)
+
+			suite.Nil(next)
+			suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+		})
+	}
+}
+
+func (suite *DelegationTestSuite) TestImpersonationApprover() {
+	var (
+		v = suite.newValidator()
+		a = bascule.NewImpersonationApprover[string](
+			bascule.AllowActorFor("support-tool", "customer"),
+		)
+	)
+
+	next, err := v.Validate(context.Background(), "source", suite.parse(map[string]any{"sub": "support-tool"}))
+	suite.Require().NoError(err)
+	suite.NoError(a.Approve(context.Background(), "resource", next))
+
+	next, err = v.Validate(context.Background(), "source", suite.parse(map[string]any{"sub": "intruder"}))
+	suite.Require().NoError(err)
+	suite.ErrorIs(a.Approve(context.Background(), "resource", next), bascule.ErrUnauthorized)
+}
+
+func TestDelegation(t *testing.T) {
+	suite.Run(t, new(DelegationTestSuite))
+}
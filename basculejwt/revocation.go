@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculejwt
+
+import "github.com/xmidt-org/bascule"
+
+// JwtIDRevocationKind is the kind of the keys produced by JwtIDRevocationKey.
+const JwtIDRevocationKind = "jti"
+
+var _ bascule.RevocationKeyFunc = JwtIDRevocationKey
+
+// JwtIDRevocationKey is a bascule.RevocationKeyFunc that uses the jti claim of a JWT,
+// qualified by JwtIDRevocationKind, e.g. "jti:abc".  Use this with bascule.WithRevocationKeys
+// to revoke individual JWTs.  Tokens that are not JWTs, and JWTs without a jti, are not checked.
+func JwtIDRevocationKey(t bascule.Token) (string, bool) {
+	var c Claims
+	if bascule.TokenAs(t, &c) {
+		jti := c.JwtID()
+		return bascule.RevocationKey(JwtIDRevocationKind, jti), len(jti) > 0
+	}
+
+	return "", false
+}
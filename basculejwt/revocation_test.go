@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculejwt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type RevocationTestSuite struct {
+	suite.Suite
+}
+
+// newToken creates a JWT token with the given jti.  An empty jti is omitted.
+func (suite *RevocationTestSuite) newToken(jti string) bascule.Token {
+	b := jwt.NewBuilder().Subject("joe")
+	if len(jti) > 0 {
+		b = b.JwtID(jti)
+	}
+
+	built, err := b.Build()
+	suite.Require().NoError(err)
+	return &token{jwt: built}
+}
+
+func (suite *RevocationTestSuite) TestJwtIDRevocationKey() {
+	suite.Run("JwtID", func() {
+		key, ok := JwtIDRevocationKey(suite.newToken("test-jwt"))
+		suite.True(ok)
+		suite.Equal("jti:test-jwt", key)
+	})
+
+	suite.Run("Wrapped", func() {
+		key, ok := JwtIDRevocationKey(bascule.JoinTokens(bascule.StubToken("other"), suite.newToken("test-jwt")))
+		suite.True(ok)
+		suite.Equal("jti:test-jwt", key)
+	})
+
+	suite.Run("NoJwtID", func() {
+		_, ok := JwtIDRevocationKey(suite.newToken(""))
+		suite.False(ok)
+	})
+
+	suite.Run("NotJWT", func() {
+		_, ok := JwtIDRevocationKey(bascule.StubToken("joe"))
+		suite.False(ok)
+	})
+}
+
+func (suite *RevocationTestSuite) TestValidator() {
+	store, err := bascule.NewMemoryRevocationStore()
+	suite.Require().NoError(err)
+	suite.Require().NoError(store.Revoke(context.Background(), bascule.RevocationKey(JwtIDRevocationKind, "revoked-jwt"), 0))
+
+	v, err := bascule.NewRevocationValidator[string](store, bascule.WithRevocationKeys(JwtIDRevocationKey))
+	suite.Require().NoError(err)
+
+	_, err = v.Validate(context.Background(), "source", suite.newToken("revoked-jwt"))
+	suite.ErrorIs(err, bascule.ErrRevokedCredentials)
+
+	_, err = v.Validate(context.Background(), "source", suite.newToken("test-jwt"))
+	suite.NoError(err)
+}
+
+func TestRevocation(t *testing.T) {
+	suite.Run(t, new(RevocationTestSuite))
+}
@@ -5,8 +5,11 @@ package basculejwt
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/xmidt-org/bascule"
 )
@@ -14,7 +17,10 @@ import (
 // CapabilitiesKey is the JWT claims key where capabilities are expected.
 const CapabilitiesKey = "capabilities"
 
-// Claims exposes standard JWT claims from a Token.
+// Claims exposes standard JWT claims from a Token.  Any Claims implementation
+// also satisfies bascule.ExpirationAccessor, bascule.NotBeforeAccessor, and
+// bascule.IssuedAtAccessor, which allows JWTs to be used with
+// bascule.NewTimeWindowValidator.
 type Claims interface {
 	// Audience returns the aud field of the JWT.
 	Audience() []string
@@ -46,6 +52,12 @@ type token struct {
 	jwt jwt.Token
 }
 
+var (
+	_ bascule.ExpirationAccessor = token{}
+	_ bascule.NotBeforeAccessor  = token{}
+	_ bascule.IssuedAtAccessor   = token{}
+)
+
 func (t token) Audience() []string {
 	return t.jwt.Audience()
 }
@@ -90,6 +102,45 @@ func (t token) Get(key string) (any, bool) {
 	return t.jwt.Get(key)
 }
 
+// Attributes returns all the claims of the JWT, both registered and private.
+func (t token) Attributes() map[string]any {
+	m, _ := t.jwt.AsMap(context.Background())
+	return m
+}
+
+// ClassifyError classifies an error from jwt.Parse by wrapping it with the appropriate
+// bascule sentinel.  The original error remains in the chain.
+//
+//   - An unsatisfied exp claim is wrapped with bascule.ErrTokenExpired.
+//   - An unsatisfied nbf claim is wrapped with bascule.ErrTokenNotYetValid.
+//   - A signature that could not be verified, or any other failed claim validation,
+//     is wrapped with bascule.ErrBadCredentials.
+//   - Any other error, e.g. a value that is not a well-formed JWT, is wrapped with
+//     bascule.ErrInvalidCredentials.
+//
+// This function returns nil if err is nil.
+func ClassifyError(err error) error {
+	var sentinel error
+	switch {
+	case err == nil:
+		return nil
+
+	case errors.Is(err, jwt.ErrTokenExpired()):
+		sentinel = bascule.ErrTokenExpired
+
+	case errors.Is(err, jwt.ErrTokenNotYetValid()):
+		sentinel = bascule.ErrTokenNotYetValid
+
+	case jws.IsVerificationError(err), jwt.IsValidationError(err):
+		sentinel = bascule.ErrBadCredentials
+
+	default:
+		sentinel = bascule.ErrInvalidCredentials
+	}
+
+	return fmt.Errorf("%w: %w", sentinel, err)
+}
+
 // tokenParser is the canonical parser for bascule that deals with JWTs.
 // This parser does not use the source.
 type tokenParser struct {
@@ -108,10 +159,12 @@ func NewTokenParser(options ...jwt.ParseOption) (bascule.TokenParser[string], er
 
 // Parse parses the value as a JWT, using the parsing options passed to NewTokenParser.
 // The returned Token will implement the bascule.Attributes, bascule.Capabilities, and Claims interfaces.
+//
+// Any error is classified as described by ClassifyError.
 func (tp *tokenParser) Parse(ctx context.Context, value string) (bascule.Token, error) {
 	jwtToken, err := jwt.ParseString(value, tp.options...)
 	if err != nil {
-		return nil, err
+		return nil, ClassifyError(err)
 	}
 
 	return &token{
@@ -99,6 +99,7 @@ func (suite *TokenTestSuite) createJWT() {
 		Claim("capabilities", suite.capabilities).
 		Claim("allowedResources", suite.allowedResources).
 		Claim("version", suite.version).
+		Claim("level", 3).
 		Build()
 
 	suite.Require().NoError(err)
@@ -144,6 +145,76 @@ func (suite *TokenTestSuite) TestTokenParser() {
 		suite.Equal(suite.issuedAt, claims.IssuedAt())
 		suite.Equal(suite.notBefore, claims.NotBefore())
 		suite.Equal(suite.jwtID, claims.JwtID())
+
+		exp, ok := bascule.GetExpiration(token)
+		suite.True(ok)
+		suite.Equal(suite.expiration, exp)
+
+		nbf, ok := bascule.GetNotBefore(token)
+		suite.True(ok)
+		suite.Equal(suite.notBefore, nbf)
+
+		iat, ok := bascule.GetIssuedAt(token)
+		suite.True(ok)
+		suite.Equal(suite.issuedAt, iat)
+	})
+
+	suite.Run("TimeWindow", func() {
+		tp, err := NewTokenParser(jwt.WithKeySet(suite.testKeySet))
+		suite.Require().NoError(err)
+
+		token, err := tp.Parse(context.Background(), string(suite.signedJWT))
+		suite.Require().NoError(err)
+
+		v, err := bascule.NewTimeWindowValidator[string](
+			bascule.WithClock(func() time.Time {
+				return suite.expiration.Add(time.Minute)
+			}),
+		)
+
+		suite.Require().NoError(err)
+		_, err = v.Validate(context.Background(), "source", token)
+		suite.ErrorIs(err, bascule.ErrTokenExpired)
+	})
+
+	suite.Run("Attributes", func() {
+		tp, err := NewTokenParser(jwt.WithKeySet(suite.testKeySet))
+		suite.Require().NoError(err)
+
+		token, err := tp.Parse(context.Background(), string(suite.signedJWT))
+		suite.Require().NoError(err)
+		attributes := token.(bascule.AttributesAccessor)
+
+		// numeric claims are decoded as float64
+		level, ok := bascule.GetAttribute[int](attributes, "level")
+		suite.True(ok)
+		suite.Equal(3, level)
+
+		partners, ok := bascule.GetAttribute[[]string](attributes, "allowedResources", "allowedPartners")
+		suite.True(ok)
+		suite.Equal([]string{"comcast"}, partners)
+
+		var decoded struct {
+			Subject    string    `json:"sub"`
+			Expiration time.Time `json:"exp"`
+			Version    string    `json:"version"`
+			Level      uint8     `json:"level"`
+			Resources  struct {
+				AllowedPartners []string `json:"allowedPartners"`
+			} `json:"allowedResources"`
+		}
+
+		suite.Require().NoError(bascule.DecodeAttributes(attributes, &decoded))
+		suite.Equal(suite.subject, decoded.Subject)
+		suite.Equal(suite.expiration, decoded.Expiration)
+		suite.Equal(suite.version, decoded.Version)
+		suite.Equal(uint8(3), decoded.Level)
+		suite.Equal([]string{"comcast"}, decoded.Resources.AllowedPartners)
+
+		all := token.(interface{ Attributes() map[string]any }).Attributes()
+		suite.Equal(suite.subject, all["sub"])
+		suite.Equal(suite.version, all["version"])
+		suite.Contains(all, "exp")
 	})
 
 	suite.Run("NoOptions", func() {
@@ -157,6 +228,86 @@ func (suite *TokenTestSuite) TestTokenParser() {
 	})
 }
 
+// signHS256 creates a signed JWT with the given expiration and not before times.
+func (suite *TokenTestSuite) signHS256(key []byte, exp, nbf time.Time) string {
+	built, err := jwt.NewBuilder().
+		Subject(suite.subject).
+		Audience(suite.audience).
+		Expiration(exp).
+		NotBefore(nbf).
+		Build()
+
+	suite.Require().NoError(err)
+
+	signed, err := jwt.Sign(built, jwt.WithKey(jwa.HS256, key))
+	suite.Require().NoError(err)
+	return string(signed)
+}
+
+func (suite *TokenTestSuite) TestClassifyError() {
+	var (
+		key      = []byte("test key")
+		wrongKey = []byte("wrong key")
+		now      = time.Now().Round(time.Second)
+	)
+
+	testCases := []struct {
+		name     string
+		value    string
+		options  []jwt.ParseOption
+		expected error
+	}{
+		{
+			name:     "Malformed",
+			value:    "this is not a JWT",
+			expected: bascule.ErrInvalidCredentials,
+		},
+		{
+			name:     "BadSignature",
+			value:    suite.signHS256(wrongKey, now.Add(time.Hour), now.Add(-time.Hour)),
+			expected: bascule.ErrBadCredentials,
+		},
+		{
+			name:     "Expired",
+			value:    suite.signHS256(key, now.Add(-time.Hour), now.Add(-2*time.Hour)),
+			expected: bascule.ErrTokenExpired,
+		},
+		{
+			name:     "NotYetValid",
+			value:    suite.signHS256(key, now.Add(2*time.Hour), now.Add(time.Hour)),
+			expected: bascule.ErrTokenNotYetValid,
+		},
+		{
+			name:     "WrongAudience",
+			value:    suite.signHS256(key, now.Add(time.Hour), now.Add(-time.Hour)),
+			options:  []jwt.ParseOption{jwt.WithAudience("other")},
+			expected: bascule.ErrBadCredentials,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			tp, err := NewTokenParser(
+				append([]jwt.ParseOption{jwt.WithKey(jwa.HS256, key)}, testCase.options...)...,
+			)
+
+			suite.Require().NoError(err)
+
+			token, err := tp.Parse(context.Background(), testCase.value)
+			suite.Nil(token)
+			suite.ErrorIs(err, testCase.expected)
+			suite.Equal(
+				bascule.CategorizeError(testCase.expected),
+				bascule.CategorizeError(err),
+			)
+		})
+	}
+
+	suite.Run("Nil", func() {
+		suite.NoError(ClassifyError(nil))
+	})
+}
+
 func TestToken(t *testing.T) {
 	suite.Run(t, new(TokenTestSuite))
 }
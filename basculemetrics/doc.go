@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculemetrics instruments the components of bascule workflows.  Token
+parsers, validators, and approvers can be decorated so that each invocation is
+counted and timed.  Measurements are written to a Sink, which is a small
+interface that can be adapted to any metrics library.  An expvar-backed Sink
+is provided as a default.
+*/
+package basculemetrics
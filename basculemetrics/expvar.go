@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"errors"
+	"expvar"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultExpvarName is the name under which DefaultSink publishes its variables.
+	DefaultExpvarName = "bascule"
+
+	// CountKey is the expvar key of an invocation counter.
+	CountKey = "count"
+
+	// LatencyKey is the expvar key of a latency histogram.
+	LatencyKey = "latency"
+
+	// SumKey is the key within a latency histogram of the total observed
+	// time, in nanoseconds.
+	SumKey = "sum_ns"
+
+	// InfBucket is the key within a latency histogram of the bucket that
+	// counts every observation.
+	InfBucket = "le_inf"
+)
+
+var (
+	// ErrExpvarExists indicates that an expvar variable with the requested
+	// name has already been published.
+	ErrExpvarExists = errors.New("expvar variable already exists")
+
+	// DefaultBuckets are the upper bounds of the latency histogram buckets
+	// used when none are supplied to NewExpvarSink.
+	DefaultBuckets = []time.Duration{
+		time.Millisecond,
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		5 * time.Second,
+	}
+
+	defaultSinkOnce sync.Once
+	defaultSink     Sink
+)
+
+// DefaultSink returns the process-wide ExpvarSink published under DefaultExpvarName.
+// The sink is created the first time this function is called.
+//
+// If some other code has already published a variable named DefaultExpvarName, the
+// returned sink still records measurements but does not publish them.  Use WithSink
+// and NewExpvarSink to publish under a different name.
+func DefaultSink() Sink {
+	defaultSinkOnce.Do(func() {
+		defaultSink = newDefaultSink(DefaultExpvarName)
+	})
+
+	return defaultSink
+}
+
+// newDefaultSink publishes an ExpvarSink under the given name, falling back to an
+// unpublished sink if that name is already in use.
+func newDefaultSink(name string) *ExpvarSink {
+	if es, err := NewExpvarSink(name); err == nil {
+		return es
+	}
+
+	return newExpvarSink(new(expvar.Map), DefaultBuckets)
+}
+
+// ExpvarSink is a Sink that publishes measurements as a tree of expvar maps,
+// organized as kind, then component name, then outcome.  For example:
+//
+//	{
+//	  "token_parser": {
+//	    "basic": {
+//	      "success": {
+//	        "count": 12,
+//	        "latency": {"le_1ms": 11, "le_5ms": 12, ..., "le_inf": 12, "sum_ns": 4815162}
+//	      }
+//	    }
+//	  }
+//	}
+//
+// Latency histogram buckets are cumulative, in the style of Prometheus.
+type ExpvarSink struct {
+	root    *expvar.Map
+	buckets []time.Duration
+	keys    []string
+
+	lock sync.Mutex
+}
+
+// NewExpvarSink publishes a new expvar map with the given name and returns an
+// ExpvarSink that writes to it.  If no buckets are supplied, DefaultBuckets is used.
+//
+// Since expvar variables cannot be unpublished, this function returns
+// ErrExpvarExists if the name is already in use.
+func NewExpvarSink(name string, buckets ...time.Duration) (*ExpvarSink, error) {
+	if expvar.Get(name) != nil {
+		return nil, ErrExpvarExists
+	}
+
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	return newExpvarSink(expvar.NewMap(name), buckets), nil
+}
+
+// newExpvarSink creates an ExpvarSink that writes to the given root map.
+func newExpvarSink(root *expvar.Map, buckets []time.Duration) *ExpvarSink {
+	es := &ExpvarSink{
+		root:    root,
+		buckets: slices.Clone(buckets),
+	}
+
+	slices.Sort(es.buckets)
+	es.buckets = slices.Compact(es.buckets)
+	es.keys = make([]string, len(es.buckets))
+	for i, b := range es.buckets {
+		es.keys[i] = bucketKey(b)
+	}
+
+	return es
+}
+
+// bucketKey produces the expvar key for a histogram bucket, e.g. "le_5ms".
+func bucketKey(d time.Duration) string {
+	if d%time.Second == 0 {
+		return "le_" + strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	} else if d%time.Millisecond == 0 {
+		return "le_" + strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+	} else if d%time.Microsecond == 0 {
+		return "le_" + strconv.FormatInt(int64(d/time.Microsecond), 10) + "us"
+	}
+
+	return "le_" + strconv.FormatInt(int64(d), 10) + "ns"
+}
+
+// child returns the named child map of the given parent, creating it if necessary.
+// The lock must be held.
+func child(parent *expvar.Map, key string) *expvar.Map {
+	if m, ok := parent.Get(key).(*expvar.Map); ok {
+		return m
+	}
+
+	m := new(expvar.Map)
+	parent.Set(key, m)
+	return m
+}
+
+// node returns the map for a set of labels.
+func (es *ExpvarSink) node(l Labels) *expvar.Map {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	return child(child(child(es.root, string(l.Kind)), l.Name), l.Outcome)
+}
+
+// histogram returns the latency histogram for a set of labels.  A new histogram
+// has every bucket initialized to zero.
+func (es *ExpvarSink) histogram(l Labels) *expvar.Map {
+	n := es.node(l)
+	if h, ok := n.Get(LatencyKey).(*expvar.Map); ok {
+		return h
+	}
+
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	if h, ok := n.Get(LatencyKey).(*expvar.Map); ok {
+		return h
+	}
+
+	h := new(expvar.Map)
+	for _, k := range es.keys {
+		h.Add(k, 0)
+	}
+
+	h.Add(InfBucket, 0)
+	h.Add(SumKey, 0)
+	n.Set(LatencyKey, h)
+	return h
+}
+
+// Inc increments the count for the given labels.
+func (es *ExpvarSink) Inc(l Labels) {
+	es.node(l).Add(CountKey, 1)
+}
+
+// Observe adds a latency observation to the histogram for the given labels.
+func (es *ExpvarSink) Observe(l Labels, d time.Duration) {
+	h := es.histogram(l)
+	for i, b := range es.buckets {
+		if d <= b {
+			h.Add(es.keys[i], 1)
+		}
+	}
+
+	h.Add(InfBucket, 1)
+	h.Add(SumKey, int64(d))
+}
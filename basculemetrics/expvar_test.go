@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExpvarTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ExpvarTestSuite) newExpvarSink(name string, buckets ...time.Duration) *ExpvarSink {
+	es, err := NewExpvarSink(name, buckets...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(es)
+	return es
+}
+
+// published decodes the JSON form of a published expvar variable.
+func (suite *ExpvarTestSuite) published(name string) (v map[string]any) {
+	suite.Require().NoError(
+		json.Unmarshal([]byte(expvar.Get(name).String()), &v),
+	)
+
+	return
+}
+
+func (suite *ExpvarTestSuite) TestBucketKey() {
+	suite.Equal("le_5s", bucketKey(5*time.Second))
+	suite.Equal("le_1500ms", bucketKey(1500*time.Millisecond))
+	suite.Equal("le_250us", bucketKey(250*time.Microsecond))
+	suite.Equal("le_100ns", bucketKey(100))
+}
+
+func (suite *ExpvarTestSuite) TestNameInUse() {
+	suite.newExpvarSink("basculemetrics.TestNameInUse")
+	es, err := NewExpvarSink("basculemetrics.TestNameInUse")
+	suite.Nil(es)
+	suite.ErrorIs(err, ErrExpvarExists)
+}
+
+func (suite *ExpvarTestSuite) TestDefaultSink() {
+	s := DefaultSink()
+	suite.Require().IsType((*ExpvarSink)(nil), s)
+	suite.Same(s, DefaultSink())
+	suite.NotNil(expvar.Get(DefaultExpvarName))
+}
+
+func (suite *ExpvarTestSuite) TestDefaultSinkNameInUse() {
+	const name = "basculemetrics.TestDefaultSinkNameInUse"
+	existing := expvar.NewString(name)
+	existing.Set("taken")
+
+	var es *ExpvarSink
+	suite.NotPanics(func() {
+		es = newDefaultSink(name)
+	})
+
+	suite.Require().NotNil(es)
+	suite.Same(existing, expvar.Get(name))
+
+	l := Labels{Kind: KindValidator, Name: "test", Outcome: OutcomeSuccess}
+	es.Inc(l)
+	es.Observe(l, time.Millisecond)
+	suite.Equal(`"taken"`, existing.String())
+}
+
+func (suite *ExpvarTestSuite) TestMeasurements() {
+	const name = "basculemetrics.TestMeasurements"
+	es := suite.newExpvarSink(name, 10*time.Millisecond, time.Millisecond, time.Millisecond)
+	suite.Equal([]time.Duration{time.Millisecond, 10 * time.Millisecond}, es.buckets)
+
+	success := Labels{Kind: KindTokenParser, Name: "basic", Outcome: OutcomeSuccess}
+	failure := Labels{Kind: KindTokenParser, Name: "basic", Outcome: "bad_credentials"}
+
+	es.Inc(success)
+	es.Observe(success, 500*time.Microsecond)
+	es.Inc(success)
+	es.Observe(success, 5*time.Millisecond)
+	es.Inc(failure)
+	es.Observe(failure, time.Second)
+
+	suite.Equal(
+		map[string]any{
+			"token_parser": map[string]any{
+				"basic": map[string]any{
+					"success": map[string]any{
+						CountKey: 2.0,
+						LatencyKey: map[string]any{
+							"le_1ms":  1.0,
+							"le_10ms": 2.0,
+							InfBucket: 2.0,
+							SumKey:    float64(5500 * time.Microsecond),
+						},
+					},
+					"bad_credentials": map[string]any{
+						CountKey: 1.0,
+						LatencyKey: map[string]any{
+							"le_1ms":  0.0,
+							"le_10ms": 0.0,
+							InfBucket: 1.0,
+							SumKey:    float64(time.Second),
+						},
+					},
+				},
+			},
+		},
+		suite.published(name),
+	)
+}
+
+func TestExpvar(t *testing.T) {
+	suite.Run(t, new(ExpvarTestSuite))
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+// Option is a configurable option for an Instrumenter.
+type Option interface {
+	apply(*Instrumenter) error
+}
+
+type optionFunc func(*Instrumenter) error
+
+func (of optionFunc) apply(in *Instrumenter) error { return of(in) }
+
+// WithSink sets the Sink that receives measurements.  A nil Sink, which is
+// the default, means the sink returned by DefaultSink.
+func WithSink(s Sink) Option {
+	return optionFunc(func(in *Instrumenter) error {
+		in.sink = s
+		return nil
+	})
+}
+
+// WithClock sets the closure used to obtain the current time when measuring
+// latency.  A nil closure, which is the default, means time.Now.
+func WithClock(now func() time.Time) Option {
+	return optionFunc(func(in *Instrumenter) error {
+		in.now = now
+		return nil
+	})
+}
+
+// Instrumenter holds the configuration shared by instrumented components.
+type Instrumenter struct {
+	sink Sink
+	now  func() time.Time
+}
+
+// NewInstrumenter creates an Instrumenter from a set of options.
+func NewInstrumenter(opts ...Option) (*Instrumenter, error) {
+	in := new(Instrumenter)
+	for _, o := range opts {
+		if err := o.apply(in); err != nil {
+			return nil, err
+		}
+	}
+
+	if in.sink == nil {
+		in.sink = DefaultSink()
+	}
+
+	if in.now == nil {
+		in.now = time.Now
+	}
+
+	return in, nil
+}
+
+// defaultInstrumenter returns the given Instrumenter, or a default one if in is nil.
+func defaultInstrumenter(in *Instrumenter) *Instrumenter {
+	if in == nil {
+		in, _ = NewInstrumenter()
+	}
+
+	return in
+}
+
+// record sends the measurements for an invocation that started at the given time.
+func (in *Instrumenter) record(kind Kind, name string, start time.Time, err error) {
+	l := Labels{
+		Kind:    kind,
+		Name:    name,
+		Outcome: Outcome(err),
+	}
+
+	in.sink.Inc(l)
+	in.sink.Observe(l, in.now().Sub(start))
+}
+
+type instrumentedTokenParser[S any] struct {
+	in   *Instrumenter
+	name string
+	next bascule.TokenParser[S]
+}
+
+// Unwrap returns the decorated parser.
+func (itp instrumentedTokenParser[S]) Unwrap() bascule.TokenParser[S] {
+	return itp.next
+}
+
+// Name returns the name under which this parser is measured.
+func (itp instrumentedTokenParser[S]) Name() string {
+	return itp.name
+}
+
+func (itp instrumentedTokenParser[S]) Parse(ctx context.Context, source S) (bascule.Token, error) {
+	start := itp.in.now()
+	t, err := itp.next.Parse(ctx, source)
+	itp.in.record(KindTokenParser, itp.name, start, err)
+	return t, err
+}
+
+// InstrumentTokenParser decorates a TokenParser so that each call to Parse is
+// counted and timed under the given name.  If in is nil, a default Instrumenter
+// is used.
+func InstrumentTokenParser[S any](in *Instrumenter, name string, tp bascule.TokenParser[S]) bascule.TokenParser[S] {
+	return instrumentedTokenParser[S]{
+		in:   defaultInstrumenter(in),
+		name: name,
+		next: tp,
+	}
+}
+
+type instrumentedValidator[S any] struct {
+	in   *Instrumenter
+	name string
+	next bascule.Validator[S]
+}
+
+// Name returns the name under which this validator is measured.
+func (iv instrumentedValidator[S]) Name() string {
+	return iv.name
+}
+
+func (iv instrumentedValidator[S]) Validate(ctx context.Context, source S, t bascule.Token) (bascule.Token, error) {
+	start := iv.in.now()
+	next, err := iv.next.Validate(ctx, source, t)
+	iv.in.record(KindValidator, iv.name, start, err)
+	return next, err
+}
+
+// InstrumentValidator decorates a Validator so that each call to Validate is
+// counted and timed under the given name.  If in is nil, a default Instrumenter
+// is used.
+func InstrumentValidator[S any](in *Instrumenter, name string, v bascule.Validator[S]) bascule.Validator[S] {
+	return instrumentedValidator[S]{
+		in:   defaultInstrumenter(in),
+		name: name,
+		next: v,
+	}
+}
+
+type instrumentedApprover[R any] struct {
+	in   *Instrumenter
+	name string
+	next bascule.Approver[R]
+}
+
+// Name returns the name under which this approver is measured.
+func (ia instrumentedApprover[R]) Name() string {
+	return ia.name
+}
+
+func (ia instrumentedApprover[R]) Approve(ctx context.Context, resource R, t bascule.Token) error {
+	start := ia.in.now()
+	err := ia.next.Approve(ctx, resource, t)
+	ia.in.record(KindApprover, ia.name, start, err)
+	return err
+}
+
+// InstrumentApprover decorates an Approver so that each call to Approve is
+// counted and timed under the given name.  If in is nil, a default Instrumenter
+// is used.
+func InstrumentApprover[R any](in *Instrumenter, name string, a bascule.Approver[R]) bascule.Approver[R] {
+	return instrumentedApprover[R]{
+		in:   defaultInstrumenter(in),
+		name: name,
+		next: a,
+	}
+}
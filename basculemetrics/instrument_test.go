@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type InstrumentTestSuite struct {
+	suite.Suite
+
+	sink *recordingSink
+	in   *Instrumenter
+}
+
+func (suite *InstrumentTestSuite) SetupTest() {
+	suite.sink = new(recordingSink)
+
+	var err error
+	suite.in, err = NewInstrumenter(
+		WithSink(suite.sink),
+		WithClock(steppingClock(time.Millisecond)),
+	)
+
+	suite.Require().NoError(err)
+}
+
+func (suite *InstrumentTestSuite) assertRecorded(expected ...Labels) {
+	counts := make(map[Labels]int)
+	for _, l := range expected {
+		counts[l]++
+	}
+
+	suite.Equal(counts, suite.sink.counts)
+	suite.Require().Len(suite.sink.observations, len(expected))
+	for i, o := range suite.sink.observations {
+		suite.Equal(expected[i], o.labels)
+		suite.Equal(time.Millisecond, o.duration)
+	}
+}
+
+func (suite *InstrumentTestSuite) TestNewInstrumenter() {
+	in, err := NewInstrumenter()
+	suite.Require().NoError(err)
+	suite.Same(DefaultSink(), in.sink)
+	suite.NotNil(in.now)
+}
+
+func (suite *InstrumentTestSuite) TestTokenParser() {
+	tp := InstrumentTokenParser(
+		suite.in,
+		"test",
+		bascule.AsTokenParser[string](func(source string) (bascule.Token, error) {
+			if source == "good" {
+				return bascule.StubToken("joe"), nil
+			}
+
+			return nil, bascule.ErrMissingCredentials
+		}),
+	)
+
+	t, err := tp.Parse(context.Background(), "good")
+	suite.NoError(err)
+	suite.Equal(bascule.StubToken("joe"), t)
+
+	t, err = tp.Parse(context.Background(), "bad")
+	suite.ErrorIs(err, bascule.ErrMissingCredentials)
+	suite.Nil(t)
+
+	suite.assertRecorded(
+		Labels{Kind: KindTokenParser, Name: "test", Outcome: OutcomeSuccess},
+		Labels{Kind: KindTokenParser, Name: "test", Outcome: "missing_credentials"},
+	)
+}
+
+func (suite *InstrumentTestSuite) TestOptionalTokenParser() {
+	tp := InstrumentTokenParser(
+		suite.in,
+		"optional",
+		bascule.OptionalTokenParser(
+			bascule.AsTokenParser[string](func(string) (bascule.Token, error) {
+				return nil, bascule.ErrMissingCredentials
+			}),
+		),
+	)
+
+	suite.True(bascule.IsOptionalTokenParser(tp))
+
+	t, err := bascule.TokenParsers[string]{
+		bascule.StubTokenParser[string]{Token: bascule.StubToken("joe")},
+		tp,
+	}.All().Parse(context.Background(), "source")
+
+	suite.NoError(err)
+	suite.Equal(bascule.StubToken("joe"), t)
+}
+
+func (suite *InstrumentTestSuite) TestValidator() {
+	v := InstrumentValidator(
+		suite.in,
+		"test",
+		bascule.AsValidator[string](func(t bascule.Token) (bascule.Token, error) {
+			if t.Principal() == "joe" {
+				return bascule.StubToken("replaced"), nil
+			}
+
+			return nil, bascule.ErrBadCredentials
+		}),
+	)
+
+	next, err := v.Validate(context.Background(), "source", bascule.StubToken("joe"))
+	suite.NoError(err)
+	suite.Equal(bascule.StubToken("replaced"), next)
+
+	_, err = v.Validate(context.Background(), "source", bascule.StubToken("fred"))
+	suite.ErrorIs(err, bascule.ErrBadCredentials)
+
+	suite.assertRecorded(
+		Labels{Kind: KindValidator, Name: "test", Outcome: OutcomeSuccess},
+		Labels{Kind: KindValidator, Name: "test", Outcome: "bad_credentials"},
+	)
+}
+
+func (suite *InstrumentTestSuite) TestApprover() {
+	a := InstrumentApprover(
+		suite.in,
+		"test",
+		bascule.ApproverFunc[string](func(_ context.Context, resource string, _ bascule.Token) error {
+			if resource == "allowed" {
+				return nil
+			}
+
+			return bascule.ErrUnauthorized
+		}),
+	)
+
+	suite.NoError(a.Approve(context.Background(), "allowed", bascule.StubToken("joe")))
+	suite.ErrorIs(a.Approve(context.Background(), "denied", bascule.StubToken("joe")), bascule.ErrUnauthorized)
+
+	suite.assertRecorded(
+		Labels{Kind: KindApprover, Name: "test", Outcome: OutcomeSuccess},
+		Labels{Kind: KindApprover, Name: "test", Outcome: "unauthorized"},
+	)
+}
+
+func (suite *InstrumentTestSuite) TestNilInstrumenter() {
+	a := InstrumentApprover(
+		nil,
+		"nil-instrumenter",
+		bascule.ApproverFunc[string](func(context.Context, string, bascule.Token) error {
+			return nil
+		}),
+	)
+
+	suite.NoError(a.Approve(context.Background(), "resource", bascule.StubToken("joe")))
+}
+
+func (suite *InstrumentTestSuite) TestNames() {
+	var (
+		tp = InstrumentTokenParser(suite.in, "parser", bascule.TokenParser[string](bascule.StubTokenParser[string]{}))
+		v  = InstrumentValidator(suite.in, "validator", bascule.AsValidator[string](func(bascule.Token) error { return nil }))
+		a  = InstrumentApprover(suite.in, "approver", bascule.ApproverFunc[string](func(context.Context, string, bascule.Token) error { return nil }))
+	)
+
+	suite.Equal("parser", bascule.ComponentName(tp))
+	suite.Equal("validator", bascule.ComponentName(v))
+	suite.Equal("approver", bascule.ComponentName(a))
+}
+
+func TestInstrument(t *testing.T) {
+	suite.Run(t, new(InstrumentTestSuite))
+}
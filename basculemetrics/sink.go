@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+// Kind identifies the type of an instrumented component.
+type Kind string
+
+const (
+	// KindTokenParser is the Kind of an instrumented bascule.TokenParser.
+	KindTokenParser Kind = "token_parser"
+
+	// KindValidator is the Kind of an instrumented bascule.Validator.
+	KindValidator Kind = "validator"
+
+	// KindApprover is the Kind of an instrumented bascule.Approver.
+	KindApprover Kind = "approver"
+)
+
+// OutcomeSuccess is the outcome of an invocation that returned no error.  Any
+// other outcome is the bascule.ErrorCategory of the returned error.
+const OutcomeSuccess = "success"
+
+// Outcome returns the low-cardinality outcome label for an error.
+func Outcome(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+
+	return bascule.CategorizeError(err).String()
+}
+
+// Labels identifies the component and result of a single invocation.
+type Labels struct {
+	// Kind is the type of component that was invoked.
+	Kind Kind
+
+	// Name is the application-supplied name of the component.
+	Name string
+
+	// Outcome is either OutcomeSuccess or the category of the error
+	// returned by the component.
+	Outcome string
+}
+
+// Sink receives measurements from instrumented components.  Implementations
+// must be safe for concurrent use.
+type Sink interface {
+	// Inc increments the invocation counter for the given labels.
+	Inc(Labels)
+
+	// Observe records the latency of a single invocation.
+	Observe(Labels, time.Duration)
+}
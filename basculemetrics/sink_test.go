@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type SinkTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SinkTestSuite) TestOutcome() {
+	suite.Equal(OutcomeSuccess, Outcome(nil))
+	suite.Equal("bad_credentials", Outcome(bascule.ErrBadCredentials))
+	suite.Equal("unauthorized", Outcome(bascule.ErrUnauthorized))
+	suite.Equal("other", Outcome(errors.New("expected")))
+}
+
+func TestSink(t *testing.T) {
+	suite.Run(t, new(SinkTestSuite))
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculemetrics
+
+import (
+	"sync"
+	"time"
+)
+
+type observation struct {
+	labels   Labels
+	duration time.Duration
+}
+
+// recordingSink is a Sink that remembers everything sent to it.
+type recordingSink struct {
+	lock         sync.Mutex
+	counts       map[Labels]int
+	observations []observation
+}
+
+func (rs *recordingSink) Inc(l Labels) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	if rs.counts == nil {
+		rs.counts = make(map[Labels]int)
+	}
+
+	rs.counts[l]++
+}
+
+func (rs *recordingSink) Observe(l Labels, d time.Duration) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	rs.observations = append(rs.observations, observation{labels: l, duration: d})
+}
+
+// steppingClock returns a clock that advances by step on each call.
+func steppingClock(step time.Duration) func() time.Time {
+	var (
+		lock sync.Mutex
+		now  = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	return func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculeotel provides OpenTelemetry tracing for bascule workflows.
+
+Authenticate and Authorize open a span around an entire workflow.  Individual
+token parsers, validators, and approvers can be decorated with TraceTokenParser,
+TraceValidator, and TraceApprover so that each appears as a child span.
+
+A Tracer can also be passed to basculehttp.WithTracing, which traces the workflows of
+a basculehttp.Middleware without basculehttp itself depending on OpenTelemetry.
+
+Spans never carry raw credentials or error text, since either may contain
+secrets.  Failures are described by their bascule.ErrorCategory.  Principals are
+hashed unless WithCleartextPrincipal is used.
+*/
+package basculeotel
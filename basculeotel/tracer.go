@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeotel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/xmidt-org/bascule"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// InstrumentationName is the name of the tracer obtained from the TracerProvider.
+	InstrumentationName = "github.com/xmidt-org/bascule/basculeotel"
+
+	// SpanAuthenticate is the name of the span around an Authenticator.
+	SpanAuthenticate = "bascule.authenticate"
+
+	// SpanAuthorize is the name of the span around an Authorizer.
+	SpanAuthorize = "bascule.authorize"
+
+	// SpanParse is the name of the span around a single TokenParser.
+	SpanParse = "bascule.parse"
+
+	// SpanValidate is the name of the span around a single Validator.
+	SpanValidate = "bascule.validate"
+
+	// SpanApprove is the name of the span around a single Approver.
+	SpanApprove = "bascule.approve"
+
+	// DefaultSchemeHeader is the default HTTP header from which the scheme of an
+	// *http.Request is recorded.
+	DefaultSchemeHeader = "Authorization"
+)
+
+const (
+	// AttrPrincipal is the attribute holding the token's principal.  By default,
+	// this is a hash of the principal.  See WithCleartextPrincipal.
+	AttrPrincipal attribute.Key = "bascule.principal"
+
+	// AttrScheme is the attribute holding the HTTP authorization scheme of
+	// an *http.Request.
+	AttrScheme attribute.Key = "bascule.scheme"
+
+	// AttrCategory is the attribute holding the bascule.ErrorCategory of a failure.
+	AttrCategory attribute.Key = "bascule.error.category"
+
+	// AttrComponent is the attribute holding the application-supplied name
+	// of a parser, validator, or approver.
+	AttrComponent attribute.Key = "bascule.component"
+)
+
+// Option is a configurable option for a Tracer.
+type Option interface {
+	apply(*Tracer) error
+}
+
+type optionFunc func(*Tracer) error
+
+func (of optionFunc) apply(t *Tracer) error { return of(t) }
+
+// WithTracerProvider sets the source of the underlying OpenTelemetry tracer.  A nil
+// provider, which is the default, means the global provider from otel.GetTracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return optionFunc(func(t *Tracer) error {
+		t.provider = tp
+		return nil
+	})
+}
+
+// WithCleartextPrincipal causes principals to be recorded as is.  By default,
+// principals are recorded as hex-encoded SHA-256 digests, which allows spans for
+// the same principal to be correlated without exposing the principal itself.
+//
+// Principals are often personal data, such as user names or email addresses, so
+// this option should only be used when trace data is handled accordingly.
+func WithCleartextPrincipal() Option {
+	return optionFunc(func(t *Tracer) error {
+		t.cleartextPrincipal = true
+		return nil
+	})
+}
+
+// WithSchemeHeader sets the HTTP header from which the scheme of an *http.Request
+// is recorded.  This should be the same header that the request's credentials are
+// parsed from, e.g. the one passed to basculehttp.WithAuthorizationHeader.  If this
+// option is not used or header is blank, DefaultSchemeHeader is used.
+func WithSchemeHeader(header string) Option {
+	return optionFunc(func(t *Tracer) error {
+		t.schemeHeader = header
+		return nil
+	})
+}
+
+// Tracer creates bascule spans.  A Tracer can be passed to basculehttp.WithTracing.
+type Tracer struct {
+	provider           trace.TracerProvider
+	cleartextPrincipal bool
+	schemeHeader       string
+	tracer             trace.Tracer
+}
+
+// NewTracer creates a Tracer from a set of options.
+func NewTracer(opts ...Option) (*Tracer, error) {
+	t := new(Tracer)
+	for _, o := range opts {
+		if err := o.apply(t); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.provider == nil {
+		t.provider = otel.GetTracerProvider()
+	}
+
+	if len(t.schemeHeader) == 0 {
+		t.schemeHeader = DefaultSchemeHeader
+	}
+
+	t.tracer = t.provider.Tracer(InstrumentationName)
+	return t, nil
+}
+
+// defaultTracer returns the given Tracer, or a default one if t is nil.
+func defaultTracer(t *Tracer) *Tracer {
+	if t == nil {
+		t, _ = NewTracer()
+	}
+
+	return t
+}
+
+// start begins a span.  If the subject is an *http.Request with this tracer's scheme
+// header, the scheme is recorded.
+func (t *Tracer) start(ctx context.Context, name string, subject any, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if request, ok := subject.(*http.Request); ok && request != nil {
+		if scheme, _, ok := strings.Cut(request.Header.Get(t.schemeHeader), " "); ok && len(scheme) > 0 {
+			attrs = append(attrs, AttrScheme.String(scheme))
+		}
+	}
+
+	return t.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// principal produces the recorded form of a principal.
+func (t *Tracer) principal(p string) string {
+	if t.cleartextPrincipal {
+		return p
+	}
+
+	digest := sha256.Sum256([]byte(p))
+	return hex.EncodeToString(digest[:])
+}
+
+// end records the result of a workflow step and ends the span.
+func (t *Tracer) end(span trace.Span, token bascule.Token, err error) {
+	if token != nil {
+		span.SetAttributes(AttrPrincipal.String(t.principal(token.Principal())))
+	}
+
+	if err != nil {
+		category := bascule.CategorizeError(err).String()
+		span.SetAttributes(AttrCategory.String(category))
+		span.SetStatus(codes.Error, category)
+	}
+
+	span.End()
+}
+
+// TraceAuthenticate runs an authentication closure within a SpanAuthenticate span.
+// The span's context is passed to the closure.  The source is used only to annotate
+// the span, e.g. with the scheme of an *http.Request.
+func (t *Tracer) TraceAuthenticate(ctx context.Context, source any, authenticate func(context.Context) (bascule.Token, error)) (bascule.Token, error) {
+	ctx, span := t.start(ctx, SpanAuthenticate, source)
+	token, err := authenticate(ctx)
+	t.end(span, token, err)
+	return token, err
+}
+
+// TraceAuthorize runs an authorization closure within a SpanAuthorize span.  The
+// span's context is passed to the closure.  The resource is used only to annotate
+// the span, e.g. with the scheme of an *http.Request.
+func (t *Tracer) TraceAuthorize(ctx context.Context, resource any, token bascule.Token, authorize func(context.Context) error) error {
+	ctx, span := t.start(ctx, SpanAuthorize, resource)
+	err := authorize(ctx)
+	t.end(span, token, err)
+	return err
+}
+
+// Authenticate runs an Authenticator within a SpanAuthenticate span.  The span's
+// context is passed to the Authenticator, so any traced parsers or validators
+// produce child spans.  If t is nil, a default Tracer is used.
+func Authenticate[S any](ctx context.Context, t *Tracer, a *bascule.Authenticator[S], source S) (bascule.Token, error) {
+	return defaultTracer(t).TraceAuthenticate(ctx, source, func(ctx context.Context) (bascule.Token, error) {
+		return a.Authenticate(ctx, source)
+	})
+}
+
+// Authorize runs an Authorizer within a SpanAuthorize span.  The span's context is
+// passed to the Authorizer, so any traced approvers produce child spans.  If t is nil,
+// a default Tracer is used.
+func Authorize[R any](ctx context.Context, t *Tracer, a *bascule.Authorizer[R], resource R, token bascule.Token) error {
+	return defaultTracer(t).TraceAuthorize(ctx, resource, token, func(ctx context.Context) error {
+		return a.Authorize(ctx, resource, token)
+	})
+}
+
+type tracedTokenParser[S any] struct {
+	t    *Tracer
+	name string
+	next bascule.TokenParser[S]
+}
+
+// Name returns the name this parser was traced under.
+func (ttp tracedTokenParser[S]) Name() string {
+	return ttp.name
+}
+
+// Unwrap returns the decorated parser.
+func (ttp tracedTokenParser[S]) Unwrap() bascule.TokenParser[S] {
+	return ttp.next
+}
+
+func (ttp tracedTokenParser[S]) Parse(ctx context.Context, source S) (bascule.Token, error) {
+	ctx, span := ttp.t.start(ctx, SpanParse, source, AttrComponent.String(ttp.name))
+	token, err := ttp.next.Parse(ctx, source)
+	ttp.t.end(span, token, err)
+	return token, err
+}
+
+// TraceTokenParser decorates a TokenParser so that each call to Parse produces a
+// SpanParse span.  If t is nil, a default Tracer is used.
+func TraceTokenParser[S any](t *Tracer, name string, tp bascule.TokenParser[S]) bascule.TokenParser[S] {
+	return tracedTokenParser[S]{
+		t:    defaultTracer(t),
+		name: name,
+		next: tp,
+	}
+}
+
+type tracedValidator[S any] struct {
+	t    *Tracer
+	name string
+	next bascule.Validator[S]
+}
+
+// Name returns the name this validator was traced under.
+func (tv tracedValidator[S]) Name() string {
+	return tv.name
+}
+
+func (tv tracedValidator[S]) Validate(ctx context.Context, source S, token bascule.Token) (bascule.Token, error) {
+	ctx, span := tv.t.start(ctx, SpanValidate, source, AttrComponent.String(tv.name))
+	next, err := tv.next.Validate(ctx, source, token)
+	if next != nil {
+		tv.t.end(span, next, err)
+	} else {
+		tv.t.end(span, token, err)
+	}
+
+	return next, err
+}
+
+// TraceValidator decorates a Validator so that each call to Validate produces a
+// SpanValidate span.  If t is nil, a default Tracer is used.
+func TraceValidator[S any](t *Tracer, name string, v bascule.Validator[S]) bascule.Validator[S] {
+	return tracedValidator[S]{
+		t:    defaultTracer(t),
+		name: name,
+		next: v,
+	}
+}
+
+type tracedApprover[R any] struct {
+	t    *Tracer
+	name string
+	next bascule.Approver[R]
+}
+
+// Name returns the name this approver was traced under.
+func (ta tracedApprover[R]) Name() string {
+	return ta.name
+}
+
+func (ta tracedApprover[R]) Approve(ctx context.Context, resource R, token bascule.Token) error {
+	ctx, span := ta.t.start(ctx, SpanApprove, resource, AttrComponent.String(ta.name))
+	err := ta.next.Approve(ctx, resource, token)
+	ta.t.end(span, token, err)
+	return err
+}
+
+// TraceApprover decorates an Approver so that each call to Approve produces a
+// SpanApprove span.  If t is nil, a default Tracer is used.
+func TraceApprover[R any](t *Tracer, name string, a bascule.Approver[R]) bascule.Approver[R] {
+	return tracedApprover[R]{
+		t:    defaultTracer(t),
+		name: name,
+		next: a,
+	}
+}
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeotel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type TracerTestSuite struct {
+	suite.Suite
+
+	recorder *tracetest.SpanRecorder
+	provider *sdktrace.TracerProvider
+}
+
+func (suite *TracerTestSuite) SetupTest() {
+	suite.recorder = tracetest.NewSpanRecorder()
+	suite.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(suite.recorder),
+	)
+}
+
+func (suite *TracerTestSuite) TearDownTest() {
+	suite.NoError(suite.provider.Shutdown(context.Background()))
+}
+
+func (suite *TracerTestSuite) newTracer(opts ...Option) *Tracer {
+	t, err := NewTracer(append([]Option{WithTracerProvider(suite.provider)}, opts...)...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(t)
+	return t
+}
+
+// spanAttributes returns the attributes of an ended span as a map.
+func (suite *TracerTestSuite) spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]string {
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+
+	return attrs
+}
+
+func (suite *TracerTestSuite) newParser() bascule.TokenParser[*http.Request] {
+	return bascule.AsTokenParser[*http.Request](func(request *http.Request) (bascule.Token, error) {
+		if user, _, ok := request.BasicAuth(); ok {
+			return bascule.StubToken(user), nil
+		}
+
+		return nil, bascule.ErrMissingCredentials
+	})
+}
+
+func (suite *TracerTestSuite) newAuthenticator(t *Tracer) *bascule.Authenticator[*http.Request] {
+	a, err := bascule.NewAuthenticator(
+		bascule.WithTokenParsers(TraceTokenParser(t, "basic", suite.newParser())),
+		bascule.WithValidators(
+			TraceValidator(t, "check", bascule.AsValidator[*http.Request](func(token bascule.Token) error {
+				if token.Principal() == "fred" {
+					return bascule.ErrBadCredentials
+				}
+
+				return nil
+			})),
+		),
+	)
+
+	suite.Require().NoError(err)
+	return a
+}
+
+func (suite *TracerTestSuite) TestAuthenticate() {
+	suite.Run("Success", func() {
+		suite.SetupTest()
+		t := suite.newTracer(WithCleartextPrincipal())
+		a := suite.newAuthenticator(t)
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.SetBasicAuth("joe", "password")
+		token, err := Authenticate(context.Background(), t, a, request)
+		suite.NoError(err)
+		suite.Equal("joe", token.Principal())
+
+		spans := suite.recorder.Ended()
+		suite.Require().Len(spans, 3)
+		suite.Equal(SpanParse, spans[0].Name())
+		suite.Equal(SpanValidate, spans[1].Name())
+		suite.Equal(SpanAuthenticate, spans[2].Name())
+
+		root := spans[2]
+		for _, child := range spans[:2] {
+			suite.Equal(root.SpanContext().SpanID(), child.Parent().SpanID())
+		}
+
+		suite.Equal(
+			map[attribute.Key]string{
+				AttrScheme:    "Basic",
+				AttrPrincipal: "joe",
+			},
+			suite.spanAttributes(root),
+		)
+
+		suite.Equal(
+			map[attribute.Key]string{
+				AttrScheme:    "Basic",
+				AttrPrincipal: "joe",
+				AttrComponent: "basic",
+			},
+			suite.spanAttributes(spans[0]),
+		)
+
+		suite.Equal(codes.Unset, root.Status().Code)
+	})
+
+	suite.Run("ValidationFailure", func() {
+		suite.SetupTest()
+		t := suite.newTracer()
+		a := suite.newAuthenticator(t)
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.SetBasicAuth("fred", "password")
+		_, err := Authenticate(context.Background(), t, a, request)
+		suite.ErrorIs(err, bascule.ErrBadCredentials)
+
+		spans := suite.recorder.Ended()
+		suite.Require().Len(spans, 3)
+		for _, span := range spans[1:] {
+			attrs := suite.spanAttributes(span)
+			suite.Equal(string(bascule.CategoryBadCredentials), attrs[AttrCategory])
+			suite.Equal(codes.Error, span.Status().Code)
+			suite.Equal(string(bascule.CategoryBadCredentials), span.Status().Description)
+		}
+	})
+
+	suite.Run("MissingCredentials", func() {
+		suite.SetupTest()
+		t := suite.newTracer()
+		a := suite.newAuthenticator(t)
+
+		_, err := Authenticate(context.Background(), t, a, httptest.NewRequest("GET", "/", nil))
+		suite.ErrorIs(err, bascule.ErrMissingCredentials)
+
+		spans := suite.recorder.Ended()
+		suite.Require().Len(spans, 2)
+		suite.Equal(
+			map[attribute.Key]string{
+				AttrCategory: string(bascule.CategoryMissingCredentials),
+			},
+			suite.spanAttributes(spans[1]),
+		)
+	})
+}
+
+func (suite *TracerTestSuite) TestSchemeHeader() {
+	suite.Run("Default", func() {
+		suite.SetupTest()
+		t := suite.newTracer()
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("X-Custom-Auth", "Bearer xyz")
+		_, err := t.TraceAuthenticate(context.Background(), request, func(context.Context) (bascule.Token, error) {
+			return nil, bascule.ErrMissingCredentials
+		})
+
+		suite.ErrorIs(err, bascule.ErrMissingCredentials)
+		spans := suite.recorder.Ended()
+		suite.Require().Len(spans, 1)
+		suite.NotContains(suite.spanAttributes(spans[0]), AttrScheme)
+	})
+
+	suite.Run("Custom", func() {
+		suite.SetupTest()
+		t := suite.newTracer(WithSchemeHeader("X-Custom-Auth"))
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("X-Custom-Auth", "Bearer xyz")
+		request.SetBasicAuth("joe", "password")
+		_, err := t.TraceAuthenticate(context.Background(), request, func(context.Context) (bascule.Token, error) {
+			return bascule.StubToken("joe"), nil
+		})
+
+		suite.NoError(err)
+		spans := suite.recorder.Ended()
+		suite.Require().Len(spans, 1)
+		suite.Equal("Bearer", suite.spanAttributes(spans[0])[AttrScheme])
+	})
+}
+
+func (suite *TracerTestSuite) TestAuthorize() {
+	t := suite.newTracer()
+	a, err := bascule.NewAuthorizer(
+		bascule.WithApprovers(
+			TraceApprover(t, "deny", bascule.ApproverFunc[string](func(context.Context, string, bascule.Token) error {
+				return fmt.Errorf("secret text: %w", bascule.ErrUnauthorized)
+			})),
+		),
+	)
+
+	suite.Require().NoError(err)
+	err = Authorize(context.Background(), t, a, "resource", bascule.StubToken("joe"))
+	suite.ErrorIs(err, bascule.ErrUnauthorized)
+
+	digest := sha256.Sum256([]byte("joe"))
+	spans := suite.recorder.Ended()
+	suite.Require().Len(spans, 2)
+	suite.Equal(SpanApprove, spans[0].Name())
+	suite.Equal(SpanAuthorize, spans[1].Name())
+	suite.Equal(
+		map[attribute.Key]string{
+			AttrPrincipal: hex.EncodeToString(digest[:]),
+			AttrCategory:  string(bascule.CategoryUnauthorized),
+		},
+		suite.spanAttributes(spans[1]),
+	)
+
+	for _, span := range spans {
+		suite.NotContains(span.Status().Description, "secret")
+		suite.Empty(span.Events())
+	}
+}
+
+func (suite *TracerTestSuite) TestValidatorReplacesToken() {
+	t := suite.newTracer(WithCleartextPrincipal())
+	v := TraceValidator(t, "replace", bascule.AsValidator[string](func(bascule.Token) (bascule.Token, error) {
+		return bascule.StubToken("replaced"), nil
+	}))
+
+	next, err := v.Validate(context.Background(), "source", bascule.StubToken("joe"))
+	suite.NoError(err)
+	suite.Equal(bascule.StubToken("replaced"), next)
+
+	spans := suite.recorder.Ended()
+	suite.Require().Len(spans, 1)
+	suite.Equal("replaced", suite.spanAttributes(spans[0])[AttrPrincipal])
+}
+
+func (suite *TracerTestSuite) TestNames() {
+	var (
+		t  = suite.newTracer()
+		tp = TraceTokenParser(t, "parser", bascule.TokenParser[string](bascule.StubTokenParser[string]{}))
+		v  = TraceValidator(t, "validator", bascule.AsValidator[string](func(bascule.Token) error { return nil }))
+		a  = TraceApprover(t, "approver", bascule.ApproverFunc[string](func(context.Context, string, bascule.Token) error { return nil }))
+	)
+
+	suite.Equal("parser", bascule.ComponentName(tp))
+	suite.Equal("validator", bascule.ComponentName(v))
+	suite.Equal("approver", bascule.ComponentName(a))
+}
+
+func (suite *TracerTestSuite) TestNilTracer() {
+	tp := TraceTokenParser[string](nil, "nil", bascule.AsTokenParser[string](func(string) (bascule.Token, error) {
+		return nil, errors.New("expected")
+	}))
+
+	_, err := tp.Parse(context.Background(), "source")
+	suite.Error(err)
+}
+
+func TestTracer(t *testing.T) {
+	suite.Run(t, new(TracerTestSuite))
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculepolicy provides a small expression language for authorization rules.
+A policy is parsed and compiled once, and the result is a bascule.Approver that
+can be used anywhere approvers are accepted, e.g. bascule.WithApprovers.
+
+A policy is a boolean expression.  For example:
+
+	principal == "admin" || ("owner" in attributes.roles && !attributes.suspended)
+	capabilities contains "device:write" and resource.method in ["GET", "HEAD"]
+	resource.path matches "^/api/v[0-9]+/" && resource.headers["X-Tenant"] == attributes.tenant
+
+The grammar is:
+
+	policy     = or
+	or         = and { ( "||" | "or" ) and }
+	and        = not { ( "&&" | "and" ) not }
+	not        = ( "!" | "not" ) not | comparison
+	comparison = operand [ operator operand ]
+	operator   = "==" | "!=" | "<" | "<=" | ">" | ">=" |
+	             "in" | "contains" | "matches" | "startsWith" | "endsWith"
+	operand    = "(" or ")" | list | string | number | "true" | "false" | reference
+	list       = "[" [ operand { "," operand } ] "]"
+	reference  = identifier { "." identifier | "[" string "]" }
+
+Strings may be double quoted, using Go escapes, or single quoted without escapes.
+Identifiers may contain letters, digits, underscores, and dashes.
+
+References must begin with one of the following roots:
+
+	principal     the token's principal
+	capabilities  the token's capabilities, via bascule.GetCapabilities
+	attributes    a token attribute, via bascule.GetAttribute, e.g. attributes.realm.roles
+	resource      a field of the resource, as supplied by a Resolver
+
+The right-hand side of matches must be a string literal, which is compiled as a
+regular expression along with the policy.  The contains and in operators test for
+an exact element of a list, or a substring of a string; they do not interpret their
+operands as patterns.
+
+References that cannot be resolved at evaluation time, as well as comparisons between
+values of different types, are simply false.  This applies to != as well, so a rule such
+as attributes.tenant != "blocked" is false when the token has no tenant.  A bare operand
+is true only if it evaluates to the boolean true.
+*/
+package basculepolicy
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseError describes a syntax or semantic error in a policy, along with
+// the position in the policy text where the error occurred.
+type ParseError struct {
+	// Offset is the zero-based byte offset into the policy text.
+	Offset int
+
+	// Line is the one-based line number of the error.
+	Line int
+
+	// Column is the one-based column, in characters, of the error.
+	Column int
+
+	// Msg describes the error.
+	Msg string
+}
+
+func (pe *ParseError) Error() string {
+	var o strings.Builder
+	o.WriteString(strconv.Itoa(pe.Line))
+	o.WriteRune(':')
+	o.WriteString(strconv.Itoa(pe.Column))
+	o.WriteString(": ")
+	o.WriteString(pe.Msg)
+	return o.String()
+}
+
+// newParseError creates a ParseError at the given offset in src.
+func newParseError(src string, offset int, msg string) *ParseError {
+	pe := &ParseError{
+		Offset: offset,
+		Line:   1,
+		Column: 1,
+		Msg:    msg,
+	}
+
+	for _, r := range src[:offset] {
+		if r == '\n' {
+			pe.Line++
+			pe.Column = 1
+		} else {
+			pe.Column++
+		}
+	}
+
+	return pe
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+// lexeme is a single token from a policy's text.
+type lexeme struct {
+	kind   tokenKind
+	text   string // the raw text, or the unquoted value for strings
+	offset int
+}
+
+func (l lexeme) is(kind tokenKind, text string) bool {
+	return l.kind == kind && l.text == text
+}
+
+func (l lexeme) describe() string {
+	switch l.kind {
+	case tokenEOF:
+		return "end of policy"
+
+	case tokenString:
+		return strconv.Quote(l.text)
+
+	default:
+		return "'" + l.text + "'"
+	}
+}
+
+// twoCharPuncts are the punctuation tokens that span two characters.
+var twoCharPuncts = []string{"==", "!=", "<=", ">=", "&&", "||"}
+
+func isWordStart(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isWordPart(b byte) bool {
+	return isWordStart(b) || isDigit(b) || b == '-'
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// lex breaks a policy's text into lexemes.  The returned slice always
+// ends with a tokenEOF lexeme.
+func lex(src string) (lexemes []lexeme, err error) {
+	for i := 0; i < len(src); {
+		b := src[i]
+		switch {
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r':
+			i++
+
+		case isWordStart(b):
+			start := i
+			for i < len(src) && isWordPart(src[i]) {
+				i++
+			}
+
+			lexemes = append(lexemes, lexeme{kind: tokenWord, text: src[start:i], offset: start})
+
+		case isDigit(b) || (b == '-' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			i++
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+
+			if _, parseErr := strconv.ParseFloat(src[start:i], 64); parseErr != nil {
+				return nil, newParseError(src, start, "invalid number '"+src[start:i]+"'")
+			}
+
+			lexemes = append(lexemes, lexeme{kind: tokenNumber, text: src[start:i], offset: start})
+
+		case b == '"' || b == '\'':
+			var value string
+			start := i
+			value, i, err = lexString(src, i)
+			if err != nil {
+				return
+			}
+
+			lexemes = append(lexemes, lexeme{kind: tokenString, text: value, offset: start})
+
+		default:
+			matched := false
+			for _, p := range twoCharPuncts {
+				if strings.HasPrefix(src[i:], p) {
+					lexemes = append(lexemes, lexeme{kind: tokenPunct, text: p, offset: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				if !strings.ContainsRune("()[],.<>!", rune(b)) {
+					r, _ := utf8.DecodeRuneInString(src[i:])
+					return nil, newParseError(src, i, "unexpected character "+strconv.QuoteRune(r))
+				}
+
+				lexemes = append(lexemes, lexeme{kind: tokenPunct, text: src[i : i+1], offset: i})
+				i++
+			}
+		}
+	}
+
+	lexemes = append(lexemes, lexeme{kind: tokenEOF, offset: len(src)})
+	return
+}
+
+// lexString scans a quoted string beginning at src[start], returning the unquoted
+// value and the offset just past the closing quote.
+func lexString(src string, start int) (value string, next int, err error) {
+	quote := src[start]
+	for next = start + 1; next < len(src); next++ {
+		switch {
+		case src[next] == '\\' && quote == '"':
+			next++ // skip the escaped character
+
+		case src[next] == quote:
+			next++
+			if quote == '\'' {
+				value = src[start+1 : next-1]
+			} else if value, err = strconv.Unquote(src[start:next]); err != nil {
+				err = newParseError(src, start, "invalid string literal")
+			}
+
+			return
+		}
+	}
+
+	err = newParseError(src, start, "unterminated string literal")
+	return
+}
@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"strconv"
+)
+
+const (
+	opAnd        = "&&"
+	opOr         = "||"
+	opNot        = "!"
+	opEqual      = "=="
+	opNotEqual   = "!="
+	opLess       = "<"
+	opLessEq     = "<="
+	opGreater    = ">"
+	opGreaterEq  = ">="
+	opIn         = "in"
+	opContains   = "contains"
+	opMatches    = "matches"
+	opStartsWith = "startsWith"
+	opEndsWith   = "endsWith"
+)
+
+// wordOperators maps keyword spellings of the logical operators onto their
+// symbolic equivalents.
+var wordOperators = map[string]string{
+	"and": opAnd,
+	"or":  opOr,
+	"not": opNot,
+}
+
+// comparisonOperators is the set of binary comparison operators, in both
+// symbolic and keyword form.
+var comparisonOperators = map[string]bool{
+	opEqual:      true,
+	opNotEqual:   true,
+	opLess:       true,
+	opLessEq:     true,
+	opGreater:    true,
+	opGreaterEq:  true,
+	opIn:         true,
+	opContains:   true,
+	opMatches:    true,
+	opStartsWith: true,
+	opEndsWith:   true,
+}
+
+// expr is a node in a parsed policy's syntax tree.
+type expr interface {
+	// offset is the position in the policy text where this node begins.
+	offset() int
+}
+
+type logicalExpr struct {
+	op          string // opAnd or opOr
+	left, right expr
+	at          int
+}
+
+func (e *logicalExpr) offset() int { return e.at }
+
+type notExpr struct {
+	operand expr
+	at      int
+}
+
+func (e *notExpr) offset() int { return e.at }
+
+type compareExpr struct {
+	op          string
+	left, right expr
+	at          int // the offset of the operator
+}
+
+func (e *compareExpr) offset() int { return e.left.offset() }
+
+type literalExpr struct {
+	value any // string, float64, or bool
+	at    int
+}
+
+func (e *literalExpr) offset() int { return e.at }
+
+type listExpr struct {
+	elements []expr
+	at       int
+}
+
+func (e *listExpr) offset() int { return e.at }
+
+type referenceExpr struct {
+	root string
+	path []string
+	at   int
+}
+
+func (e *referenceExpr) offset() int { return e.at }
+
+// parser is a recursive descent parser for policies.
+type parser struct {
+	src     string
+	lexemes []lexeme
+	pos     int
+}
+
+// parse turns policy text into a syntax tree.
+func parse(src string) (expr, error) {
+	lexemes, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{
+		src:     src,
+		lexemes: lexemes,
+	}
+
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty policy")
+	}
+
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEOF {
+		err = p.errorf(p.peek(), "unexpected "+p.peek().describe())
+	}
+
+	return root, err
+}
+
+func (p *parser) peek() lexeme {
+	return p.lexemes[p.pos]
+}
+
+func (p *parser) next() lexeme {
+	l := p.lexemes[p.pos]
+	if l.kind != tokenEOF {
+		p.pos++
+	}
+
+	return l
+}
+
+func (p *parser) errorf(at lexeme, msg string) error {
+	return newParseError(p.src, at.offset, msg)
+}
+
+func (p *parser) expect(kind tokenKind, text string) (lexeme, error) {
+	l := p.next()
+	if !l.is(kind, text) {
+		return l, p.errorf(l, "expected '"+text+"' but found "+l.describe())
+	}
+
+	return l, nil
+}
+
+// logicalOp returns the normalized logical operator for the next lexeme, if any.
+func (p *parser) logicalOp() string {
+	l := p.peek()
+	switch l.kind {
+	case tokenPunct:
+		return l.text
+
+	case tokenWord:
+		return wordOperators[l.text]
+
+	default:
+		return ""
+	}
+}
+
+func (p *parser) parseOr() (expr, error) {
+	return p.parseLogical(opOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	return p.parseLogical(opAnd, p.parseNot)
+}
+
+func (p *parser) parseLogical(op string, operand func() (expr, error)) (expr, error) {
+	left, err := operand()
+	for err == nil && p.logicalOp() == op {
+		at := p.next().offset
+
+		var right expr
+		if right, err = operand(); err == nil {
+			left = &logicalExpr{
+				op:    op,
+				left:  left,
+				right: right,
+				at:    at,
+			}
+		}
+	}
+
+	return left, err
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.logicalOp() == opNot {
+		at := p.next().offset
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notExpr{operand: operand, at: at}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	l := p.peek()
+	if (l.kind != tokenPunct && l.kind != tokenWord) || !comparisonOperators[l.text] {
+		return left, nil
+	}
+
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &compareExpr{
+		op:    l.text,
+		left:  left,
+		right: right,
+		at:    l.offset,
+	}, nil
+}
+
+func (p *parser) parseOperand() (expr, error) {
+	l := p.next()
+	switch {
+	case l.is(tokenPunct, "("):
+		inner, err := p.parseOr()
+		if err == nil {
+			_, err = p.expect(tokenPunct, ")")
+		}
+
+		return inner, err
+
+	case l.is(tokenPunct, "["):
+		return p.parseList(l)
+
+	case l.kind == tokenString:
+		return &literalExpr{value: l.text, at: l.offset}, nil
+
+	case l.kind == tokenNumber:
+		// the lexer has already verified that this is a valid number
+		v, _ := strconv.ParseFloat(l.text, 64)
+		return &literalExpr{value: v, at: l.offset}, nil
+
+	case l.is(tokenWord, "true"):
+		return &literalExpr{value: true, at: l.offset}, nil
+
+	case l.is(tokenWord, "false"):
+		return &literalExpr{value: false, at: l.offset}, nil
+
+	case l.kind == tokenWord && len(wordOperators[l.text]) == 0 && !comparisonOperators[l.text]:
+		return p.parseReference(l)
+
+	default:
+		return nil, p.errorf(l, "unexpected "+l.describe())
+	}
+}
+
+func (p *parser) parseList(open lexeme) (expr, error) {
+	list := &listExpr{at: open.offset}
+	if p.peek().is(tokenPunct, "]") {
+		p.next()
+		return list, nil
+	}
+
+	for {
+		element, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		list.elements = append(list.elements, element)
+		l := p.next()
+		switch {
+		case l.is(tokenPunct, "]"):
+			return list, nil
+
+		case !l.is(tokenPunct, ","):
+			return nil, p.errorf(l, "expected ',' or ']' but found "+l.describe())
+		}
+	}
+}
+
+func (p *parser) parseReference(root lexeme) (expr, error) {
+	ref := &referenceExpr{
+		root: root.text,
+		at:   root.offset,
+	}
+
+	for {
+		l := p.peek()
+		switch {
+		case l.is(tokenPunct, "."):
+			p.next()
+			segment := p.next()
+			if segment.kind != tokenWord {
+				return nil, p.errorf(segment, "expected a name but found "+segment.describe())
+			}
+
+			ref.path = append(ref.path, segment.text)
+
+		case l.is(tokenPunct, "["):
+			p.next()
+			key := p.next()
+			if key.kind != tokenString {
+				return nil, p.errorf(key, "expected a quoted key but found "+key.describe())
+			}
+
+			if _, err := p.expect(tokenPunct, "]"); err != nil {
+				return nil, err
+			}
+
+			ref.path = append(ref.path, key.text)
+
+		default:
+			return ref, nil
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ParserTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ParserTestSuite) TestValid() {
+	testCases := []string{
+		`true`,
+		`principal == "joe"`,
+		`principal == 'joe'`,
+		`not principal == "joe"`,
+		`!(principal == "joe")`,
+		`principal == "joe" and capabilities contains "x" or false`,
+		`principal == "joe" && (capabilities contains "x" || false)`,
+		`attributes.realm.roles contains "admin"`,
+		`attributes["odd.key"]["in"] == 1`,
+		`attributes.level >= -2.5`,
+		`resource.method in []`,
+		`resource.method in ["GET", "HEAD"]`,
+		`resource.headers.X-Tenant == attributes.tenant`,
+		`resource.path matches "^/api/"`,
+		`resource.path startsWith "/api" && resource.path endsWith ".json"`,
+		"principal == \"joe\"\n\t|| principal == \"fred\"",
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase, func() {
+			p, err := CompileHTTP(testCase)
+			suite.Require().NoError(err)
+			suite.Require().NotNil(p)
+			suite.Equal(testCase, p.String())
+		})
+	}
+}
+
+func (suite *ParserTestSuite) TestInvalid() {
+	testCases := []struct {
+		src            string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{src: ``, expectedLine: 1, expectedColumn: 1},
+		{src: `   `, expectedLine: 1, expectedColumn: 4},
+		{src: `principal ==`, expectedLine: 1, expectedColumn: 13},
+		{src: `principal == "joe" extra`, expectedLine: 1, expectedColumn: 20},
+		{src: `principal == "unterminated`, expectedLine: 1, expectedColumn: 14},
+		{src: `principal == "bad \q escape"`, expectedLine: 1, expectedColumn: 14},
+		{src: `principal # "joe"`, expectedLine: 1, expectedColumn: 11},
+		{src: `1.2.3 == 1`, expectedLine: 1, expectedColumn: 1},
+		{src: `(principal == "joe"`, expectedLine: 1, expectedColumn: 20},
+		{src: `principal in ["a" "b"]`, expectedLine: 1, expectedColumn: 19},
+		{src: `attributes.`, expectedLine: 1, expectedColumn: 12},
+		{src: `attributes[1]`, expectedLine: 1, expectedColumn: 12},
+		{src: `attributes["a"`, expectedLine: 1, expectedColumn: 15},
+		{src: `principal == and`, expectedLine: 1, expectedColumn: 14},
+		{src: "true &&\n  nosuch == 1", expectedLine: 2, expectedColumn: 3},
+		{src: `principal.name == "joe"`, expectedLine: 1, expectedColumn: 1},
+		{src: `capabilities.first == "x"`, expectedLine: 1, expectedColumn: 1},
+		{src: `attributes == "x"`, expectedLine: 1, expectedColumn: 1},
+		{src: `principal matches attributes.pattern`, expectedLine: 1, expectedColumn: 19},
+		{src: `principal matches "("`, expectedLine: 1, expectedColumn: 19},
+		{src: `!nosuch`, expectedLine: 1, expectedColumn: 2},
+		{src: `true || nosuch`, expectedLine: 1, expectedColumn: 9},
+		{src: `[nosuch]`, expectedLine: 1, expectedColumn: 2},
+		{src: `nosuch == 1`, expectedLine: 1, expectedColumn: 1},
+		{src: `1 == nosuch`, expectedLine: 1, expectedColumn: 6},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.src, func() {
+			p, err := CompileHTTP(testCase.src)
+			suite.Nil(p)
+
+			var pe *ParseError
+			suite.Require().ErrorAs(err, &pe)
+			suite.Equal(testCase.expectedLine, pe.Line, pe.Error())
+			suite.Equal(testCase.expectedColumn, pe.Column, pe.Error())
+			suite.NotEmpty(pe.Msg)
+		})
+	}
+}
+
+func (suite *ParserTestSuite) TestNoResolver() {
+	p, err := Compile[*http.Request](`resource.method == "GET"`)
+	suite.Nil(p)
+
+	var pe *ParseError
+	suite.Require().ErrorAs(err, &pe)
+	suite.Equal(1, pe.Column)
+}
+
+func (suite *ParserTestSuite) TestMustCompile() {
+	suite.Panics(func() {
+		MustCompile[*http.Request](`principal ==`)
+	})
+
+	suite.NotPanics(func() {
+		MustCompile[*http.Request](`principal == "joe"`)
+	})
+}
+
+func TestParser(t *testing.T) {
+	suite.Run(t, new(ParserTestSuite))
+}
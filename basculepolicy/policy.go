@@ -0,0 +1,309 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	rootPrincipal    = "principal"
+	rootCapabilities = "capabilities"
+	rootAttributes   = "attributes"
+	rootResource     = "resource"
+)
+
+// DeniedError is returned by a Policy when its expression does not evaluate to true.
+// This error always has bascule.ErrUnauthorized in its chain.
+type DeniedError struct {
+	// Policy is the source text of the policy that denied access.
+	Policy string
+}
+
+// Unwrap returns bascule.ErrUnauthorized.
+func (de *DeniedError) Unwrap() error {
+	return bascule.ErrUnauthorized
+}
+
+func (de *DeniedError) Error() string {
+	var o strings.Builder
+	o.WriteString("policy denied access: ")
+	o.WriteString(de.Policy)
+	return o.String()
+}
+
+// evaluation holds the inputs to a single evaluation of a policy.
+type evaluation[R any] struct {
+	resource R
+	token    bascule.Token
+}
+
+// evaluator is the compiled form of an expression.
+type evaluator[R any] func(*evaluation[R]) any
+
+// Option is a configurable option for compiling a Policy.
+type Option[R any] interface {
+	apply(*Policy[R]) error
+}
+
+type optionFunc[R any] func(*Policy[R]) error
+
+//nolint:unused
+func (of optionFunc[R]) apply(p *Policy[R]) error { return of(p) }
+
+// WithResolver sets the Resolver used for resource references.  Without a
+// Resolver, any policy that references the resource fails to compile.
+func WithResolver[R any](r Resolver[R]) Option[R] {
+	return optionFunc[R](func(p *Policy[R]) error {
+		p.resolver = r
+		return nil
+	})
+}
+
+// Policy is a compiled policy expression.  A Policy is a bascule.Approver,
+// and is safe for concurrent use.
+type Policy[R any] struct {
+	src      string
+	resolver Resolver[R]
+	eval     evaluator[R]
+}
+
+// Compile parses and compiles a policy.  Any syntax or semantic error in the
+// policy text is returned as a *ParseError.
+func Compile[R any](src string, opts ...Option[R]) (*Policy[R], error) {
+	p := &Policy[R]{
+		src: src,
+	}
+
+	for _, o := range opts {
+		if err := o.apply(p); err != nil {
+			return nil, err
+		}
+	}
+
+	root, err := parse(src)
+	if err == nil {
+		p.eval, err = p.compile(root)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// MustCompile is like Compile, but panics on any error.
+func MustCompile[R any](src string, opts ...Option[R]) *Policy[R] {
+	p, err := Compile(src, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// String returns the source text of this policy.
+func (p *Policy[R]) String() string {
+	return p.src
+}
+
+// Approve evaluates this policy against the resource and token.  If the policy
+// evaluates to true, this method returns nil.  Otherwise, a *DeniedError is returned.
+func (p *Policy[R]) Approve(_ context.Context, resource R, token bascule.Token) error {
+	if token != nil {
+		result, _ := p.eval(&evaluation[R]{
+			resource: resource,
+			token:    token,
+		}).(bool)
+
+		if result {
+			return nil
+		}
+	}
+
+	return &DeniedError{
+		Policy: p.src,
+	}
+}
+
+func (p *Policy[R]) errorAt(e expr, msg string) error {
+	return newParseError(p.src, e.offset(), msg)
+}
+
+func (p *Policy[R]) compile(e expr) (evaluator[R], error) {
+	switch et := e.(type) {
+	case *logicalExpr:
+		return p.compileLogical(et)
+
+	case *notExpr:
+		operand, err := p.compile(et.operand)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ev *evaluation[R]) any {
+			return !truthy(operand(ev))
+		}, nil
+
+	case *compareExpr:
+		return p.compileComparison(et)
+
+	case *literalExpr:
+		value := et.value
+		return func(*evaluation[R]) any { return value }, nil
+
+	case *listExpr:
+		elements := make([]evaluator[R], 0, len(et.elements))
+		for _, element := range et.elements {
+			ev, err := p.compile(element)
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, ev)
+		}
+
+		return func(ev *evaluation[R]) any {
+			values := make([]any, 0, len(elements))
+			for _, element := range elements {
+				values = append(values, element(ev))
+			}
+
+			return values
+		}, nil
+
+	case *referenceExpr:
+		return p.compileReference(et)
+
+	default:
+		return nil, p.errorAt(e, "unsupported expression")
+	}
+}
+
+func (p *Policy[R]) compileLogical(e *logicalExpr) (evaluator[R], error) {
+	left, err := p.compile(e.left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := p.compile(e.right)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.op == opAnd {
+		return func(ev *evaluation[R]) any {
+			return truthy(left(ev)) && truthy(right(ev))
+		}, nil
+	}
+
+	return func(ev *evaluation[R]) any {
+		return truthy(left(ev)) || truthy(right(ev))
+	}, nil
+}
+
+func (p *Policy[R]) compileComparison(e *compareExpr) (evaluator[R], error) {
+	left, err := p.compile(e.left)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.op == opMatches {
+		var pattern string
+		literal, ok := e.right.(*literalExpr)
+		if ok {
+			pattern, ok = literal.value.(string)
+		}
+
+		if !ok {
+			return nil, p.errorAt(e.right, "the right side of matches must be a string literal")
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, p.errorAt(e.right, "invalid regular expression: "+err.Error())
+		}
+
+		return func(ev *evaluation[R]) any {
+			s, ok := left(ev).(string)
+			return ok && re.MatchString(s)
+		}, nil
+	}
+
+	right, err := p.compile(e.right)
+	if err != nil {
+		return nil, err
+	}
+
+	compare := comparisons[e.op]
+	return func(ev *evaluation[R]) any {
+		return compare(left(ev), right(ev))
+	}, nil
+}
+
+func (p *Policy[R]) compileReference(e *referenceExpr) (evaluator[R], error) {
+	switch e.root {
+	case rootPrincipal:
+		if len(e.path) > 0 {
+			return nil, p.errorAt(e, "principal has no fields")
+		}
+
+		return func(ev *evaluation[R]) any {
+			return ev.token.Principal()
+		}, nil
+
+	case rootCapabilities:
+		if len(e.path) > 0 {
+			return nil, p.errorAt(e, "capabilities has no fields")
+		}
+
+		return func(ev *evaluation[R]) any {
+			var ca bascule.CapabilitiesAccessor
+			if bascule.TokenAs(ev.token, &ca) {
+				return normalize(ca.Capabilities())
+			}
+
+			return nil
+		}, nil
+
+	case rootAttributes:
+		if len(e.path) == 0 {
+			return nil, p.errorAt(e, "attributes requires at least one key")
+		}
+
+		keys := e.path
+		return func(ev *evaluation[R]) any {
+			var aa bascule.AttributesAccessor
+			if bascule.TokenAs(ev.token, &aa) {
+				if v, ok := bascule.GetAttribute[any](aa, keys...); ok {
+					return normalize(v)
+				}
+			}
+
+			return nil
+		}, nil
+
+	case rootResource:
+		if p.resolver == nil {
+			return nil, p.errorAt(e, "no resolver is configured for resource references")
+		}
+
+		resolver, path := p.resolver, e.path
+		return func(ev *evaluation[R]) any {
+			if v, ok := resolver.Resolve(ev.resource, path); ok {
+				return normalize(v)
+			}
+
+			return nil
+		}, nil
+
+	default:
+		return nil, p.errorAt(e, "unknown reference '"+e.root+"'")
+	}
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type testToken struct {
+	principal    string
+	capabilities []string
+	attributes   map[string]any
+}
+
+func (tt testToken) Principal() string { return tt.principal }
+
+func (tt testToken) Capabilities() []string { return tt.capabilities }
+
+func (tt testToken) Get(key string) (v any, ok bool) {
+	v, ok = tt.attributes[key]
+	return
+}
+
+type PolicyTestSuite struct {
+	suite.Suite
+
+	token bascule.Token
+}
+
+func (suite *PolicyTestSuite) SetupTest() {
+	suite.token = testToken{
+		principal:    "joe",
+		capabilities: []string{"read", "write"},
+		attributes: map[string]any{
+			"tenant":    "acme",
+			"suspended": false,
+			"level":     3,
+			"score":     json.Number("7.5"),
+			"roles":     []any{"owner", "user"},
+			"realm": map[string]any{
+				"roles": []string{"admin"},
+			},
+			"odd.key": "odd",
+		},
+	}
+}
+
+func (suite *PolicyTestSuite) newRequest() *http.Request {
+	request := httptest.NewRequest("GET", "https://example.com/api/v1/devices?limit=10", nil)
+	request.Header.Set("X-Tenant", "acme")
+	return request
+}
+
+func (suite *PolicyTestSuite) compile(src string) *Policy[*http.Request] {
+	p, err := CompileHTTP(src)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(p)
+	return p
+}
+
+func (suite *PolicyTestSuite) TestApprove() {
+	testCases := []struct {
+		src      string
+		expected bool
+	}{
+		{src: `true`, expected: true},
+		{src: `false`},
+		{src: `principal == "joe"`, expected: true},
+		{src: `principal != "joe"`},
+		{src: `principal == "fred"`},
+		{src: `"joe" == principal`, expected: true},
+		{src: `principal in ["fred", "joe"]`, expected: true},
+		{src: `principal in "joey"`, expected: true},
+		{src: `principal in []`},
+		{src: `capabilities contains "read"`, expected: true},
+		{src: `"write" in capabilities`, expected: true},
+		{src: `capabilities contains "delete"`},
+		{src: `attributes.tenant == resource.headers.X-Tenant`, expected: true},
+		{src: `attributes.tenant == resource.headers["X-Tenant"]`, expected: true},
+		{src: `attributes.suspended`},
+		{src: `!attributes.suspended`, expected: true},
+		{src: `not attributes.suspended and principal == "joe"`, expected: true},
+		{src: `attributes.level == 3`, expected: true},
+		{src: `attributes.level >= 3 && attributes.level <= 3`, expected: true},
+		{src: `attributes.level > 3 || attributes.level < 3`},
+		{src: `attributes.score > 7`, expected: true},
+		{src: `attributes.level < "a"`},
+		{src: `"a" < "b"`, expected: true},
+		{src: `attributes.roles contains "owner"`, expected: true},
+		{src: `attributes.realm.roles contains "admin"`, expected: true},
+		{src: `attributes["odd.key"] == "odd"`, expected: true},
+		{src: `attributes.missing == "x"`},
+		{src: `attributes.missing != "x"`},
+		{src: `!(attributes.missing == "x")`, expected: true},
+		{src: `attributes.tenant != "blocked"`, expected: true},
+		{src: `attributes.level != 4`, expected: true},
+		{src: `attributes.level != 3`},
+		{src: `attributes.level != "3"`},
+		{src: `[1] != [2]`},
+		{src: `attributes.realm == "x"`},
+		{src: `attributes.tenant`},
+		{src: `true == true`, expected: true},
+		{src: `[1] == [1]`},
+		{src: `[1] contains 1`, expected: true},
+		{src: `1 contains 1`},
+		{src: `resource.method in ["GET", "HEAD"]`, expected: true},
+		{src: `resource.path startsWith "/api"`, expected: true},
+		{src: `resource.path endsWith "/devices"`, expected: true},
+		{src: `resource.path endsWith 1`},
+		{src: `resource.path matches "^/api/v[0-9]+/"`, expected: true},
+		{src: `resource.path matches "^/admin"`},
+		{src: `attributes.level matches "3"`},
+		{src: `resource.host == "example.com"`, expected: true},
+		{src: `resource.query.limit == "10"`, expected: true},
+		{src: `resource.query.missing == "10"`},
+		{src: `resource.headers.Missing == "x"`},
+		{src: `resource.nosuch == "x"`},
+		{
+			src:      `principal == "admin" || ("owner" in attributes.roles && !attributes.suspended)`,
+			expected: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.src, func() {
+			p := suite.compile(testCase.src)
+			err := p.Approve(context.Background(), suite.newRequest(), suite.token)
+			if testCase.expected {
+				suite.NoError(err)
+				return
+			}
+
+			suite.ErrorIs(err, bascule.ErrUnauthorized)
+
+			var de *DeniedError
+			suite.Require().ErrorAs(err, &de)
+			suite.Equal(testCase.src, de.Policy)
+			suite.Contains(de.Error(), testCase.src)
+		})
+	}
+}
+
+func (suite *PolicyTestSuite) TestNilToken() {
+	p := suite.compile(`true`)
+	suite.ErrorIs(
+		p.Approve(context.Background(), suite.newRequest(), nil),
+		bascule.ErrUnauthorized,
+	)
+}
+
+func (suite *PolicyTestSuite) TestTokenWithoutAccessors() {
+	p := suite.compile(`capabilities contains "read" || attributes.tenant == "acme"`)
+	suite.ErrorIs(
+		p.Approve(context.Background(), suite.newRequest(), bascule.StubToken("joe")),
+		bascule.ErrUnauthorized,
+	)
+}
+
+func (suite *PolicyTestSuite) TestAuthorizer() {
+	authorizer, err := bascule.NewAuthorizer(
+		bascule.WithApprovers[*http.Request](
+			suite.compile(`resource.method == "GET" && capabilities contains "read"`),
+		),
+	)
+
+	suite.Require().NoError(err)
+	suite.NoError(authorizer.Authorize(context.Background(), suite.newRequest(), suite.token))
+	suite.ErrorIs(
+		authorizer.Authorize(context.Background(), httptest.NewRequest("PUT", "/", nil), suite.token),
+		bascule.ErrUnauthorized,
+	)
+}
+
+func (suite *PolicyTestSuite) TestCustomResolver() {
+	p, err := Compile(
+		`resource.name == principal`,
+		WithResolver[map[string]string](
+			ResolverFunc[map[string]string](func(resource map[string]string, path []string) (any, bool) {
+				v, ok := resource[path[0]]
+				return v, ok
+			}),
+		),
+	)
+
+	suite.Require().NoError(err)
+	suite.NoError(p.Approve(context.Background(), map[string]string{"name": "joe"}, suite.token))
+	suite.Error(p.Approve(context.Background(), map[string]string{"name": "fred"}, suite.token))
+}
+
+func TestPolicy(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
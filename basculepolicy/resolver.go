@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"net/http"
+)
+
+// Resolver is a strategy for obtaining fields from a resource.  Policies use
+// a Resolver to evaluate references that begin with resource.
+type Resolver[R any] interface {
+	// Resolve returns the value of the field identified by path, e.g. ["headers", "X-Tenant"]
+	// for the reference resource.headers["X-Tenant"].  If the field does not exist,
+	// this method returns false.
+	Resolve(resource R, path []string) (any, bool)
+}
+
+// ResolverFunc is a closure type that implements Resolver.
+type ResolverFunc[R any] func(R, []string) (any, bool)
+
+func (rf ResolverFunc[R]) Resolve(resource R, path []string) (any, bool) {
+	return rf(resource, path)
+}
+
+// HTTPResolver is a Resolver for HTTP requests.  The following fields are supported:
+//
+//	resource.method         the request method
+//	resource.path           the request's URL path
+//	resource.host           the request's host
+//	resource.headers.Name   the first value of the given header
+//	resource.query.name     the first value of the given URL query parameter
+type HTTPResolver struct{}
+
+// Resolve implements Resolver for HTTP requests.
+func (HTTPResolver) Resolve(request *http.Request, path []string) (any, bool) {
+	switch {
+	case request == nil:
+		return nil, false
+
+	case len(path) == 1 && path[0] == "method":
+		return request.Method, true
+
+	case len(path) == 1 && path[0] == "path":
+		return request.URL.Path, true
+
+	case len(path) == 1 && path[0] == "host":
+		return request.Host, true
+
+	case len(path) == 2 && path[0] == "headers":
+		values := request.Header.Values(path[1])
+		if len(values) > 0 {
+			return values[0], true
+		}
+
+	case len(path) == 2 && path[0] == "query":
+		values, ok := request.URL.Query()[path[1]]
+		if ok && len(values) > 0 {
+			return values[0], true
+		}
+	}
+
+	return nil, false
+}
+
+// CompileHTTP compiles a policy for HTTP requests, using HTTPResolver for resource
+// references.  The returned Policy can be passed to basculehttp.NewAuthorizer via
+// bascule.WithApprovers.
+func CompileHTTP(src string) (*Policy[*http.Request], error) {
+	return Compile(src, WithResolver[*http.Request](HTTPResolver{}))
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ResolverTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ResolverTestSuite) TestHTTPResolver() {
+	request := httptest.NewRequest("POST", "http://example.com/path?q=1&q=2", nil)
+	request.Header.Add("X-Multi", "first")
+	request.Header.Add("X-Multi", "second")
+
+	testCases := []struct {
+		path          []string
+		expectedValue any
+		expectedOK    bool
+	}{
+		{path: nil},
+		{path: []string{"method"}, expectedValue: "POST", expectedOK: true},
+		{path: []string{"path"}, expectedValue: "/path", expectedOK: true},
+		{path: []string{"host"}, expectedValue: "example.com", expectedOK: true},
+		{path: []string{"headers", "x-multi"}, expectedValue: "first", expectedOK: true},
+		{path: []string{"headers", "X-Missing"}},
+		{path: []string{"headers"}},
+		{path: []string{"query", "q"}, expectedValue: "1", expectedOK: true},
+		{path: []string{"query", "missing"}},
+		{path: []string{"method", "extra"}},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run("", func() {
+			v, ok := HTTPResolver{}.Resolve(request, testCase.path)
+			suite.Equal(testCase.expectedValue, v)
+			suite.Equal(testCase.expectedOK, ok)
+		})
+	}
+
+	suite.Run("NilRequest", func() {
+		v, ok := HTTPResolver{}.Resolve((*http.Request)(nil), []string{"method"})
+		suite.Nil(v)
+		suite.False(ok)
+	})
+}
+
+func TestResolver(t *testing.T) {
+	suite.Run(t, new(ResolverTestSuite))
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculepolicy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// truthy tests if a value is the boolean true.
+func truthy(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// normalize converts an arbitrary value into one of the types that policies
+// operate on:  string, float64, bool, or []any.  Any other types are returned as is,
+// and will not compare equal to anything.
+func normalize(v any) any {
+	switch vt := v.(type) {
+	case nil, string, float64, bool:
+		return v
+
+	case json.Number:
+		if f, err := vt.Float64(); err == nil {
+			return f
+		}
+
+		return vt.String()
+
+	case []string:
+		values := make([]any, 0, len(vt))
+		for _, s := range vt {
+			values = append(values, s)
+		}
+
+		return values
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+
+	case reflect.Float32:
+		return rv.Float()
+
+	case reflect.String:
+		return rv.String()
+
+	case reflect.Slice, reflect.Array:
+		values := make([]any, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, normalize(rv.Index(i).Interface()))
+		}
+
+		return values
+
+	default:
+		return v
+	}
+}
+
+// equal tests two normalized values for equality.  Only scalars can be equal.
+func equal(left, right any) bool {
+	switch lt := left.(type) {
+	case string:
+		rt, ok := right.(string)
+		return ok && lt == rt
+
+	case float64:
+		rt, ok := right.(float64)
+		return ok && lt == rt
+
+	case bool:
+		rt, ok := right.(bool)
+		return ok && lt == rt
+
+	default:
+		return false
+	}
+}
+
+// notEqual tests two normalized values for inequality.  As with equal, only scalars
+// of the same type can be compared.  Values that cannot be compared, such as the nil
+// from an unresolved reference, are neither equal nor unequal.
+func notEqual(left, right any) bool {
+	switch left.(type) {
+	case string, float64, bool:
+		return reflect.TypeOf(left) == reflect.TypeOf(right) && left != right
+
+	default:
+		return false
+	}
+}
+
+// order compares two normalized values, which must be either both strings or both numbers.
+// The returned int has the same semantics as strings.Compare.  If the values cannot be
+// ordered, this function returns false.
+func order(left, right any) (int, bool) {
+	switch lt := left.(type) {
+	case string:
+		if rt, ok := right.(string); ok {
+			return strings.Compare(lt, rt), true
+		}
+
+	case float64:
+		if rt, ok := right.(float64); ok {
+			switch {
+			case lt < rt:
+				return -1, true
+
+			case lt > rt:
+				return 1, true
+
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// listContains tests if a normalized list contains the given normalized value.
+func listContains(list []any, v any) bool {
+	for _, element := range list {
+		if equal(element, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains tests if container, which is either a list or a string, contains v.
+func contains(container, v any) bool {
+	switch ct := container.(type) {
+	case []any:
+		return listContains(ct, v)
+
+	case string:
+		s, ok := v.(string)
+		return ok && strings.Contains(ct, s)
+
+	default:
+		return false
+	}
+}
+
+// stringOp adapts a string predicate into a comparison.
+func stringOp(f func(string, string) bool) func(any, any) bool {
+	return func(left, right any) bool {
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		return lok && rok && f(ls, rs)
+	}
+}
+
+// orderOp adapts a test of the result of order into a comparison.
+func orderOp(f func(int) bool) func(any, any) bool {
+	return func(left, right any) bool {
+		c, ok := order(left, right)
+		return ok && f(c)
+	}
+}
+
+// comparisons holds the implementations of each comparison operator, excluding
+// matches, which requires compilation.
+var comparisons = map[string]func(any, any) bool{
+	opEqual:    equal,
+	opNotEqual: notEqual,
+
+	opLess:      orderOp(func(c int) bool { return c < 0 }),
+	opLessEq:    orderOp(func(c int) bool { return c <= 0 }),
+	opGreater:   orderOp(func(c int) bool { return c > 0 }),
+	opGreaterEq: orderOp(func(c int) bool { return c >= 0 }),
+
+	opIn:       func(left, right any) bool { return contains(right, left) },
+	opContains: contains,
+
+	opStartsWith: stringOp(strings.HasPrefix),
+	opEndsWith:   stringOp(strings.HasSuffix),
+}
@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculeslog provides bascule listeners that write audit records
+using log/slog.
+
+Records never contain raw credentials.  Any password carried by a token, along
+with any credentials in an *http.Request source's Authorization header or in a
+header given to WithCredentialHeaders, are secrets.  Any logged value that contains
+a secret is replaced as a whole with Redacted.
+*/
+package basculeslog
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeslog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/xmidt-org/bascule"
+)
+
+const (
+	// AuthenticateMessage is the message of records written for authentication events.
+	AuthenticateMessage = "authenticate"
+
+	// AuthorizeMessage is the message of records written for authorization events.
+	AuthorizeMessage = "authorize"
+)
+
+// AuthenticateListener writes an audit record for each bascule.AuthenticateEvent.
+type AuthenticateListener[S any] struct {
+	cfg *config
+}
+
+// NewAuthenticateListener creates a listener for authentication events.
+func NewAuthenticateListener[S any](opts ...Option) (*AuthenticateListener[S], error) {
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthenticateListener[S]{
+		cfg: cfg,
+	}, nil
+}
+
+// OnEvent writes a record describing the given event.
+func (al *AuthenticateListener[S]) OnEvent(e bascule.AuthenticateEvent[S]) {
+	al.cfg.log(AuthenticateMessage, e.Source, e.Token, e.Err)
+}
+
+// AuthorizeListener writes an audit record for each bascule.AuthorizeEvent.
+type AuthorizeListener[R any] struct {
+	cfg *config
+}
+
+// NewAuthorizeListener creates a listener for authorization events.
+func NewAuthorizeListener[R any](opts ...Option) (*AuthorizeListener[R], error) {
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthorizeListener[R]{
+		cfg: cfg,
+	}, nil
+}
+
+// OnEvent writes a record describing the given event.
+func (al *AuthorizeListener[R]) OnEvent(e bascule.AuthorizeEvent[R]) {
+	al.cfg.log(AuthorizeMessage, e.Resource, e.Token, e.Err)
+}
+
+// secrets returns the credentials that must never appear in a record.
+func (c *config) secrets(subject any, token bascule.Token) (s []string) {
+	if token != nil {
+		if password, ok := bascule.GetPassword(token); ok && len(password) > 0 {
+			s = append(s, password)
+		}
+	}
+
+	if request, ok := subject.(*http.Request); ok && request != nil {
+		if _, password, ok := request.BasicAuth(); ok && len(password) > 0 {
+			s = append(s, password)
+		}
+
+		for _, h := range c.headers {
+			for _, value := range request.Header.Values(h) {
+				if value = strings.TrimSpace(value); len(value) > 0 {
+					s = append(s, value)
+				}
+
+				// the credentials alone, without any scheme
+				if _, credentials, ok := strings.Cut(value, " "); ok {
+					if credentials = strings.TrimSpace(credentials); len(credentials) > 0 {
+						s = append(s, credentials)
+					}
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// redactor creates the closure that scrubs secrets from logged string values.  Redaction
+// applies to whole values:  a value that contains any secret is replaced entirely with
+// Redacted, rather than having the secret cut out of it.  This never leaves a partial
+// secret behind and never alters the parts of a value that are unrelated to a secret.
+func (c *config) redactor(subject any, token bascule.Token) func(string) string {
+	s := c.secrets(subject, token)
+	return func(v string) string {
+		for _, secret := range s {
+			if strings.Contains(v, secret) {
+				return Redacted
+			}
+		}
+
+		for _, r := range c.redactors {
+			v = r(v)
+		}
+
+		return v
+	}
+}
+
+// log writes a single record for an event.  The subject is either the source or
+// the resource of the event.
+func (c *config) log(msg string, subject any, token bascule.Token, err error) {
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	ctx := context.Background()
+	request, _ := subject.(*http.Request)
+	if request != nil {
+		ctx = request.Context()
+	}
+
+	level := c.successLevel
+	if err != nil {
+		level = c.failureLevel
+	}
+
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var (
+		redact = c.redactor(subject, token)
+		attrs  = make([]slog.Attr, 0, len(allFields))
+	)
+
+	for _, f := range allFields {
+		if !c.fields[f] {
+			continue
+		}
+
+		switch f {
+		case FieldPrincipal:
+			if token != nil {
+				attrs = append(attrs, slog.String(string(f), redact(token.Principal())))
+			}
+
+		case FieldOutcome:
+			outcome := OutcomeSuccess
+			if err != nil {
+				outcome = OutcomeFailure
+			}
+
+			attrs = append(attrs, slog.String(string(f), outcome))
+
+		case FieldCategory:
+			if err != nil {
+				attrs = append(attrs, slog.String(string(f), bascule.CategorizeError(err).String()))
+			}
+
+		case FieldError:
+			if err != nil {
+				attrs = append(attrs, slog.String(string(f), redact(err.Error())))
+			}
+
+		case FieldMethod:
+			if request != nil {
+				attrs = append(attrs, slog.String(string(f), request.Method))
+			}
+
+		case FieldPath:
+			if request != nil && request.URL != nil {
+				attrs = append(attrs, slog.String(string(f), redact(request.URL.Path)))
+			}
+		}
+	}
+
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
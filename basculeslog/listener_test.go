@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeslog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type passwordToken struct {
+	principal, password string
+}
+
+func (pt passwordToken) Principal() string { return pt.principal }
+
+func (pt passwordToken) Password() string { return pt.password }
+
+type ListenerTestSuite struct {
+	TestSuite
+}
+
+func (suite *ListenerTestSuite) newAuthenticateListener(opts ...Option) *AuthenticateListener[string] {
+	l, err := NewAuthenticateListener[string](append([]Option{WithLogger(suite.logger)}, opts...)...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(l)
+	return l
+}
+
+func (suite *ListenerTestSuite) TestNewAuthenticateListenerError() {
+	l, err := NewAuthenticateListener[string](WithFields(Field("nosuch")))
+	suite.Nil(l)
+	suite.ErrorIs(err, ErrUnknownField)
+}
+
+func (suite *ListenerTestSuite) TestNewAuthorizeListenerError() {
+	l, err := NewAuthorizeListener[string](WithFields(Field("nosuch")))
+	suite.Nil(l)
+	suite.ErrorIs(err, ErrUnknownField)
+}
+
+func (suite *ListenerTestSuite) TestAuthenticateSuccess() {
+	suite.newAuthenticateListener().OnEvent(bascule.AuthenticateEvent[string]{
+		Source: "source",
+		Token:  bascule.StubToken("joe"),
+	})
+
+	suite.Equal(
+		[]map[string]any{
+			{
+				slog.LevelKey:   "INFO",
+				slog.MessageKey: AuthenticateMessage,
+				"principal":     "joe",
+				"outcome":       OutcomeSuccess,
+			},
+		},
+		suite.records(),
+	)
+}
+
+func (suite *ListenerTestSuite) TestAuthenticateFailure() {
+	testCases := []struct {
+		err      error
+		expected bascule.ErrorCategory
+	}{
+		{err: bascule.ErrMissingCredentials, expected: bascule.CategoryMissingCredentials},
+		{err: bascule.ErrInvalidCredentials, expected: bascule.CategoryInvalidCredentials},
+		{err: bascule.ErrBadCredentials, expected: bascule.CategoryBadCredentials},
+		{err: errors.New("expected"), expected: bascule.CategoryOther},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(string(testCase.expected), func() {
+			suite.SetupTest()
+			suite.newAuthenticateListener().OnEvent(bascule.AuthenticateEvent[string]{
+				Source: "source",
+				Err:    testCase.err,
+			})
+
+			suite.Equal(
+				[]map[string]any{
+					{
+						slog.LevelKey:   "WARN",
+						slog.MessageKey: AuthenticateMessage,
+						"outcome":       OutcomeFailure,
+						"category":      string(testCase.expected),
+						"error":         testCase.err.Error(),
+					},
+				},
+				suite.records(),
+			)
+		})
+	}
+}
+
+func (suite *ListenerTestSuite) TestAuthorize() {
+	l, err := NewAuthorizeListener[string](WithLogger(suite.logger))
+	suite.Require().NoError(err)
+
+	l.OnEvent(bascule.AuthorizeEvent[string]{
+		Resource: "resource",
+		Token:    bascule.StubToken("joe"),
+		Err:      bascule.ErrUnauthorized,
+	})
+
+	suite.Equal(
+		[]map[string]any{
+			{
+				slog.LevelKey:   "WARN",
+				slog.MessageKey: AuthorizeMessage,
+				"principal":     "joe",
+				"outcome":       OutcomeFailure,
+				"category":      string(bascule.CategoryUnauthorized),
+				"error":         bascule.ErrUnauthorized.Error(),
+			},
+		},
+		suite.records(),
+	)
+}
+
+func (suite *ListenerTestSuite) TestHTTPRequest() {
+	request := httptest.NewRequest("PUT", "/api/devices?secret=query", nil)
+	request.SetBasicAuth("joe", "s3cr3t")
+
+	l, err := NewAuthenticateListener[*httptest.ResponseRecorder](WithLogger(suite.logger))
+	suite.Require().NoError(err)
+	l.OnEvent(bascule.AuthenticateEvent[*httptest.ResponseRecorder]{}) // not a request: no method or path
+
+	rl, err := NewAuthorizeListener[any](WithLogger(suite.logger))
+	suite.Require().NoError(err)
+	rl.OnEvent(bascule.AuthorizeEvent[any]{
+		Resource: request,
+		Token:    passwordToken{principal: "joe", password: "s3cr3t"},
+		Err:      fmt.Errorf("password s3cr3t does not match: %w", bascule.ErrBadCredentials),
+	})
+
+	records := suite.records()
+	suite.Require().Len(records, 2)
+	suite.NotContains(records[0], "method")
+	suite.NotContains(records[0], "path")
+
+	suite.Equal("PUT", records[1]["method"])
+	suite.Equal("/api/devices", records[1]["path"])
+	suite.Equal(Redacted, records[1]["error"])
+	suite.Equal("joe", records[1]["principal"])
+}
+
+func (suite *ListenerTestSuite) TestRedaction() {
+	suite.Run("Token", func() {
+		suite.SetupTest()
+		suite.newAuthenticateListener().OnEvent(bascule.AuthenticateEvent[string]{
+			Token: passwordToken{principal: "joe", password: "s3cr3t"},
+			Err:   errors.New("s3cr3t is wrong"),
+		})
+
+		suite.NotContains(suite.output.String(), "s3cr3t")
+		suite.Contains(suite.output.String(), Redacted)
+	})
+
+	suite.Run("AuthorizationHeader", func() {
+		suite.SetupTest()
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Authorization", "Bearer abc.def.ghi")
+
+		l, err := NewAuthenticateListener[any](WithLogger(suite.logger))
+		suite.Require().NoError(err)
+		l.OnEvent(bascule.AuthenticateEvent[any]{
+			Source: request,
+			Err:    errors.New("cannot parse abc.def.ghi"),
+		})
+
+		suite.NotContains(suite.output.String(), "abc.def.ghi")
+	})
+
+	suite.Run("CredentialHeader", func() {
+		suite.SetupTest()
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("X-Api-Key", "k3y")
+
+		l, err := NewAuthenticateListener[any](WithLogger(suite.logger), WithCredentialHeaders("", "x-api-key"))
+		suite.Require().NoError(err)
+		l.OnEvent(bascule.AuthenticateEvent[any]{
+			Source: request,
+			Err:    errors.New("cannot parse k3y"),
+		})
+
+		suite.NotContains(suite.output.String(), "k3y")
+		suite.Equal(Redacted, suite.records()[0]["error"])
+	})
+
+	suite.Run("WholeValue", func() {
+		suite.SetupTest()
+		suite.newAuthenticateListener().OnEvent(bascule.AuthenticateEvent[string]{
+			Token: passwordToken{principal: "joe", password: "e"},
+			Err:   errors.New("the password is wrong"),
+		})
+
+		records := suite.records()
+		suite.Require().Len(records, 1)
+		suite.Equal(Redacted, records[0]["principal"])
+		suite.Equal(Redacted, records[0]["error"])
+		suite.Equal(OutcomeFailure, records[0]["outcome"])
+	})
+
+	suite.Run("Unrelated", func() {
+		suite.SetupTest()
+		suite.newAuthenticateListener().OnEvent(bascule.AuthenticateEvent[string]{
+			Token: passwordToken{principal: "joe", password: "s3cr3t"},
+			Err:   errors.New("the password is wrong"),
+		})
+
+		records := suite.records()
+		suite.Require().Len(records, 1)
+		suite.Equal("joe", records[0]["principal"])
+		suite.Equal("the password is wrong", records[0]["error"])
+	})
+
+	suite.Run("Custom", func() {
+		suite.SetupTest()
+		suite.newAuthenticateListener(
+			WithRedactor(func(v string) string {
+				return strings.ReplaceAll(v, "internal", "***")
+			}),
+		).OnEvent(bascule.AuthenticateEvent[string]{
+			Err: errors.New("internal failure"),
+		})
+
+		suite.Equal("*** failure", suite.records()[0]["error"])
+	})
+}
+
+func (suite *ListenerTestSuite) TestFieldSelection() {
+	suite.newAuthenticateListener(WithFields(FieldOutcome)).OnEvent(bascule.AuthenticateEvent[string]{
+		Token: bascule.StubToken("joe"),
+		Err:   bascule.ErrBadCredentials,
+	})
+
+	suite.Equal(
+		[]map[string]any{
+			{
+				slog.LevelKey:   "WARN",
+				slog.MessageKey: AuthenticateMessage,
+				"outcome":       OutcomeFailure,
+			},
+		},
+		suite.records(),
+	)
+}
+
+func (suite *ListenerTestSuite) TestLevels() {
+	l := suite.newAuthenticateListener(
+		WithSuccessLevel(slog.LevelDebug),
+		WithFailureLevel(slog.LevelError),
+	)
+
+	l.OnEvent(bascule.AuthenticateEvent[string]{Token: bascule.StubToken("joe")})
+	l.OnEvent(bascule.AuthenticateEvent[string]{Err: bascule.ErrBadCredentials})
+
+	records := suite.records()
+	suite.Require().Len(records, 2)
+	suite.Equal("DEBUG", records[0][slog.LevelKey])
+	suite.Equal("ERROR", records[1][slog.LevelKey])
+}
+
+func (suite *ListenerTestSuite) TestDisabledLevel() {
+	suite.newAuthenticateListener(WithSuccessLevel(slog.LevelDebug - 1)).OnEvent(
+		bascule.AuthenticateEvent[string]{Token: bascule.StubToken("joe")},
+	)
+
+	suite.Empty(suite.records())
+}
+
+func (suite *ListenerTestSuite) TestDefaultLogger() {
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(suite.logger)
+
+	l, err := NewAuthenticateListener[string]()
+	suite.Require().NoError(err)
+	l.OnEvent(bascule.AuthenticateEvent[string]{Token: bascule.StubToken("joe")})
+	suite.Len(suite.records(), 1)
+}
+
+func TestListener(t *testing.T) {
+	suite.Run(t, new(ListenerTestSuite))
+}
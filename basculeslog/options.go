@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeslog
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// Field identifies an attribute that may appear in a logged record.
+type Field string
+
+const (
+	// FieldPrincipal is the principal of the event's token, if any.
+	FieldPrincipal Field = "principal"
+
+	// FieldOutcome is either OutcomeSuccess or OutcomeFailure.
+	FieldOutcome Field = "outcome"
+
+	// FieldCategory is the bascule.ErrorCategory of the event's error.  This
+	// field is omitted on success.
+	FieldCategory Field = "category"
+
+	// FieldError is the text of the event's error.  This field is omitted
+	// on success.
+	FieldError Field = "error"
+
+	// FieldMethod is the HTTP method of an *http.Request source or resource.
+	FieldMethod Field = "method"
+
+	// FieldPath is the URL path of an *http.Request source or resource.
+	FieldPath Field = "path"
+)
+
+const (
+	// OutcomeSuccess is the value of FieldOutcome for events without an error.
+	OutcomeSuccess = "success"
+
+	// OutcomeFailure is the value of FieldOutcome for events with an error.
+	OutcomeFailure = "failure"
+
+	// Redacted replaces any logged value that contains a secret.
+	Redacted = "[REDACTED]"
+)
+
+var (
+	// ErrUnknownField indicates that WithFields was passed a Field not
+	// defined by this package.
+	ErrUnknownField = errors.New("unknown log field")
+
+	// allFields is the default field selection, in output order.
+	allFields = []Field{
+		FieldPrincipal,
+		FieldOutcome,
+		FieldCategory,
+		FieldError,
+		FieldMethod,
+		FieldPath,
+	}
+)
+
+// Option is a configurable option for the listeners in this package.
+type Option interface {
+	apply(*config) error
+}
+
+type optionFunc func(*config) error
+
+func (of optionFunc) apply(c *config) error { return of(c) }
+
+// WithLogger sets the logger that receives records.  A nil logger, which is
+// the default, means slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return optionFunc(func(c *config) error {
+		c.logger = l
+		return nil
+	})
+}
+
+// WithFields selects the attributes written to each record.  By default, all
+// fields are written.  Calling this with no fields writes records that contain
+// only the message.
+func WithFields(fields ...Field) Option {
+	return optionFunc(func(c *config) error {
+		selected := make(map[Field]bool, len(fields))
+		for _, f := range fields {
+			if !isKnownField(f) {
+				return ErrUnknownField
+			}
+
+			selected[f] = true
+		}
+
+		c.fields = selected
+		return nil
+	})
+}
+
+// WithSuccessLevel sets the level of records for events without an error.
+// The default is slog.LevelInfo.
+func WithSuccessLevel(level slog.Level) Option {
+	return optionFunc(func(c *config) error {
+		c.successLevel = level
+		return nil
+	})
+}
+
+// WithFailureLevel sets the level of records for events with an error.
+// The default is slog.LevelWarn.
+func WithFailureLevel(level slog.Level) Option {
+	return optionFunc(func(c *config) error {
+		c.failureLevel = level
+		return nil
+	})
+}
+
+// WithRedactor adds a closure that is applied to every string value that was not
+// replaced by the built-in redaction of credentials.  This can be used to scrub other
+// application-specific secrets.  Multiple redactors are applied in order.
+func WithRedactor(r func(string) string) Option {
+	return optionFunc(func(c *config) error {
+		if r != nil {
+			c.redactors = append(c.redactors, r)
+		}
+
+		return nil
+	})
+}
+
+// WithCredentialHeaders adds HTTP headers whose values are credentials that must be
+// redacted, in addition to the Authorization header.  This option should be used with
+// the same header given to basculehttp.WithAuthorizationHeader, if any.  Multiple
+// invocations of this option are cumulative.  Empty header names are ignored.
+func WithCredentialHeaders(headers ...string) Option {
+	return optionFunc(func(c *config) error {
+		for _, h := range headers {
+			if len(h) > 0 {
+				c.headers = append(c.headers, h)
+			}
+		}
+
+		return nil
+	})
+}
+
+type config struct {
+	logger       *slog.Logger
+	fields       map[Field]bool
+	successLevel slog.Level
+	failureLevel slog.Level
+	redactors    []func(string) string
+	headers      []string
+}
+
+func isKnownField(f Field) bool {
+	for _, known := range allFields {
+		if f == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newConfig(opts ...Option) (*config, error) {
+	c := &config{
+		successLevel: slog.LevelInfo,
+		failureLevel: slog.LevelWarn,
+		headers:      []string{"Authorization"},
+	}
+
+	for _, o := range opts {
+		if err := o.apply(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.fields == nil {
+		c.fields = make(map[Field]bool, len(allFields))
+		for _, f := range allFields {
+			c.fields[f] = true
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeslog
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OptionsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *OptionsTestSuite) TestDefaults() {
+	c, err := newConfig()
+	suite.Require().NoError(err)
+	suite.Nil(c.logger)
+	suite.Equal(slog.LevelInfo, c.successLevel)
+	suite.Equal(slog.LevelWarn, c.failureLevel)
+	suite.Len(c.fields, len(allFields))
+	suite.Empty(c.redactors)
+}
+
+func (suite *OptionsTestSuite) TestWithFields() {
+	suite.Run("Known", func() {
+		c, err := newConfig(WithFields(FieldPrincipal, FieldOutcome))
+		suite.Require().NoError(err)
+		suite.Equal(map[Field]bool{FieldPrincipal: true, FieldOutcome: true}, c.fields)
+	})
+
+	suite.Run("None", func() {
+		c, err := newConfig(WithFields())
+		suite.Require().NoError(err)
+		suite.Empty(c.fields)
+	})
+
+	suite.Run("Unknown", func() {
+		c, err := newConfig(WithFields(FieldPrincipal, Field("nosuch")))
+		suite.Nil(c)
+		suite.ErrorIs(err, ErrUnknownField)
+	})
+}
+
+func (suite *OptionsTestSuite) TestWithRedactor() {
+	c, err := newConfig(WithRedactor(nil), WithRedactor(func(v string) string { return v }))
+	suite.Require().NoError(err)
+	suite.Len(c.redactors, 1)
+}
+
+func TestOptions(t *testing.T) {
+	suite.Run(t, new(OptionsTestSuite))
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculeslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// TestSuite captures records written to a JSON slog handler.
+type TestSuite struct {
+	suite.Suite
+
+	output *bytes.Buffer
+	logger *slog.Logger
+}
+
+func (suite *TestSuite) SetupTest() {
+	suite.output = new(bytes.Buffer)
+	suite.logger = slog.New(
+		slog.NewJSONHandler(suite.output, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+}
+
+// records decodes and returns all records written so far.  The time
+// attribute is removed from each record.
+func (suite *TestSuite) records() (r []map[string]any) {
+	decoder := json.NewDecoder(bytes.NewReader(suite.output.Bytes()))
+	for decoder.More() {
+		var record map[string]any
+		suite.Require().NoError(decoder.Decode(&record))
+		delete(record, slog.TimeKey)
+		r = append(r, record)
+	}
+
+	return
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+// ApproverSuite is a conformance suite that verifies a bascule.Approver obeys
+// the documented contract:
+//
+//   - an approved token yields a nil error
+//   - a denied token yields an error with bascule.ErrUnauthorized in its chain
+//   - a token the approver doesn't support yields a nil error
+type ApproverSuite[R any] struct {
+	suite.Suite
+
+	// NewApprover creates the Approver under test.  This field is required.
+	NewApprover func() bascule.Approver[R]
+
+	// Resource creates the resource passed to the approver.  If unset, the zero
+	// value of R is used.
+	Resource func() R
+
+	// Approved creates a token that the approver grants access to Resource.
+	// This field is required.
+	Approved func() bascule.Token
+
+	// Denied creates a token that the approver denies access to Resource.  If
+	// unset, the corresponding test is skipped.
+	Denied func() bascule.Token
+
+	// Unsupported creates a token that the approver does not support.  If unset,
+	// the corresponding test is skipped.
+	Unsupported func() bascule.Token
+}
+
+// SetupSuite verifies that the required fields are set.
+func (suite *ApproverSuite[R]) SetupSuite() {
+	suite.Require().NotNil(suite.NewApprover, "NewApprover is required")
+	suite.Require().NotNil(suite.Approved, "Approved is required")
+}
+
+func (suite *ApproverSuite[R]) approve(t bascule.Token) error {
+	a := suite.NewApprover()
+	suite.Require().NotNil(a)
+
+	var resource R
+	if suite.Resource != nil {
+		resource = suite.Resource()
+	}
+
+	return a.Approve(context.Background(), resource, t)
+}
+
+func (suite *ApproverSuite[R]) TestApproved() {
+	suite.NoError(suite.approve(suite.Approved()))
+}
+
+func (suite *ApproverSuite[R]) TestDenied() {
+	if suite.Denied == nil {
+		suite.T().Skip("Denied is not set")
+	}
+
+	suite.ErrorIs(suite.approve(suite.Denied()), bascule.ErrUnauthorized)
+}
+
+func (suite *ApproverSuite[R]) TestUnsupported() {
+	if suite.Unsupported == nil {
+		suite.T().Skip("Unsupported is not set")
+	}
+
+	suite.NoError(suite.approve(suite.Unsupported()), "unsupported tokens must be ignored")
+}
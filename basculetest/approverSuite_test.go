@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+func TestApproverSuite(t *testing.T) {
+	suite.Run(t, &ApproverSuite[string]{
+		NewApprover: func() bascule.Approver[string] {
+			return bascule.NewPermissionApprover(
+				func(_ context.Context, resource string) (bascule.Permission, error) {
+					return bascule.ParsePermission(resource + ":read")
+				},
+			)
+		},
+		Resource: func() string {
+			return "doc"
+		},
+		Approved: func() bascule.Token {
+			return NewToken("joe").Capabilities("doc:*").Build()
+		},
+		Denied: func() bascule.Token {
+			return NewToken("joe").Capabilities("doc:write", "mail:read").Build()
+		},
+	})
+}
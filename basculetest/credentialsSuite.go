@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule/basculehash"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CredentialsSuite is a conformance suite that verifies a basculehash.Credentials
+// obeys the documented contract for Get, Set, Delete, and Update.
+type CredentialsSuite[C basculehash.Credentials] struct {
+	suite.Suite
+
+	// NewCredentials creates an empty Credentials under test.  This is invoked
+	// before each test.  This field is required.
+	NewCredentials func() C
+
+	// Hasher is used to create digests.  If unset, bcrypt with its minimum cost
+	// is used.
+	Hasher basculehash.Hasher
+
+	credentials C
+}
+
+// SetupSuite verifies that the required fields are set.
+func (suite *CredentialsSuite[C]) SetupSuite() {
+	suite.Require().NotNil(suite.NewCredentials, "NewCredentials is required")
+	if suite.Hasher == nil {
+		suite.Hasher = basculehash.Bcrypt{Cost: bcrypt.MinCost}
+	}
+}
+
+// SetupTest creates the Credentials for each test.
+func (suite *CredentialsSuite[C]) SetupTest() {
+	suite.credentials = suite.NewCredentials()
+}
+
+// newDigest creates a distinct digest.  Hashers must salt, so each digest differs.
+func (suite *CredentialsSuite[C]) newDigest() basculehash.Digest {
+	d, err := suite.Hasher.Hash([]byte("conformance plaintext"))
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(d)
+	return d
+}
+
+func (suite *CredentialsSuite[C]) exists(principal string, expected basculehash.Digest) {
+	d, ok := suite.credentials.Get(context.Background(), principal)
+	suite.Require().True(ok, "principal %s should exist", principal)
+	suite.Require().Equal(expected, d)
+}
+
+func (suite *CredentialsSuite[C]) notExists(principal string) {
+	d, ok := suite.credentials.Get(context.Background(), principal)
+	suite.Require().False(ok, "principal %s should not exist", principal)
+	suite.Require().Empty(d)
+}
+
+func (suite *CredentialsSuite[C]) TestEmpty() {
+	suite.notExists("joe")
+	suite.credentials.Delete(context.Background(), "joe")
+	suite.credentials.Delete(context.Background())
+	suite.notExists("joe")
+}
+
+func (suite *CredentialsSuite[C]) TestGetSetDelete() {
+	ctx := context.Background()
+
+	joeDigest := suite.newDigest()
+	suite.credentials.Set(ctx, "joe", joeDigest)
+	suite.exists("joe", joeDigest)
+
+	fredDigest := suite.newDigest()
+	suite.credentials.Set(ctx, "fred", fredDigest)
+	suite.exists("joe", joeDigest)
+	suite.exists("fred", fredDigest)
+
+	newJoeDigest := suite.newDigest()
+	suite.Require().NotEqual(joeDigest, newJoeDigest)
+	suite.credentials.Set(ctx, "joe", newJoeDigest)
+	suite.exists("joe", newJoeDigest)
+	suite.exists("fred", fredDigest)
+
+	suite.credentials.Delete(ctx, "fred")
+	suite.notExists("fred")
+	suite.exists("joe", newJoeDigest)
+}
+
+func (suite *CredentialsSuite[C]) TestDeleteMany() {
+	ctx := context.Background()
+	moeDigest := suite.newDigest()
+	suite.credentials.Update(ctx, basculehash.Principals{
+		"joe":  suite.newDigest(),
+		"fred": suite.newDigest(),
+		"moe":  moeDigest,
+	})
+
+	suite.credentials.Delete(ctx, "joe", "fred", "nosuch")
+	suite.notExists("joe")
+	suite.notExists("fred")
+	suite.exists("moe", moeDigest)
+}
+
+func (suite *CredentialsSuite[C]) TestUpdate() {
+	ctx := context.Background()
+	suite.credentials.Update(ctx, nil)
+
+	joeDigest := suite.newDigest()
+	fredDigest := suite.newDigest()
+	suite.credentials.Update(ctx, basculehash.Principals{
+		"joe":  joeDigest,
+		"fred": fredDigest,
+	})
+
+	suite.exists("joe", joeDigest)
+	suite.exists("fred", fredDigest)
+
+	// existing principals are replaced, but others are retained
+	joeDigest = suite.newDigest()
+	moeDigest := suite.newDigest()
+	suite.credentials.Update(ctx, basculehash.Principals{
+		"joe": joeDigest,
+		"moe": moeDigest,
+	})
+
+	suite.exists("joe", joeDigest)
+	suite.exists("fred", fredDigest)
+	suite.exists("moe", moeDigest)
+}
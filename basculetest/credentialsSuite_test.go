@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule/basculehash"
+)
+
+func TestCredentialsSuite(t *testing.T) {
+	t.Run("Principals", func(t *testing.T) {
+		suite.Run(t, &CredentialsSuite[basculehash.Principals]{
+			NewCredentials: func() basculehash.Principals {
+				return basculehash.Principals{}
+			},
+		})
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		suite.Run(t, &CredentialsSuite[*basculehash.Store]{
+			NewCredentials: func() *basculehash.Store {
+				return new(basculehash.Store)
+			},
+		})
+	})
+}
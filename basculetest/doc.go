@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package basculetest provides test infrastructure for code that uses bascule.
+
+This package includes recording listeners, mocks of the workflow interfaces,
+a token builder, helpers for creating HTTP requests, and conformance suites
+that verify that custom implementations of bascule's interfaces obey the
+documented contracts.  A conformance suite is run like any other testify suite:
+
+	func TestMyParser(t *testing.T) {
+		suite.Run(t, &basculetest.TokenParserSuite[*http.Request]{
+			NewParser: newMyParser,
+			Valid:     newValidRequest,
+			Missing:   newRequestWithoutCredentials,
+		})
+	}
+*/
+package basculetest
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+// NewRequest creates a server-side request, as with httptest.NewRequest, that
+// has no credentials.
+func NewRequest(method, target string) *http.Request {
+	return httptest.NewRequest(method, target, nil)
+}
+
+// NewAuthorizationRequest creates a server-side request with an Authorization
+// header composed of the given scheme and value.
+func NewAuthorizationRequest(method, target string, scheme basculehttp.Scheme, value string) *http.Request {
+	r := NewRequest(method, target)
+	r.Header.Set(basculehttp.DefaultAuthorizationHeader, string(scheme)+" "+value)
+	return r
+}
+
+// NewBasicAuthRequest creates a server-side request with basic auth credentials.
+func NewBasicAuthRequest(method, target, userName, password string) *http.Request {
+	r := NewRequest(method, target)
+	r.SetBasicAuth(userName, password)
+	return r
+}
+
+// NewBearerRequest creates a server-side request with a bearer token.
+func NewBearerRequest(method, target, token string) *http.Request {
+	return NewAuthorizationRequest(method, target, basculehttp.SchemeBearer, token)
+}
+
+// NewAuthenticatedRequest creates a server-side request whose context holds the
+// given token, as if the request had passed through basculehttp middleware.
+// This is useful for testing handlers that call bascule.GetFrom.
+func NewAuthenticatedRequest(method, target string, t bascule.Token) *http.Request {
+	return WithToken(NewRequest(method, target), t)
+}
+
+// WithToken returns a shallow copy of the request whose context holds the given token.
+func WithToken(r *http.Request, t bascule.Token) *http.Request {
+	return r.WithContext(
+		bascule.WithToken(r.Context(), t),
+	)
+}
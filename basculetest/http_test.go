@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+type HTTPTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HTTPTestSuite) TestNewRequest() {
+	r := NewRequest(http.MethodGet, "/test")
+	suite.Equal(http.MethodGet, r.Method)
+	suite.Equal("/test", r.URL.Path)
+	suite.Empty(r.Header.Get(basculehttp.DefaultAuthorizationHeader))
+}
+
+func (suite *HTTPTestSuite) TestNewAuthorizationRequest() {
+	r := NewAuthorizationRequest(http.MethodPost, "/test", "Custom", "value")
+	suite.Equal("Custom value", r.Header.Get(basculehttp.DefaultAuthorizationHeader))
+}
+
+func (suite *HTTPTestSuite) TestNewBasicAuthRequest() {
+	r := NewBasicAuthRequest(http.MethodGet, "/test", "joe", "secret")
+	userName, password, ok := r.BasicAuth()
+	suite.True(ok)
+	suite.Equal("joe", userName)
+	suite.Equal("secret", password)
+}
+
+func (suite *HTTPTestSuite) TestNewBearerRequest() {
+	r := NewBearerRequest(http.MethodGet, "/test", "abc.def.ghi")
+	suite.Equal("Bearer abc.def.ghi", r.Header.Get(basculehttp.DefaultAuthorizationHeader))
+}
+
+func (suite *HTTPTestSuite) TestNewAuthenticatedRequest() {
+	expected := NewToken("joe").Build()
+	r := NewAuthenticatedRequest(http.MethodGet, "/test", expected)
+
+	actual, ok := bascule.GetFrom(r)
+	suite.True(ok)
+	suite.Equal(expected, actual)
+}
+
+func TestHTTP(t *testing.T) {
+	suite.Run(t, new(HTTPTestSuite))
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/xmidt-org/bascule"
+)
+
+// MockTokenParser is a mocked bascule.TokenParser.  Results are scripted with
+// ExpectParse, e.g.:
+//
+//	m.ExpectParse(ctx, source).Return(token, nil).Once()
+type MockTokenParser[S any] struct {
+	mock.Mock
+}
+
+// Parse returns the scripted result for the given arguments.
+func (m *MockTokenParser[S]) Parse(ctx context.Context, source S) (bascule.Token, error) {
+	args := m.Called(ctx, source)
+	t, _ := args.Get(0).(bascule.Token)
+	return t, args.Error(1)
+}
+
+// ExpectParse sets up an expected call to Parse.  Use mock.Anything to match
+// any argument.
+func (m *MockTokenParser[S]) ExpectParse(ctx any, source any) *mock.Call {
+	return m.On("Parse", ctx, source)
+}
+
+// MockValidator is a mocked bascule.Validator.  Results are scripted with
+// ExpectValidate.  A nil token result means that the token is unchanged.
+type MockValidator[S any] struct {
+	mock.Mock
+}
+
+// Validate returns the scripted result for the given arguments.
+func (m *MockValidator[S]) Validate(ctx context.Context, source S, token bascule.Token) (bascule.Token, error) {
+	args := m.Called(ctx, source, token)
+	t, _ := args.Get(0).(bascule.Token)
+	return t, args.Error(1)
+}
+
+// ExpectValidate sets up an expected call to Validate.  Use mock.Anything to match
+// any argument.
+func (m *MockValidator[S]) ExpectValidate(ctx any, source any, token any) *mock.Call {
+	return m.On("Validate", ctx, source, token)
+}
+
+// MockApprover is a mocked bascule.Approver.  Results are scripted with ExpectApprove.
+type MockApprover[R any] struct {
+	mock.Mock
+}
+
+// Approve returns the scripted result for the given arguments.
+func (m *MockApprover[R]) Approve(ctx context.Context, resource R, token bascule.Token) error {
+	return m.Called(ctx, resource, token).Error(0)
+}
+
+// ExpectApprove sets up an expected call to Approve.  Use mock.Anything to match
+// any argument.
+func (m *MockApprover[R]) ExpectApprove(ctx any, resource any, token any) *mock.Call {
+	return m.On("Approve", ctx, resource, token)
+}
+
+// MockListener is a mocked bascule.Listener.  Use a Recorder instead when events
+// only need to be inspected after the fact.
+type MockListener[E any] struct {
+	mock.Mock
+}
+
+// OnEvent records the call to this mock.
+func (m *MockListener[E]) OnEvent(e E) {
+	m.Called(e)
+}
+
+// ExpectOnEvent sets up an expected call to OnEvent.  Use mock.Anything or
+// mock.MatchedBy to match events loosely.
+func (m *MockListener[E]) ExpectOnEvent(expected any) *mock.Call {
+	return m.On("OnEvent", expected)
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type MocksTestSuite struct {
+	suite.Suite
+
+	ctx context.Context
+}
+
+func (suite *MocksTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+}
+
+func (suite *MocksTestSuite) TestMockTokenParser() {
+	var (
+		m           = new(MockTokenParser[string])
+		expectedErr = errors.New("expected")
+	)
+
+	m.ExpectParse(suite.ctx, "first").Return(UnsupportedToken("joe"), nil).Once()
+	m.ExpectParse(mock.Anything, "second").Return(nil, expectedErr).Once()
+
+	t, err := m.Parse(suite.ctx, "first")
+	suite.Equal(UnsupportedToken("joe"), t)
+	suite.NoError(err)
+
+	t, err = m.Parse(suite.ctx, "second")
+	suite.Nil(t)
+	suite.ErrorIs(err, expectedErr)
+
+	m.AssertExpectations(suite.T())
+}
+
+func (suite *MocksTestSuite) TestMockValidator() {
+	var (
+		m           = new(MockValidator[string])
+		original    = UnsupportedToken("original")
+		replacement = UnsupportedToken("replacement")
+	)
+
+	m.ExpectValidate(suite.ctx, "source", original).Return(replacement, nil).Once()
+	m.ExpectValidate(suite.ctx, "source", replacement).Return(nil, bascule.ErrBadCredentials).Once()
+
+	t, err := m.Validate(suite.ctx, "source", original)
+	suite.Equal(replacement, t)
+	suite.NoError(err)
+
+	t, err = m.Validate(suite.ctx, "source", replacement)
+	suite.Nil(t)
+	suite.ErrorIs(err, bascule.ErrBadCredentials)
+
+	m.AssertExpectations(suite.T())
+}
+
+func (suite *MocksTestSuite) TestMockApprover() {
+	m := new(MockApprover[string])
+	m.ExpectApprove(suite.ctx, "resource", mock.Anything).Return(bascule.ErrUnauthorized).Once()
+
+	suite.ErrorIs(
+		m.Approve(suite.ctx, "resource", UnsupportedToken("joe")),
+		bascule.ErrUnauthorized,
+	)
+
+	m.AssertExpectations(suite.T())
+}
+
+func (suite *MocksTestSuite) TestMockListener() {
+	m := new(MockListener[int])
+	m.ExpectOnEvent(1).Once()
+	m.OnEvent(1)
+	m.AssertExpectations(suite.T())
+}
+
+func TestMocks(t *testing.T) {
+	suite.Run(t, new(MocksTestSuite))
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"slices"
+	"sync"
+)
+
+// Recorder is a bascule.Listener that records each event it receives.  A Recorder
+// is safe for concurrent use.  The zero value is ready to use.
+type Recorder[E any] struct {
+	lock   sync.Mutex
+	events []E
+}
+
+// OnEvent records the given event.
+func (r *Recorder[E]) OnEvent(e E) {
+	r.lock.Lock()
+	r.events = append(r.events, e)
+	r.lock.Unlock()
+}
+
+// Events returns a copy of the recorded events, in the order received.
+func (r *Recorder[E]) Events() []E {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Clone(r.events)
+}
+
+// Len returns the number of recorded events.
+func (r *Recorder[E]) Len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.events)
+}
+
+// Last returns the most recently recorded event.  If no events have been
+// recorded, this method returns false.
+func (r *Recorder[E]) Last() (e E, ok bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if n := len(r.events); n > 0 {
+		e, ok = r.events[n-1], true
+	}
+
+	return
+}
+
+// Reset discards all recorded events.
+func (r *Recorder[E]) Reset() {
+	r.lock.Lock()
+	r.events = nil
+	r.lock.Unlock()
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type RecorderTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RecorderTestSuite) TestEmpty() {
+	var r Recorder[int]
+	suite.Zero(r.Len())
+	suite.Empty(r.Events())
+
+	_, ok := r.Last()
+	suite.False(ok)
+}
+
+func (suite *RecorderTestSuite) TestOnEvent() {
+	var r Recorder[int]
+	r.OnEvent(1)
+	r.OnEvent(2)
+	suite.Equal(2, r.Len())
+	suite.Equal([]int{1, 2}, r.Events())
+
+	last, ok := r.Last()
+	suite.True(ok)
+	suite.Equal(2, last)
+
+	// Events must return a copy
+	r.Events()[0] = 99
+	suite.Equal([]int{1, 2}, r.Events())
+
+	r.Reset()
+	suite.Zero(r.Len())
+}
+
+func (suite *RecorderTestSuite) TestConcurrent() {
+	var (
+		r  Recorder[int]
+		wg sync.WaitGroup
+	)
+
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.OnEvent(i)
+		}()
+	}
+
+	wg.Wait()
+	suite.ElementsMatch([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, r.Events())
+}
+
+func (suite *RecorderTestSuite) TestAuthenticator() {
+	var r Recorder[bascule.AuthenticateEvent[string]]
+	a, err := bascule.NewAuthenticator(
+		bascule.WithTokenParsers(
+			bascule.AsTokenParser[string](func(string) (bascule.Token, error) {
+				return UnsupportedToken("joe"), nil
+			}),
+		),
+		bascule.WithAuthenticateListeners[string](&r),
+	)
+
+	suite.Require().NoError(err)
+	_, err = a.Authenticate(context.Background(), "source")
+	suite.Require().NoError(err)
+
+	e, ok := r.Last()
+	suite.Require().True(ok)
+	suite.Equal("source", e.Source)
+	suite.Equal(UnsupportedToken("joe"), e.Token)
+}
+
+func TestRecorder(t *testing.T) {
+	suite.Run(t, new(RecorderTestSuite))
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/xmidt-org/bascule"
+)
+
+// Token is the basic token created by a TokenBuilder.  Every Token is a
+// bascule.CapabilitiesAccessor and a bascule.AttributesAccessor, even if no
+// capabilities or attributes were supplied.
+type Token struct {
+	principal    string
+	capabilities []string
+	attributes   map[string]any
+}
+
+// Principal returns this token's principal.
+func (t *Token) Principal() string {
+	return t.principal
+}
+
+// Capabilities returns a copy of this token's capabilities.
+func (t *Token) Capabilities() []string {
+	return slices.Clone(t.capabilities)
+}
+
+// Get returns the value of a top-level attribute.
+func (t *Token) Get(key string) (v any, ok bool) {
+	v, ok = t.attributes[key]
+	return
+}
+
+// Attributes returns a copy of all of this token's attributes.
+func (t *Token) Attributes() map[string]any {
+	return maps.Clone(t.attributes)
+}
+
+// passwordToken is a Token that also carries a password.
+type passwordToken struct {
+	*Token
+	password string
+}
+
+func (pt passwordToken) Password() string {
+	return pt.password
+}
+
+// timeWindowToken is a Token that also carries a time window.
+type timeWindowToken struct {
+	*Token
+	expiration, notBefore, issuedAt time.Time
+}
+
+func (twt timeWindowToken) Expiration() time.Time { return twt.expiration }
+func (twt timeWindowToken) NotBefore() time.Time  { return twt.notBefore }
+func (twt timeWindowToken) IssuedAt() time.Time   { return twt.issuedAt }
+
+// passwordTimeWindowToken is a Token with both a password and a time window.
+type passwordTimeWindowToken struct {
+	timeWindowToken
+	password string
+}
+
+func (ptwt passwordTimeWindowToken) Password() string {
+	return ptwt.password
+}
+
+// TokenBuilder is a fluent builder for tokens.  Only the optional interfaces
+// that are configured are implemented by the built token, so that, for example,
+// bascule.GetPassword only succeeds when a password was supplied.
+type TokenBuilder struct {
+	principal    string
+	capabilities []string
+	attributes   map[string]any
+
+	password    *string
+	expiration  time.Time
+	notBefore   time.Time
+	issuedAt    time.Time
+	hasTimeData bool
+}
+
+// NewToken starts building a token with the given principal.
+func NewToken(principal string) *TokenBuilder {
+	return &TokenBuilder{
+		principal: principal,
+	}
+}
+
+// Capabilities appends capabilities to the token.
+func (tb *TokenBuilder) Capabilities(more ...string) *TokenBuilder {
+	tb.capabilities = append(tb.capabilities, more...)
+	return tb
+}
+
+// Attribute sets a single attribute.  Nested attributes can be supplied as
+// map[string]any values.
+func (tb *TokenBuilder) Attribute(key string, value any) *TokenBuilder {
+	if tb.attributes == nil {
+		tb.attributes = make(map[string]any)
+	}
+
+	tb.attributes[key] = value
+	return tb
+}
+
+// Attributes sets several attributes at once.
+func (tb *TokenBuilder) Attributes(more map[string]any) *TokenBuilder {
+	for k, v := range more {
+		tb.Attribute(k, v)
+	}
+
+	return tb
+}
+
+// Password gives the token a password, making it a bascule.Passworder.
+func (tb *TokenBuilder) Password(password string) *TokenBuilder {
+	tb.password = &password
+	return tb
+}
+
+// Expiration sets the token's expiry, making it a bascule.ExpirationAccessor,
+// bascule.NotBeforeAccessor, and bascule.IssuedAtAccessor.
+func (tb *TokenBuilder) Expiration(exp time.Time) *TokenBuilder {
+	tb.expiration = exp
+	tb.hasTimeData = true
+	return tb
+}
+
+// NotBefore sets the time at which the token becomes valid.  See Expiration.
+func (tb *TokenBuilder) NotBefore(nbf time.Time) *TokenBuilder {
+	tb.notBefore = nbf
+	tb.hasTimeData = true
+	return tb
+}
+
+// IssuedAt sets the time at which the token was issued.  See Expiration.
+func (tb *TokenBuilder) IssuedAt(iat time.Time) *TokenBuilder {
+	tb.issuedAt = iat
+	tb.hasTimeData = true
+	return tb
+}
+
+// Build creates the token.  A TokenBuilder may be used to build any number of
+// tokens, and changes to the builder do not affect tokens already built.
+func (tb *TokenBuilder) Build() bascule.Token {
+	base := &Token{
+		principal:    tb.principal,
+		capabilities: slices.Clone(tb.capabilities),
+		attributes:   maps.Clone(tb.attributes),
+	}
+
+	switch {
+	case tb.hasTimeData && tb.password != nil:
+		return passwordTimeWindowToken{
+			timeWindowToken: timeWindowToken{
+				Token:      base,
+				expiration: tb.expiration,
+				notBefore:  tb.notBefore,
+				issuedAt:   tb.issuedAt,
+			},
+			password: *tb.password,
+		}
+
+	case tb.hasTimeData:
+		return timeWindowToken{
+			Token:      base,
+			expiration: tb.expiration,
+			notBefore:  tb.notBefore,
+			issuedAt:   tb.issuedAt,
+		}
+
+	case tb.password != nil:
+		return passwordToken{
+			Token:    base,
+			password: *tb.password,
+		}
+
+	default:
+		return base
+	}
+}
+
+// UnsupportedToken is a token with only a principal.  It implements none of the
+// optional token interfaces, and is useful for verifying that components ignore
+// tokens they don't support.
+type UnsupportedToken string
+
+// Principal returns this token as a string.
+func (ut UnsupportedToken) Principal() string {
+	return string(ut)
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+// TokenParserSuite is a conformance suite that verifies a bascule.TokenParser
+// obeys the documented contract:
+//
+//   - a source without recognized credentials yields bascule.ErrMissingCredentials
+//   - a source with malformed credentials yields bascule.ErrInvalidCredentials
+//   - a nil token is always accompanied by a non-nil error
+type TokenParserSuite[S any] struct {
+	suite.Suite
+
+	// NewParser creates the TokenParser under test.  This field is required.
+	NewParser func() bascule.TokenParser[S]
+
+	// Valid creates a source with credentials that the parser accepts.  This
+	// field is required.
+	Valid func() S
+
+	// Principal is the expected principal of the token parsed from Valid.  If
+	// unset, the principal is not verified.
+	Principal string
+
+	// Missing creates a source with no credentials that the parser recognizes.
+	// This field is required.
+	Missing func() S
+
+	// Invalid creates a source whose credentials cannot be parsed.  If unset,
+	// the corresponding test is skipped.
+	Invalid func() S
+
+	// Bad creates a source whose credentials can be parsed, but which the parser
+	// rejects, e.g. with a wrong password.  If unset, the corresponding test is skipped.
+	Bad func() S
+}
+
+// SetupSuite verifies that the required fields are set.
+func (suite *TokenParserSuite[S]) SetupSuite() {
+	suite.Require().NotNil(suite.NewParser, "NewParser is required")
+	suite.Require().NotNil(suite.Valid, "Valid is required")
+	suite.Require().NotNil(suite.Missing, "Missing is required")
+}
+
+// parse creates a parser and parses the source, asserting that a nil token
+// is accompanied by an error.
+func (suite *TokenParserSuite[S]) parse(source S) (bascule.Token, error) {
+	tp := suite.NewParser()
+	suite.Require().NotNil(tp)
+
+	t, err := tp.Parse(context.Background(), source)
+	if t == nil {
+		suite.Require().Error(err, "a nil token must be accompanied by an error")
+	}
+
+	return t, err
+}
+
+func (suite *TokenParserSuite[S]) TestValid() {
+	t, err := suite.parse(suite.Valid())
+	suite.Require().NoError(err)
+	suite.Require().NotNil(t)
+
+	if len(suite.Principal) > 0 {
+		suite.Equal(suite.Principal, t.Principal())
+	}
+}
+
+func (suite *TokenParserSuite[S]) TestMissing() {
+	_, err := suite.parse(suite.Missing())
+	suite.ErrorIs(err, bascule.ErrMissingCredentials)
+}
+
+func (suite *TokenParserSuite[S]) TestInvalid() {
+	if suite.Invalid == nil {
+		suite.T().Skip("Invalid is not set")
+	}
+
+	_, err := suite.parse(suite.Invalid())
+	suite.ErrorIs(err, bascule.ErrInvalidCredentials)
+	suite.NotErrorIs(err, bascule.ErrMissingCredentials)
+}
+
+func (suite *TokenParserSuite[S]) TestBad() {
+	if suite.Bad == nil {
+		suite.T().Skip("Bad is not set")
+	}
+
+	_, err := suite.parse(suite.Bad())
+	suite.Error(err)
+	suite.NotErrorIs(err, bascule.ErrMissingCredentials)
+}
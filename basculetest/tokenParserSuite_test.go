@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+	"github.com/xmidt-org/bascule/basculehttp"
+)
+
+func TestTokenParserSuite(t *testing.T) {
+	suite.Run(t, &TokenParserSuite[*http.Request]{
+		NewParser: func() bascule.TokenParser[*http.Request] {
+			ap, err := basculehttp.NewAuthorizationParser(basculehttp.WithBasic())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ap
+		},
+		Valid: func() *http.Request {
+			return NewBasicAuthRequest(http.MethodGet, "/test", "joe", "secret")
+		},
+		Principal: "joe",
+		Missing: func() *http.Request {
+			return NewRequest(http.MethodGet, "/test")
+		},
+		Invalid: func() *http.Request {
+			return NewAuthorizationRequest(http.MethodGet, "/test", basculehttp.SchemeBasic, "this is not base64")
+		},
+	})
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+type TokenTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TokenTestSuite) TestBasic() {
+	t := NewToken("joe").
+		Capabilities("doc:read").
+		Capabilities("doc:write").
+		Attribute("level", 3).
+		Attributes(map[string]any{"nested": map[string]any{"key": "value"}}).
+		Build()
+
+	suite.Equal("joe", t.Principal())
+
+	caps, ok := bascule.GetCapabilities(t)
+	suite.True(ok)
+	suite.Equal([]string{"doc:read", "doc:write"}, caps)
+
+	var aa bascule.AttributesAccessor
+	suite.Require().True(bascule.TokenAs(t, &aa))
+	level, ok := bascule.GetAttribute[int](aa, "level")
+	suite.True(ok)
+	suite.Equal(3, level)
+
+	nested, ok := bascule.GetAttribute[string](aa, "nested", "key")
+	suite.True(ok)
+	suite.Equal("value", nested)
+
+	suite.Len(t.(*Token).Attributes(), 2)
+
+	_, ok = bascule.GetPassword(t)
+	suite.False(ok)
+
+	_, ok = bascule.GetExpiration(t)
+	suite.False(ok)
+}
+
+func (suite *TokenTestSuite) TestPassword() {
+	t := NewToken("joe").Password("secret").Build()
+
+	password, ok := bascule.GetPassword(t)
+	suite.True(ok)
+	suite.Equal("secret", password)
+
+	_, ok = bascule.GetExpiration(t)
+	suite.False(ok)
+}
+
+func (suite *TokenTestSuite) TestTimeWindow() {
+	var (
+		iat = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+		nbf = iat.Add(time.Minute)
+		exp = iat.Add(time.Hour)
+	)
+
+	suite.Run("NoPassword", func() {
+		t := NewToken("joe").IssuedAt(iat).NotBefore(nbf).Expiration(exp).Build()
+
+		actual, ok := bascule.GetExpiration(t)
+		suite.True(ok)
+		suite.Equal(exp, actual)
+
+		actual, ok = bascule.GetNotBefore(t)
+		suite.True(ok)
+		suite.Equal(nbf, actual)
+
+		actual, ok = bascule.GetIssuedAt(t)
+		suite.True(ok)
+		suite.Equal(iat, actual)
+
+		_, ok = bascule.GetPassword(t)
+		suite.False(ok)
+	})
+
+	suite.Run("WithPassword", func() {
+		t := NewToken("joe").Password("secret").Expiration(exp).Build()
+
+		actual, ok := bascule.GetExpiration(t)
+		suite.True(ok)
+		suite.Equal(exp, actual)
+
+		password, ok := bascule.GetPassword(t)
+		suite.True(ok)
+		suite.Equal("secret", password)
+	})
+}
+
+func (suite *TokenTestSuite) TestBuilderReuse() {
+	b := NewToken("joe").Capabilities("doc:read").Attribute("level", 1)
+	first := b.Build()
+
+	b.Capabilities("doc:write").Attribute("level", 2)
+	second := b.Build()
+
+	caps, _ := bascule.GetCapabilities(first)
+	suite.Equal([]string{"doc:read"}, caps)
+	level, _ := first.(*Token).Get("level")
+	suite.Equal(1, level)
+
+	caps, _ = bascule.GetCapabilities(second)
+	suite.Equal([]string{"doc:read", "doc:write"}, caps)
+	level, _ = second.(*Token).Get("level")
+	suite.Equal(2, level)
+}
+
+func (suite *TokenTestSuite) TestUnsupportedToken() {
+	t := UnsupportedToken("joe")
+	suite.Equal("joe", t.Principal())
+
+	_, ok := bascule.GetCapabilities(t)
+	suite.False(ok)
+}
+
+func TestToken(t *testing.T) {
+	suite.Run(t, new(TokenTestSuite))
+}
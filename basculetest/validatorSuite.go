@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+// ValidatorSuite is a conformance suite that verifies a bascule.Validator obeys
+// the documented contract:
+//
+//   - a valid token yields a nil error, along with either a nil token or a replacement
+//   - an invalid token yields a non-nil error
+//   - a token the validator doesn't support yields a nil error and is left unchanged
+type ValidatorSuite[S any] struct {
+	suite.Suite
+
+	// NewValidator creates the Validator under test.  This field is required.
+	NewValidator func() bascule.Validator[S]
+
+	// Source creates the source passed to the validator.  If unset, the zero
+	// value of S is used.
+	Source func() S
+
+	// Valid creates a token that the validator accepts.  This field is required.
+	Valid func() bascule.Token
+
+	// Invalid creates a token that the validator rejects.  If unset, the
+	// corresponding test is skipped.
+	Invalid func() bascule.Token
+
+	// Unsupported creates a token that the validator does not support.  If unset,
+	// the corresponding test is skipped.  UnsupportedToken is often a good choice.
+	Unsupported func() bascule.Token
+}
+
+// SetupSuite verifies that the required fields are set.
+func (suite *ValidatorSuite[S]) SetupSuite() {
+	suite.Require().NotNil(suite.NewValidator, "NewValidator is required")
+	suite.Require().NotNil(suite.Valid, "Valid is required")
+}
+
+func (suite *ValidatorSuite[S]) validate(t bascule.Token) (bascule.Token, error) {
+	v := suite.NewValidator()
+	suite.Require().NotNil(v)
+
+	var source S
+	if suite.Source != nil {
+		source = suite.Source()
+	}
+
+	return v.Validate(context.Background(), source, t)
+}
+
+func (suite *ValidatorSuite[S]) TestValid() {
+	_, err := suite.validate(suite.Valid())
+	suite.NoError(err)
+}
+
+func (suite *ValidatorSuite[S]) TestInvalid() {
+	if suite.Invalid == nil {
+		suite.T().Skip("Invalid is not set")
+	}
+
+	_, err := suite.validate(suite.Invalid())
+	suite.Error(err)
+}
+
+func (suite *ValidatorSuite[S]) TestUnsupported() {
+	if suite.Unsupported == nil {
+		suite.T().Skip("Unsupported is not set")
+	}
+
+	original := suite.Unsupported()
+	next, err := suite.validate(original)
+	suite.NoError(err, "unsupported tokens must be ignored")
+	if next != nil {
+		suite.Equal(original, next, "unsupported tokens must not be replaced")
+	}
+}
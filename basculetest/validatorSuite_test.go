@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package basculetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/bascule"
+)
+
+func TestValidatorSuite(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	suite.Run(t, &ValidatorSuite[string]{
+		NewValidator: func() bascule.Validator[string] {
+			v, err := bascule.NewTimeWindowValidator[string](
+				bascule.WithClock(func() time.Time { return now }),
+			)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return v
+		},
+		Valid: func() bascule.Token {
+			return NewToken("joe").Expiration(now.Add(time.Hour)).Build()
+		},
+		Invalid: func() bascule.Token {
+			return NewToken("joe").Expiration(now.Add(-time.Hour)).Build()
+		},
+		Unsupported: func() bascule.Token {
+			return UnsupportedToken("joe")
+		},
+	})
+}
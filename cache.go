@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is the default amount of time a successful authentication is cached.
+	DefaultCacheTTL = time.Minute
+
+	// DefaultCacheNegativeTTL is the default amount of time a failed authentication is cached.
+	DefaultCacheNegativeTTL = 5 * time.Second
+
+	// DefaultCacheMaxSize is the default maximum number of entries held in a cache.
+	DefaultCacheMaxSize = 1024
+)
+
+var (
+	// ErrInvalidCacheConfig indicates that a cache option was given an invalid value,
+	// e.g. a negative TTL.
+	ErrInvalidCacheConfig = errors.New("invalid cache configuration")
+)
+
+// CacheKeyFunc extracts the raw credential from a source, e.g. the value of an
+// Authorization header.  If the source carries no credential, this closure must
+// return false, in which case the source is never cached.
+type CacheKeyFunc[S any] func(S) ([]byte, bool)
+
+// CacheOption is a configurable option for an AuthenticateCache.
+type CacheOption interface {
+	apply(*cacheConfig) error
+}
+
+type cacheOptionFunc func(*cacheConfig) error
+
+func (cof cacheOptionFunc) apply(cc *cacheConfig) error { return cof(cc) }
+
+// WithCacheTTL sets the maximum time a successful authentication is cached.  A
+// cached token never outlives its own expiration, as reported by GetExpiration.
+// If this option is not supplied, DefaultCacheTTL is used.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return cacheOptionFunc(func(cc *cacheConfig) error {
+		if ttl <= 0 {
+			return ErrInvalidCacheConfig
+		}
+
+		cc.ttl = ttl
+		return nil
+	})
+}
+
+// WithCacheNegativeTTL sets the time a failed authentication is cached.  A zero
+// value disables negative caching.  If this option is not supplied,
+// DefaultCacheNegativeTTL is used.
+func WithCacheNegativeTTL(ttl time.Duration) CacheOption {
+	return cacheOptionFunc(func(cc *cacheConfig) error {
+		if ttl < 0 {
+			return ErrInvalidCacheConfig
+		}
+
+		cc.negativeTTL = ttl
+		return nil
+	})
+}
+
+// WithCacheMaxSize sets the maximum number of entries in the cache.  When the cache
+// is full, the least recently used entry is evicted.  If this option is not supplied,
+// DefaultCacheMaxSize is used.
+func WithCacheMaxSize(maxSize int) CacheOption {
+	return cacheOptionFunc(func(cc *cacheConfig) error {
+		if maxSize < 1 {
+			return ErrInvalidCacheConfig
+		}
+
+		cc.maxSize = maxSize
+		return nil
+	})
+}
+
+// WithCacheClock sets the closure used to obtain the current time.  By default,
+// time.Now is used.  A nil closure restores the default.
+func WithCacheClock(now func() time.Time) CacheOption {
+	return cacheOptionFunc(func(cc *cacheConfig) error {
+		cc.now = now
+		return nil
+	})
+}
+
+type cacheConfig struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxSize     int
+	now         func() time.Time
+}
+
+// cacheKey is the secure hash of a raw credential.  Raw credentials
+// are never stored by the cache.
+type cacheKey [sha256.Size]byte
+
+type cacheEntry struct {
+	key     cacheKey
+	token   Token
+	err     error
+	expires time.Time
+}
+
+// AuthenticateCache holds the results of authentication, keyed by a secure hash of
+// the raw credential presented by a source.  Use WithAuthenticateCache to enable
+// caching for an Authenticator.
+//
+// Successful results are cached for the configured TTL, but never beyond the token's
+// own expiration.  Failed results are cached for the shorter negative TTL, but only
+// when the failure is due to the credentials themselves, i.e. ErrBadCredentials,
+// ErrInvalidCredentials, or ErrTokenExpired.  Other errors, such as a failure to
+// contact an external system, are never cached.
+//
+// A cache should only be used when validation depends solely on the credential and
+// not on other parts of the source.
+//
+// A cache hit skips parsing and every validator for as long as the entry lives.  This
+// includes validators whose outcome can change for the same credential over time, such
+// as those created by NewRevocationValidator, basculehash lockout validators, and
+// NewTimeWindowValidator.  For example, a revoked token continues to authenticate from
+// the cache until its entry expires.  Keep the TTL short when such validators are in
+// use, and remove affected entries with Delete, Invalidate, or InvalidateFunc when the
+// underlying state changes.  InvalidateOnRevoke does this for revocations.
+//
+// An AuthenticateCache is safe for concurrent use.
+type AuthenticateCache[S any] struct {
+	keyFunc CacheKeyFunc[S]
+	hashKey []byte
+	cfg     cacheConfig
+
+	lock    sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+}
+
+// NewAuthenticateCache creates an AuthenticateCache that uses the given closure to
+// obtain the raw credential from each source.
+func NewAuthenticateCache[S any](keyFunc CacheKeyFunc[S], opts ...CacheOption) (*AuthenticateCache[S], error) {
+	if keyFunc == nil {
+		return nil, ErrInvalidCacheConfig
+	}
+
+	ac := &AuthenticateCache[S]{
+		keyFunc: keyFunc,
+		hashKey: make([]byte, sha256.BlockSize),
+		cfg: cacheConfig{
+			ttl:         DefaultCacheTTL,
+			negativeTTL: DefaultCacheNegativeTTL,
+			maxSize:     DefaultCacheMaxSize,
+		},
+		entries: make(map[cacheKey]*list.Element),
+		lru:     list.New(),
+	}
+
+	for _, o := range opts {
+		if err := o.apply(&ac.cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if ac.cfg.now == nil {
+		ac.cfg.now = time.Now
+	}
+
+	// a random, per-cache key ensures that digests are useless outside this process
+	if _, err := rand.Read(ac.hashKey); err != nil {
+		return nil, err
+	}
+
+	return ac, nil
+}
+
+// Len returns the number of entries currently in this cache, including any
+// expired entries that have not yet been evicted.
+func (ac *AuthenticateCache[S]) Len() (n int) {
+	ac.lock.Lock()
+	n = ac.lru.Len()
+	ac.lock.Unlock()
+	return
+}
+
+// Purge removes all entries from this cache.
+func (ac *AuthenticateCache[S]) Purge() {
+	ac.lock.Lock()
+	clear(ac.entries)
+	ac.lru.Init()
+	ac.lock.Unlock()
+}
+
+// Delete removes the entry for the credential carried by the given source.  This
+// method returns true if an entry was removed.  A source without a credential, as
+// determined by this cache's CacheKeyFunc, never has an entry.
+func (ac *AuthenticateCache[S]) Delete(source S) (deleted bool) {
+	k, ok := ac.key(source)
+	if !ok {
+		return
+	}
+
+	ac.lock.Lock()
+	if e, exists := ac.entries[k]; exists {
+		ac.remove(e)
+		deleted = true
+	}
+
+	ac.lock.Unlock()
+	return
+}
+
+// Invalidate removes every successful result whose token has the given principal.
+// This method returns the number of entries removed.
+func (ac *AuthenticateCache[S]) Invalidate(principal string) int {
+	return ac.InvalidateFunc(func(t Token) bool {
+		return t.Principal() == principal
+	})
+}
+
+// InvalidateFunc removes every successful result whose token satisfies the given
+// predicate.  Cached failures are unaffected.  This method returns the number of
+// entries removed.
+//
+// The predicate is invoked while this cache is locked, so it must not call back
+// into this cache.
+func (ac *AuthenticateCache[S]) InvalidateFunc(f func(Token) bool) (n int) {
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	for e := ac.lru.Front(); e != nil; {
+		next := e.Next()
+		if ce := e.Value.(*cacheEntry); ce.token != nil && ce.err == nil && f(ce.token) {
+			ac.remove(e)
+			n++
+		}
+
+		e = next
+	}
+
+	return
+}
+
+// key computes the cache key for a source.  If the source has no credential,
+// this method returns false.
+func (ac *AuthenticateCache[S]) key(source S) (k cacheKey, ok bool) {
+	var raw []byte
+	if raw, ok = ac.keyFunc(source); ok {
+		h := hmac.New(sha256.New, ac.hashKey)
+		h.Write(raw)
+		h.Sum(k[:0])
+	}
+
+	return
+}
+
+// get returns the cached result for a key, if one exists and has not expired.
+func (ac *AuthenticateCache[S]) get(k cacheKey) (ce cacheEntry, hit bool) {
+	now := ac.cfg.now()
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	if e, exists := ac.entries[k]; exists {
+		if cached := e.Value.(*cacheEntry); now.Before(cached.expires) {
+			ac.lru.MoveToFront(e)
+			return *cached, true
+		}
+
+		ac.remove(e)
+	}
+
+	return
+}
+
+// negativelyCacheable tests if an authentication error is due to the credentials
+// themselves, as opposed to some transient condition.
+func negativelyCacheable(err error) bool {
+	return errors.Is(err, ErrBadCredentials) ||
+		errors.Is(err, ErrInvalidCredentials) ||
+		errors.Is(err, ErrTokenExpired)
+}
+
+// put caches the result of an authentication, if appropriate.
+func (ac *AuthenticateCache[S]) put(k cacheKey, token Token, err error) {
+	now := ac.cfg.now()
+	var expires time.Time
+	switch {
+	case err == nil:
+		expires = now.Add(ac.cfg.ttl)
+		if exp, ok := GetExpiration(token); ok && exp.Before(expires) {
+			expires = exp
+		}
+
+	case ac.cfg.negativeTTL > 0 && negativelyCacheable(err):
+		expires = now.Add(ac.cfg.negativeTTL)
+
+	default:
+		return
+	}
+
+	if !now.Before(expires) {
+		return
+	}
+
+	ac.lock.Lock()
+	defer ac.lock.Unlock()
+
+	if e, exists := ac.entries[k]; exists {
+		ac.remove(e)
+	}
+
+	for ac.lru.Len() >= ac.cfg.maxSize {
+		ac.remove(ac.lru.Back())
+	}
+
+	ac.entries[k] = ac.lru.PushFront(&cacheEntry{
+		key:     k,
+		token:   token,
+		err:     err,
+		expires: expires,
+	})
+}
+
+// remove deletes an element from this cache.  The lock must be held.
+func (ac *AuthenticateCache[S]) remove(e *list.Element) {
+	ac.lru.Remove(e)
+	delete(ac.entries, e.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,361 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheTestSuite struct {
+	TestSuite
+
+	now time.Time
+}
+
+func (suite *CacheTestSuite) SetupTest() {
+	suite.now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *CacheTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *CacheTestSuite) keyFunc(source string) ([]byte, bool) {
+	return []byte(source), len(source) > 0
+}
+
+func (suite *CacheTestSuite) newCache(opts ...CacheOption) *AuthenticateCache[string] {
+	ac, err := NewAuthenticateCache(
+		suite.keyFunc,
+		append([]CacheOption{WithCacheClock(suite.clock)}, opts...)...,
+	)
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(ac)
+	return ac
+}
+
+// putSource caches a result for the given source.
+func (suite *CacheTestSuite) putSource(ac *AuthenticateCache[string], source string, token Token, err error) {
+	k, ok := ac.key(source)
+	suite.Require().True(ok)
+	ac.put(k, token, err)
+}
+
+// getSource returns the cached result for the given source.
+func (suite *CacheTestSuite) getSource(ac *AuthenticateCache[string], source string) (cacheEntry, bool) {
+	k, ok := ac.key(source)
+	suite.Require().True(ok)
+	return ac.get(k)
+}
+
+func (suite *CacheTestSuite) TestInvalidConfig() {
+	testCases := []struct {
+		name    string
+		keyFunc CacheKeyFunc[string]
+		opts    []CacheOption
+	}{
+		{
+			name: "NilKeyFunc",
+		},
+		{
+			name:    "ZeroTTL",
+			keyFunc: suite.keyFunc,
+			opts:    []CacheOption{WithCacheTTL(0)},
+		},
+		{
+			name:    "NegativeNegativeTTL",
+			keyFunc: suite.keyFunc,
+			opts:    []CacheOption{WithCacheNegativeTTL(-time.Second)},
+		},
+		{
+			name:    "ZeroMaxSize",
+			keyFunc: suite.keyFunc,
+			opts:    []CacheOption{WithCacheMaxSize(0)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			ac, err := NewAuthenticateCache(testCase.keyFunc, testCase.opts...)
+			suite.Nil(ac)
+			suite.ErrorIs(err, ErrInvalidCacheConfig)
+		})
+	}
+}
+
+func (suite *CacheTestSuite) TestKey() {
+	ac := suite.newCache()
+
+	_, ok := ac.key("")
+	suite.False(ok)
+
+	k1, ok := ac.key("credential")
+	suite.True(ok)
+
+	k2, _ := ac.key("credential")
+	suite.Equal(k1, k2)
+
+	k3, _ := ac.key("other")
+	suite.NotEqual(k1, k3)
+
+	// a different cache uses a different hash key
+	k4, _ := suite.newCache().key("credential")
+	suite.NotEqual(k1, k4)
+}
+
+func (suite *CacheTestSuite) TestTTL() {
+	ac := suite.newCache(WithCacheTTL(time.Minute))
+	suite.putSource(ac, "credential", suite.testToken(), nil)
+	suite.Equal(1, ac.Len())
+
+	suite.now = suite.now.Add(59 * time.Second)
+	ce, hit := suite.getSource(ac, "credential")
+	suite.True(hit)
+	suite.Equal(suite.testToken(), ce.token)
+	suite.NoError(ce.err)
+
+	suite.now = suite.now.Add(time.Second)
+	_, hit = suite.getSource(ac, "credential")
+	suite.False(hit)
+	suite.Zero(ac.Len())
+}
+
+func (suite *CacheTestSuite) TestTokenExpiration() {
+	suite.Run("CapsTTL", func() {
+		ac := suite.newCache(WithCacheTTL(time.Hour))
+		suite.putSource(ac, "credential", timeWindowToken{exp: suite.now.Add(time.Minute)}, nil)
+
+		_, hit := suite.getSource(ac, "credential")
+		suite.True(hit)
+
+		suite.now = suite.now.Add(time.Minute)
+		_, hit = suite.getSource(ac, "credential")
+		suite.False(hit)
+	})
+
+	suite.Run("AlreadyExpired", func() {
+		ac := suite.newCache()
+		suite.putSource(ac, "credential", timeWindowToken{exp: suite.now}, nil)
+		suite.Zero(ac.Len())
+	})
+}
+
+func (suite *CacheTestSuite) TestNegative() {
+	testCases := []struct {
+		name     string
+		err      error
+		opts     []CacheOption
+		expected bool
+	}{
+		{
+			name:     "BadCredentials",
+			err:      ErrBadCredentials,
+			expected: true,
+		},
+		{
+			name:     "InvalidCredentials",
+			err:      ErrInvalidCredentials,
+			expected: true,
+		},
+		{
+			name:     "TokenExpired",
+			err:      ErrTokenExpired,
+			expected: true,
+		},
+		{
+			name: "Transient",
+			err:  errors.New("expected"),
+		},
+		{
+			name: "MissingCredentials",
+			err:  ErrMissingCredentials,
+		},
+		{
+			name: "Disabled",
+			err:  ErrBadCredentials,
+			opts: []CacheOption{WithCacheNegativeTTL(0)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			ac := suite.newCache(testCase.opts...)
+			suite.putSource(ac, "credential", nil, testCase.err)
+
+			ce, hit := suite.getSource(ac, "credential")
+			suite.Equal(testCase.expected, hit)
+			if hit {
+				suite.ErrorIs(ce.err, testCase.err)
+				suite.Nil(ce.token)
+
+				suite.now = suite.now.Add(DefaultCacheNegativeTTL)
+				_, hit = suite.getSource(ac, "credential")
+				suite.False(hit)
+			}
+		})
+	}
+}
+
+func (suite *CacheTestSuite) TestMaxSize() {
+	ac := suite.newCache(WithCacheMaxSize(2))
+	suite.putSource(ac, "one", suite.testToken(), nil)
+	suite.putSource(ac, "two", suite.testToken(), nil)
+
+	// touch "one" so that "two" is the least recently used
+	_, hit := suite.getSource(ac, "one")
+	suite.True(hit)
+
+	suite.putSource(ac, "three", suite.testToken(), nil)
+	suite.Equal(2, ac.Len())
+
+	_, hit = suite.getSource(ac, "two")
+	suite.False(hit)
+
+	_, hit = suite.getSource(ac, "one")
+	suite.True(hit)
+
+	_, hit = suite.getSource(ac, "three")
+	suite.True(hit)
+
+	// replacing an entry does not grow the cache
+	suite.putSource(ac, "three", StubToken("replaced"), nil)
+	suite.Equal(2, ac.Len())
+	ce, _ := suite.getSource(ac, "three")
+	suite.Equal(StubToken("replaced"), ce.token)
+}
+
+func (suite *CacheTestSuite) TestPurge() {
+	ac := suite.newCache()
+	suite.putSource(ac, "one", suite.testToken(), nil)
+	suite.putSource(ac, "two", suite.testToken(), nil)
+	suite.Equal(2, ac.Len())
+
+	ac.Purge()
+	suite.Zero(ac.Len())
+
+	_, hit := suite.getSource(ac, "one")
+	suite.False(hit)
+}
+
+func (suite *CacheTestSuite) TestDelete() {
+	ac := suite.newCache()
+	suite.putSource(ac, "one", suite.testToken(), nil)
+	suite.putSource(ac, "two", nil, ErrBadCredentials)
+
+	suite.True(ac.Delete("one"))
+	suite.False(ac.Delete("one"))
+	suite.False(ac.Delete(""))
+	suite.True(ac.Delete("two"))
+	suite.Zero(ac.Len())
+}
+
+func (suite *CacheTestSuite) TestInvalidate() {
+	ac := suite.newCache()
+	suite.putSource(ac, "one", StubToken("joe"), nil)
+	suite.putSource(ac, "two", StubToken("joe"), nil)
+	suite.putSource(ac, "three", StubToken("jane"), nil)
+	suite.putSource(ac, "four", nil, ErrBadCredentials)
+
+	suite.Equal(2, ac.Invalidate("joe"))
+	suite.Equal(2, ac.Len())
+
+	_, hit := suite.getSource(ac, "one")
+	suite.False(hit)
+
+	_, hit = suite.getSource(ac, "three")
+	suite.True(hit)
+
+	_, hit = suite.getSource(ac, "four")
+	suite.True(hit)
+
+	suite.Equal(1, ac.InvalidateFunc(func(t Token) bool { return true }))
+	suite.Zero(ac.Invalidate("joe"))
+	suite.Equal(1, ac.Len())
+}
+
+func (suite *CacheTestSuite) TestAuthenticator() {
+	suite.Run("Success", func() {
+		var (
+			ctx      = context.Background()
+			parser   = new(mockTokenParser[string])
+			listener = new(mockAuthenticateListener[string])
+		)
+
+		a, err := NewAuthenticator(
+			WithTokenParsers[string](parser),
+			WithAuthenticateListeners[string](listener),
+			WithAuthenticateClock[string](suite.clock),
+			WithAuthenticateCache(suite.newCache()),
+		)
+
+		suite.Require().NoError(err)
+
+		parser.ExpectParse(ctx, "credential").
+			Return(suite.testToken(), error(nil)).Once()
+
+		// only the first event involves parsing
+		listener.ExpectOnEvent(AuthenticateEvent[string]{
+			Source:   "credential",
+			Token:    suite.testToken(),
+			Attempts: []ParseAttempt{{Index: 0, Name: "*bascule.mockTokenParser[string]", Token: suite.testToken()}},
+			Start:    suite.now,
+		}).Once()
+
+		listener.ExpectOnEvent(AuthenticateEvent[string]{
+			Source: "credential",
+			Token:  suite.testToken(),
+			Start:  suite.now,
+		}).Twice()
+
+		for i := 0; i < 3; i++ {
+			token, err := a.Authenticate(ctx, "credential")
+			suite.NoError(err)
+			suite.Equal(suite.testToken(), token)
+		}
+
+		parser.AssertExpectations(suite.T())
+		listener.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Uncacheable", func() {
+		var (
+			ctx         = context.Background()
+			expectedErr = errors.New("expected")
+			parser      = new(mockTokenParser[string])
+		)
+
+		a, err := NewAuthenticator(
+			WithTokenParsers[string](parser),
+			WithAuthenticateCache(suite.newCache()),
+		)
+
+		suite.Require().NoError(err)
+
+		parser.ExpectParse(ctx, "").
+			Return(suite.testToken(), error(nil)).Twice()
+
+		parser.ExpectParse(ctx, "credential").
+			Return(Token(nil), expectedErr).Twice()
+
+		for i := 0; i < 2; i++ {
+			_, err := a.Authenticate(ctx, "")
+			suite.NoError(err)
+
+			_, err = a.Authenticate(ctx, "credential")
+			suite.ErrorIs(err, expectedErr)
+		}
+
+		parser.AssertExpectations(suite.T())
+	})
+}
+
+func TestCache(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
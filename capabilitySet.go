@@ -0,0 +1,326 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// permissionNode is a node in the trie that indexes a CapabilitySet.  Each level
+// of the trie corresponds to a part of a permission.
+type permissionNode struct {
+	children map[string]*permissionNode
+	wildcard *permissionNode
+
+	// terminal is true if a permission ends at this node.  A terminal
+	// node implies every permission beneath it.
+	terminal bool
+}
+
+func (n *permissionNode) insert(parts []string) {
+	for _, part := range parts {
+		var next *permissionNode
+		if part == PermissionWildcard {
+			if n.wildcard == nil {
+				n.wildcard = new(permissionNode)
+			}
+
+			next = n.wildcard
+		} else {
+			if n.children == nil {
+				n.children = make(map[string]*permissionNode)
+			}
+
+			if next = n.children[part]; next == nil {
+				next = new(permissionNode)
+				n.children[part] = next
+			}
+		}
+
+		n = next
+	}
+
+	n.terminal = true
+}
+
+// wildcardTail tests if a permission ends at or below this node via only wildcards.
+func (n *permissionNode) wildcardTail() bool {
+	for ; n != nil; n = n.wildcard {
+		if n.terminal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// implies tests if any permission in this subtrie implies the single-valued permission
+// given by parts.
+func (n *permissionNode) implies(parts []string) bool {
+	if n == nil {
+		return false
+	}
+
+	if n.terminal {
+		return true
+	}
+
+	if len(parts) == 0 {
+		return n.wildcardTail()
+	}
+
+	if parts[0] != PermissionWildcard && n.children[parts[0]].implies(parts[1:]) {
+		return true
+	}
+
+	return n.wildcard.implies(parts[1:])
+}
+
+// CapabilitySet is an immutable set of permissions, indexed for efficient lookup.
+// The zero value is an empty set.
+//
+// A set implies a permission if every combination of that permission's subparts is
+// implied by some member of the set.  For example, the set {"doc:read", "doc:write"}
+// implies "doc:read,write" even though neither member does on its own.
+type CapabilitySet struct {
+	permissions []Permission
+	root        *permissionNode
+}
+
+// NewCapabilitySet creates a set from zero or more permissions.  Zero value permissions
+// and duplicates are ignored.
+func NewCapabilitySet(permissions ...Permission) CapabilitySet {
+	cs := CapabilitySet{
+		root: new(permissionNode),
+	}
+
+	seen := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		if key := p.String(); !p.IsZero() && !seen[key] {
+			seen[key] = true
+			cs.permissions = append(cs.permissions, p)
+			p.expand(func(parts []string) bool {
+				cs.root.insert(parts)
+				return true
+			})
+		}
+	}
+
+	return cs
+}
+
+// ParseCapabilitySet parses each capability as a Permission and returns the set.
+// The first parse error encountered is returned.
+func ParseCapabilitySet(capabilities ...string) (CapabilitySet, error) {
+	permissions := make([]Permission, 0, len(capabilities))
+	for _, c := range capabilities {
+		p, err := ParsePermission(c)
+		if err != nil {
+			return CapabilitySet{}, err
+		}
+
+		permissions = append(permissions, p)
+	}
+
+	return NewCapabilitySet(permissions...), nil
+}
+
+// CapabilitySetAccessor is implemented by tokens that carry a prebuilt CapabilitySet,
+// such as those produced by WithCapabilitySet.
+type CapabilitySetAccessor interface {
+	// CapabilitySet returns the token's capabilities as a CapabilitySet.
+	CapabilitySet() CapabilitySet
+}
+
+// GetCapabilitySet returns the CapabilitySet for a token.  If the token tree contains
+// a CapabilitySetAccessor, its set is returned as is.  Otherwise, a set is built from
+// the token's capabilities, as given by CapabilitiesAccessor.  Capabilities that are
+// not valid permissions are skipped.  If the token has neither accessor, the returned
+// set is empty and this function returns false.
+func GetCapabilitySet(t Token) (cs CapabilitySet, ok bool) {
+	var csa CapabilitySetAccessor
+	if TokenAs(t, &csa) {
+		return csa.CapabilitySet(), true
+	}
+
+	var ca CapabilitiesAccessor
+	if ok = TokenAs(t, &ca); ok {
+		capabilities := ca.Capabilities()
+		permissions := make([]Permission, 0, len(capabilities))
+		for _, c := range capabilities {
+			if p, err := ParsePermission(c); err == nil {
+				permissions = append(permissions, p)
+			}
+		}
+
+		cs = NewCapabilitySet(permissions...)
+	}
+
+	return
+}
+
+// capabilitySetToken decorates a token with a prebuilt CapabilitySet.
+type capabilitySetToken struct {
+	Token
+	cs CapabilitySet
+}
+
+// CapabilitySet returns the prebuilt set.
+func (cst capabilitySetToken) CapabilitySet() CapabilitySet {
+	return cst.cs
+}
+
+// Unwrap returns the decorated token.
+func (cst capabilitySetToken) Unwrap() Token {
+	return cst.Token
+}
+
+// WithCapabilitySet decorates a token so that its CapabilitySet is built only once,
+// rather than each time GetCapabilitySet is called.  If the token already carries a
+// CapabilitySet, or has no capabilities, it is returned as is.
+func WithCapabilitySet(t Token) Token {
+	var csa CapabilitySetAccessor
+	if TokenAs(t, &csa) {
+		return t
+	}
+
+	cs, ok := GetCapabilitySet(t)
+	if !ok {
+		return t
+	}
+
+	return capabilitySetToken{
+		Token: t,
+		cs:    cs,
+	}
+}
+
+// NewCapabilitySetValidator creates a Validator that replaces each token with the result
+// of WithCapabilitySet.  Placing this validator last allows permission Approvers to reuse
+// the same index on every request, and with an AuthenticateCache, across requests for the
+// same credentials.
+func NewCapabilitySetValidator[S any]() Validator[S] {
+	return AsValidator[S](func(t Token) (Token, error) {
+		return WithCapabilitySet(t), nil
+	})
+}
+
+// Len returns the number of distinct permissions in this set.
+func (cs CapabilitySet) Len() int {
+	return len(cs.permissions)
+}
+
+// Permissions returns a copy of the permissions in this set, in the order in
+// which they were added.
+func (cs CapabilitySet) Permissions() []Permission {
+	return slices.Clone(cs.permissions)
+}
+
+// Strings returns the canonical text form of each permission in this set.
+func (cs CapabilitySet) Strings() []string {
+	s := make([]string, len(cs.permissions))
+	for i, p := range cs.permissions {
+		s[i] = p.String()
+	}
+
+	return s
+}
+
+// String returns a human-readable representation of this set.
+func (cs CapabilitySet) String() string {
+	return "[" + strings.Join(cs.Strings(), " ") + "]"
+}
+
+// Implies tests if this set grants the given permission.  Lookups use an index
+// over the set, so the cost does not depend on the number of permissions in the set.
+func (cs CapabilitySet) Implies(p Permission) bool {
+	if cs.root == nil || p.IsZero() {
+		return false
+	}
+
+	return p.expand(cs.root.implies)
+}
+
+// ImpliesAll tests if this set grants every permission in another set.  Any set
+// implies the empty set.
+func (cs CapabilitySet) ImpliesAll(other CapabilitySet) bool {
+	for _, p := range other.permissions {
+		if !cs.Implies(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Union returns a set containing the permissions of both sets.
+func (cs CapabilitySet) Union(other CapabilitySet) CapabilitySet {
+	return NewCapabilitySet(append(slices.Clone(cs.permissions), other.permissions...)...)
+}
+
+// Intersection returns a set that grants only what both sets grant.  The result contains
+// each permission from either set that the other set implies.  For example, the
+// intersection of {"doc:*"} and {"doc:read", "mail:send"} is {"doc:read"}.
+func (cs CapabilitySet) Intersection(other CapabilitySet) CapabilitySet {
+	var permissions []Permission
+	for _, p := range cs.permissions {
+		if other.Implies(p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	for _, p := range other.permissions {
+		if cs.Implies(p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	return NewCapabilitySet(permissions...)
+}
+
+// PermissionDeniedError is returned by a permission Approver when a token's
+// capabilities do not imply the required permission.  This error always has
+// ErrUnauthorized in its chain.
+type PermissionDeniedError struct {
+	// Required is the permission that was not granted.
+	Required Permission
+}
+
+// Unwrap returns ErrUnauthorized.
+func (pde *PermissionDeniedError) Unwrap() error {
+	return ErrUnauthorized
+}
+
+func (pde *PermissionDeniedError) Error() string {
+	return "permission denied: " + pde.Required.String()
+}
+
+// RequiredPermissionFunc derives the permission needed to access a resource.
+type RequiredPermissionFunc[R any] func(ctx context.Context, resource R) (Permission, error)
+
+// NewPermissionApprover creates an Approver that requires the token's capabilities,
+// interpreted as a CapabilitySet, to imply the permission derived from each resource.
+// The set is obtained with GetCapabilitySet, so it is rebuilt on every call unless the
+// token carries one, e.g. via NewCapabilitySetValidator.
+//
+// Any error from the RequiredPermissionFunc is returned as is.  If the token's capabilities
+// do not imply the required permission, a *PermissionDeniedError is returned.
+func NewPermissionApprover[R any](required RequiredPermissionFunc[R]) Approver[R] {
+	return ApproverFunc[R](func(ctx context.Context, resource R, token Token) error {
+		p, err := required(ctx, resource)
+		if err != nil {
+			return err
+		}
+
+		if cs, _ := GetCapabilitySet(token); !cs.Implies(p) {
+			return &PermissionDeniedError{
+				Required: p,
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type capabilitiesToken struct {
+	StubToken
+	capabilities []string
+}
+
+func (ct capabilitiesToken) Capabilities() []string { return ct.capabilities }
+
+type CapabilitySetTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CapabilitySetTestSuite) newCapabilitySet(capabilities ...string) CapabilitySet {
+	cs, err := ParseCapabilitySet(capabilities...)
+	suite.Require().NoError(err)
+	return cs
+}
+
+func (suite *CapabilitySetTestSuite) TestZero() {
+	var cs CapabilitySet
+	suite.Zero(cs.Len())
+	suite.Empty(cs.Permissions())
+	suite.Equal("[]", cs.String())
+	suite.False(cs.Implies(MustParsePermission("*")))
+	suite.True(cs.ImpliesAll(CapabilitySet{}))
+}
+
+func (suite *CapabilitySetTestSuite) TestNewCapabilitySet() {
+	cs := NewCapabilitySet(
+		MustParsePermission("doc:write,read"),
+		Permission{},
+		MustParsePermission("doc:read,write"),
+		MustParsePermission("mail:send"),
+	)
+
+	suite.Equal(2, cs.Len())
+	suite.Equal([]string{"doc:read,write", "mail:send"}, cs.Strings())
+	suite.Equal("[doc:read,write mail:send]", cs.String())
+	suite.Equal(
+		[]Permission{MustParsePermission("doc:read,write"), MustParsePermission("mail:send")},
+		cs.Permissions(),
+	)
+}
+
+func (suite *CapabilitySetTestSuite) TestParseCapabilitySetError() {
+	cs, err := ParseCapabilitySet("doc:read", "doc::read")
+	suite.ErrorIs(err, ErrInvalidPermission)
+	suite.Zero(cs.Len())
+}
+
+func (suite *CapabilitySetTestSuite) TestImplies() {
+	cs := suite.newCapabilitySet(
+		"doc:read",
+		"doc:write",
+		"printer:*:lp7200",
+		"admin",
+		"report:*",
+	)
+
+	testCases := []struct {
+		permission string
+		expected   bool
+	}{
+		{permission: "doc:read", expected: true},
+		{permission: "doc:read:42", expected: true},
+		{permission: "doc:read,write", expected: true},
+		{permission: "doc:read,delete"},
+		{permission: "doc:*"},
+		{permission: "doc"},
+		{permission: "printer:print:lp7200", expected: true},
+		{permission: "printer:print,query:lp7200", expected: true},
+		{permission: "printer:print:epson"},
+		{permission: "printer:*:lp7200", expected: true},
+		{permission: "admin:anything:at:all", expected: true},
+		{permission: "admin:*", expected: true},
+		{permission: "report", expected: true},
+		{permission: "report:x:y", expected: true},
+		{permission: "*"},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.permission, func() {
+			suite.Equal(testCase.expected, cs.Implies(MustParsePermission(testCase.permission)))
+		})
+	}
+
+	suite.False(cs.Implies(Permission{}))
+}
+
+func (suite *CapabilitySetTestSuite) TestLarge() {
+	capabilities := make([]string, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		capabilities = append(capabilities, fmt.Sprintf("device:%d:read,write", i))
+	}
+
+	cs := suite.newCapabilitySet(capabilities...)
+	suite.Equal(10000, cs.Len())
+	suite.True(cs.Implies(MustParsePermission("device:9999:write")))
+	suite.False(cs.Implies(MustParsePermission("device:10000:write")))
+	suite.False(cs.Implies(MustParsePermission("device:*:write")))
+}
+
+func (suite *CapabilitySetTestSuite) TestImpliesAll() {
+	cs := suite.newCapabilitySet("doc:*", "mail:send")
+	suite.True(cs.ImpliesAll(suite.newCapabilitySet("doc:read", "mail:send:bob")))
+	suite.False(cs.ImpliesAll(suite.newCapabilitySet("doc:read", "mail:read")))
+}
+
+func (suite *CapabilitySetTestSuite) TestUnion() {
+	a := suite.newCapabilitySet("doc:read", "mail:send")
+	b := suite.newCapabilitySet("mail:send", "doc:write")
+
+	union := a.Union(b)
+	suite.Equal([]string{"doc:read", "mail:send", "doc:write"}, union.Strings())
+	suite.True(union.Implies(MustParsePermission("doc:read,write")))
+	suite.Equal(a.Strings(), a.Union(CapabilitySet{}).Strings())
+}
+
+func (suite *CapabilitySetTestSuite) TestIntersection() {
+	testCases := []struct {
+		a, b     []string
+		expected []string
+	}{
+		{
+			a:        []string{"doc:*"},
+			b:        []string{"doc:read", "mail:send"},
+			expected: []string{"doc:read"},
+		},
+		{
+			a:        []string{"doc:read", "doc:write"},
+			b:        []string{"doc:write", "doc:delete"},
+			expected: []string{"doc:write"},
+		},
+		{
+			a:        []string{"*"},
+			b:        []string{"doc:*"},
+			expected: []string{"doc:*"},
+		},
+		{
+			a: []string{"doc:read"},
+			b: []string{"mail:send"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		suite.Run(fmt.Sprintf("case%d", i), func() {
+			a, b := suite.newCapabilitySet(testCase.a...), suite.newCapabilitySet(testCase.b...)
+			suite.ElementsMatch(testCase.expected, a.Intersection(b).Strings())
+			suite.ElementsMatch(testCase.expected, b.Intersection(a).Strings())
+		})
+	}
+}
+
+func (suite *CapabilitySetTestSuite) TestGetCapabilitySet() {
+	cs, ok := GetCapabilitySet(capabilitiesToken{
+		StubToken:    "joe",
+		capabilities: []string{"doc:read", "not::valid"},
+	})
+
+	suite.True(ok)
+	suite.Equal([]string{"doc:read"}, cs.Strings())
+
+	cs, ok = GetCapabilitySet(StubToken("joe"))
+	suite.False(ok)
+	suite.Zero(cs.Len())
+}
+
+func (suite *CapabilitySetTestSuite) TestWithCapabilitySet() {
+	token := capabilitiesToken{
+		StubToken:    "joe",
+		capabilities: []string{"doc:read", "not::valid"},
+	}
+
+	wrapped := WithCapabilitySet(token)
+	suite.Equal("joe", wrapped.Principal())
+	suite.Equal(wrapped, WithCapabilitySet(wrapped))
+
+	var csa CapabilitySetAccessor
+	suite.Require().True(TokenAs(wrapped, &csa))
+	suite.Equal([]string{"doc:read"}, csa.CapabilitySet().Strings())
+
+	var ca CapabilitiesAccessor
+	suite.Require().True(TokenAs(wrapped, &ca))
+	suite.Equal(token.capabilities, ca.Capabilities())
+
+	cs, ok := GetCapabilitySet(wrapped)
+	suite.True(ok)
+	suite.Same(csa.CapabilitySet().root, cs.root)
+
+	suite.Run("NoCapabilities", func() {
+		suite.Equal(StubToken("joe"), WithCapabilitySet(StubToken("joe")))
+	})
+}
+
+func (suite *CapabilitySetTestSuite) TestCapabilitySetValidator() {
+	token := capabilitiesToken{
+		StubToken:    "joe",
+		capabilities: []string{"doc:read"},
+	}
+
+	next, err := NewCapabilitySetValidator[string]().Validate(context.Background(), "source", token)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(next)
+
+	var csa CapabilitySetAccessor
+	suite.True(TokenAs(next, &csa))
+	suite.True(csa.CapabilitySet().Implies(MustParsePermission("doc:read")))
+}
+
+func (suite *CapabilitySetTestSuite) TestPermissionApprover() {
+	var (
+		requiredErr = errors.New("expected")
+		approver    = NewPermissionApprover(func(_ context.Context, resource string) (Permission, error) {
+			if resource == "error" {
+				return Permission{}, requiredErr
+			}
+
+			return ParsePermission("doc:" + resource)
+		})
+
+		token = capabilitiesToken{
+			StubToken:    "joe",
+			capabilities: []string{"doc:read"},
+		}
+	)
+
+	suite.NoError(approver.Approve(context.Background(), "read", token))
+	suite.ErrorIs(approver.Approve(context.Background(), "error", token), requiredErr)
+
+	err := approver.Approve(context.Background(), "write", token)
+	suite.ErrorIs(err, ErrUnauthorized)
+
+	var pde *PermissionDeniedError
+	suite.Require().ErrorAs(err, &pde)
+	suite.Equal("doc:write", pde.Required.String())
+	suite.Contains(pde.Error(), "doc:write")
+
+	suite.ErrorIs(approver.Approve(context.Background(), "read", StubToken("joe")), ErrUnauthorized)
+
+	suite.Run("Prebuilt", func() {
+		prebuilt := WithCapabilitySet(token)
+		suite.NoError(approver.Approve(context.Background(), "read", prebuilt))
+		suite.ErrorIs(approver.Approve(context.Background(), "write", prebuilt), ErrUnauthorized)
+	})
+}
+
+func TestCapabilitySet(t *testing.T) {
+	suite.Run(t, new(CapabilitySetTestSuite))
+}
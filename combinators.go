@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+const (
+	// CombinatorAllOf is the combinator name reported by AllOf.
+	CombinatorAllOf = "AllOf"
+
+	// CombinatorAnyOf is the combinator name reported by AnyOf.
+	CombinatorAnyOf = "AnyOf"
+
+	// CombinatorNot is the combinator name reported by Not.
+	CombinatorNot = "Not"
+
+	// CombinatorAtLeast is the combinator name reported by AtLeast.
+	CombinatorAtLeast = "AtLeast"
+)
+
+// BranchError reports which branch of an approver combinator denied access.
+// Nested combinators produce nested BranchErrors, so the full path to the
+// denying approver can be recovered by following the Err chain.
+type BranchError struct {
+	// Combinator is the name of the combinator that produced this error, e.g. CombinatorAllOf.
+	Combinator string
+
+	// Index is the zero-based position of the denying branch within its combinator.
+	Index int
+
+	// Err is the error returned by the branch.  For Not, this is ErrUnauthorized.
+	Err error
+}
+
+// Unwrap returns the branch's error.
+func (be *BranchError) Unwrap() error {
+	return be.Err
+}
+
+func (be *BranchError) Error() string {
+	var o strings.Builder
+	o.WriteString(be.Combinator)
+	o.WriteRune('[')
+	o.WriteString(strconv.Itoa(be.Index))
+	o.WriteString("] denied: ")
+	if be.Err != nil {
+		o.WriteString(be.Err.Error())
+	}
+
+	return o.String()
+}
+
+// copyApprovers creates a distinct copy of the given approvers, so that combinators
+// are unaffected by subsequent changes to the caller's slice.
+func copyApprovers[R any](as []Approver[R]) Approvers[R] {
+	return append(make(Approvers[R], 0, len(as)), as...)
+}
+
+type allOf[R any] struct {
+	as Approvers[R]
+}
+
+func (ao allOf[R]) Approve(ctx context.Context, resource R, token Token) error {
+	for i, a := range ao.as {
+		if err := a.Approve(ctx, resource, token); err != nil {
+			return &BranchError{
+				Combinator: CombinatorAllOf,
+				Index:      i,
+				Err:        err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// AllOf returns an Approver that is a logical AND of the given approvers.  Execution
+// halts at the first denial, which is reported as a *BranchError.  An empty AllOf
+// approves all access.
+func AllOf[R any](as ...Approver[R]) Approver[R] {
+	return allOf[R]{
+		as: copyApprovers(as),
+	}
+}
+
+type anyOf[R any] struct {
+	as Approvers[R]
+}
+
+func (ao anyOf[R]) Approve(ctx context.Context, resource R, token Token) (err error) {
+	for i, a := range ao.as {
+		branchErr := a.Approve(ctx, resource, token)
+		if branchErr == nil {
+			return nil
+		}
+
+		err = multierr.Append(err, &BranchError{
+			Combinator: CombinatorAnyOf,
+			Index:      i,
+			Err:        branchErr,
+		})
+	}
+
+	return
+}
+
+// AnyOf returns an Approver that is a logical OR of the given approvers.  Execution
+// halts at the first approval.  If every branch denies access, the returned error
+// is an aggregate of one *BranchError per branch.  An empty AnyOf approves all access,
+// which is consistent with Approvers.Any.
+func AnyOf[R any](as ...Approver[R]) Approver[R] {
+	return anyOf[R]{
+		as: copyApprovers(as),
+	}
+}
+
+type not[R any] struct {
+	a Approver[R]
+}
+
+func (n not[R]) Approve(ctx context.Context, resource R, token Token) error {
+	err := n.a.Approve(ctx, resource, token)
+	switch {
+	case err == nil:
+		return &BranchError{
+			Combinator: CombinatorNot,
+			Index:      0,
+			Err:        ErrUnauthorized,
+		}
+
+	case onlyUnauthorized(err):
+		return nil
+
+	default:
+		return err
+	}
+}
+
+// onlyUnauthorized tests if every error in err's tree is ErrUnauthorized.  An aggregate
+// error, such as one from AnyOf, satisfies this function only when every aggregated
+// error does.  This keeps Not from inverting a denial that is mixed with some other
+// failure, which would fail open.
+func onlyUnauthorized(err error) bool {
+	switch u := err.(type) {
+	case nil:
+		return false
+
+	case interface{ Unwrap() []error }:
+		errs := u.Unwrap()
+		for _, e := range errs {
+			if !onlyUnauthorized(e) {
+				return false
+			}
+		}
+
+		return len(errs) > 0
+
+	case interface{ Unwrap() error }:
+		return onlyUnauthorized(u.Unwrap())
+
+	default:
+		return errors.Is(err, ErrUnauthorized)
+	}
+}
+
+// Not returns an Approver that inverts the given approver.  Access is denied with
+// ErrUnauthorized if the given approver allows it, and approved if the given approver
+// denies it with an error that has ErrUnauthorized in its chain.  For an aggregate
+// error, such as one from AnyOf or AtLeast, every aggregated error must be ErrUnauthorized.
+//
+// Any other error, e.g. a failure to contact an external system, is returned as is.
+// This ensures that a negated approver fails closed.
+func Not[R any](a Approver[R]) Approver[R] {
+	return not[R]{
+		a: a,
+	}
+}
+
+type atLeast[R any] struct {
+	k  int
+	as Approvers[R]
+}
+
+func (al atLeast[R]) Approve(ctx context.Context, resource R, token Token) (err error) {
+	approved := 0
+	for i, a := range al.as {
+		if approved >= al.k {
+			break
+		}
+
+		if remaining := len(al.as) - i; approved+remaining < al.k {
+			// the threshold cannot be reached
+			break
+		}
+
+		branchErr := a.Approve(ctx, resource, token)
+		if branchErr == nil {
+			approved++
+			continue
+		}
+
+		err = multierr.Append(err, &BranchError{
+			Combinator: CombinatorAtLeast,
+			Index:      i,
+			Err:        branchErr,
+		})
+	}
+
+	if approved >= al.k {
+		return nil
+	} else if err == nil {
+		err = ErrUnauthorized
+	}
+
+	return
+}
+
+// AtLeast returns a k-of-n threshold Approver:  at least k of the given approvers must
+// allow access.  Approvers are executed in order, and execution halts as soon as either
+// the threshold has been reached or it can no longer be reached.
+//
+// If the threshold is not met, the returned error is an aggregate of one *BranchError
+// for each denying branch that was executed.  If k is nonpositive, all access is approved.
+// If k exceeds the number of approvers, all access is denied.
+func AtLeast[R any](k int, as ...Approver[R]) Approver[R] {
+	return atLeast[R]{
+		k:  k,
+		as: copyApprovers(as),
+	}
+}
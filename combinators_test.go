@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/multierr"
+)
+
+type CombinatorsTestSuite struct {
+	TestSuite
+}
+
+// approve returns an approver that always grants access and records its invocation.
+func (suite *CombinatorsTestSuite) approve(called *int) Approver[string] {
+	return ApproverFunc[string](func(context.Context, string, Token) error {
+		*called++
+		return nil
+	})
+}
+
+// deny returns an approver that always denies access with the given error.
+func (suite *CombinatorsTestSuite) deny(called *int, err error) Approver[string] {
+	return ApproverFunc[string](func(context.Context, string, Token) error {
+		*called++
+		return err
+	})
+}
+
+func (suite *CombinatorsTestSuite) approveWith(a Approver[string]) error {
+	return a.Approve(suite.testContext(), "resource", suite.testToken())
+}
+
+// assertBranch asserts that err is a BranchError for the given combinator and index.
+func (suite *CombinatorsTestSuite) assertBranch(err error, combinator string, index int) *BranchError {
+	var be *BranchError
+	suite.Require().ErrorAs(err, &be)
+	suite.Equal(combinator, be.Combinator)
+	suite.Equal(index, be.Index)
+	suite.NotEmpty(be.Error())
+	return be
+}
+
+func (suite *CombinatorsTestSuite) TestAllOf() {
+	suite.Run("Empty", func() {
+		suite.NoError(suite.approveWith(AllOf[string]()))
+	})
+
+	suite.Run("AllApprove", func() {
+		var called int
+		suite.NoError(suite.approveWith(
+			AllOf(suite.approve(&called), suite.approve(&called)),
+		))
+
+		suite.Equal(2, called)
+	})
+
+	suite.Run("Deny", func() {
+		var called int
+		err := suite.approveWith(
+			AllOf(
+				suite.approve(&called),
+				suite.deny(&called, ErrUnauthorized),
+				suite.approve(&called),
+			),
+		)
+
+		suite.ErrorIs(err, ErrUnauthorized)
+		suite.assertBranch(err, CombinatorAllOf, 1)
+		suite.Equal(2, called)
+	})
+
+	suite.Run("Distinct", func() {
+		var called int
+		as := []Approver[string]{suite.approve(&called)}
+		a := AllOf(as...)
+		as[0] = suite.deny(&called, ErrUnauthorized)
+		suite.NoError(suite.approveWith(a))
+	})
+}
+
+func (suite *CombinatorsTestSuite) TestAnyOf() {
+	suite.Run("Empty", func() {
+		suite.NoError(suite.approveWith(AnyOf[string]()))
+	})
+
+	suite.Run("FirstApproves", func() {
+		var called int
+		suite.NoError(suite.approveWith(
+			AnyOf(suite.approve(&called), suite.approve(&called)),
+		))
+
+		suite.Equal(1, called)
+	})
+
+	suite.Run("LastApproves", func() {
+		var called int
+		suite.NoError(suite.approveWith(
+			AnyOf(suite.deny(&called, ErrUnauthorized), suite.approve(&called)),
+		))
+
+		suite.Equal(2, called)
+	})
+
+	suite.Run("AllDeny", func() {
+		var (
+			called      int
+			expectedErr = errors.New("expected")
+		)
+
+		err := suite.approveWith(
+			AnyOf(suite.deny(&called, ErrUnauthorized), suite.deny(&called, expectedErr)),
+		)
+
+		suite.ErrorIs(err, ErrUnauthorized)
+		suite.ErrorIs(err, expectedErr)
+
+		errs := multierr.Errors(err)
+		suite.Require().Len(errs, 2)
+		suite.assertBranch(errs[0], CombinatorAnyOf, 0)
+		suite.assertBranch(errs[1], CombinatorAnyOf, 1)
+	})
+}
+
+func (suite *CombinatorsTestSuite) TestNot() {
+	suite.Run("InnerApproves", func() {
+		var called int
+		err := suite.approveWith(Not(suite.approve(&called)))
+		suite.ErrorIs(err, ErrUnauthorized)
+		suite.assertBranch(err, CombinatorNot, 0)
+	})
+
+	suite.Run("InnerDenies", func() {
+		var called int
+		suite.NoError(suite.approveWith(Not(suite.deny(&called, ErrUnauthorized))))
+	})
+
+	suite.Run("InnerFails", func() {
+		var (
+			called      int
+			expectedErr = errors.New("expected")
+		)
+
+		err := suite.approveWith(Not(suite.deny(&called, expectedErr)))
+		suite.ErrorIs(err, expectedErr)
+	})
+
+	suite.Run("InnerAnyOfDenies", func() {
+		var called int
+		suite.NoError(suite.approveWith(Not(AnyOf(
+			suite.deny(&called, ErrUnauthorized),
+			suite.deny(&called, ErrUnauthorized),
+		))))
+
+		suite.Equal(2, called)
+	})
+
+	suite.Run("InnerAnyOfFails", func() {
+		var (
+			called      int
+			expectedErr = errors.New("expected")
+		)
+
+		err := suite.approveWith(Not(AnyOf(
+			suite.deny(&called, ErrUnauthorized),
+			suite.deny(&called, expectedErr),
+		)))
+
+		suite.ErrorIs(err, expectedErr)
+		suite.Equal(2, called)
+	})
+}
+
+func (suite *CombinatorsTestSuite) TestAtLeast() {
+	suite.Run("Nonpositive", func() {
+		var called int
+		suite.NoError(suite.approveWith(AtLeast(0, suite.deny(&called, ErrUnauthorized))))
+		suite.Zero(called)
+	})
+
+	suite.Run("ThresholdTooHigh", func() {
+		var called int
+		err := suite.approveWith(AtLeast(3, suite.approve(&called), suite.approve(&called)))
+		suite.ErrorIs(err, ErrUnauthorized)
+		suite.Zero(called)
+	})
+
+	suite.Run("Met", func() {
+		var called int
+		suite.NoError(suite.approveWith(
+			AtLeast(2,
+				suite.approve(&called),
+				suite.deny(&called, ErrUnauthorized),
+				suite.approve(&called),
+				suite.approve(&called),
+			),
+		))
+
+		suite.Equal(3, called)
+	})
+
+	suite.Run("NotMet", func() {
+		var called int
+		err := suite.approveWith(
+			AtLeast(3,
+				suite.approve(&called),
+				suite.deny(&called, ErrUnauthorized),
+				suite.deny(&called, ErrUnauthorized),
+				suite.approve(&called),
+			),
+		)
+
+		suite.ErrorIs(err, ErrUnauthorized)
+		suite.Equal(3, called) // the last branch cannot change the outcome
+
+		errs := multierr.Errors(err)
+		suite.Require().Len(errs, 2)
+		suite.assertBranch(errs[0], CombinatorAtLeast, 1)
+		suite.assertBranch(errs[1], CombinatorAtLeast, 2)
+	})
+}
+
+func (suite *CombinatorsTestSuite) TestNested() {
+	// admin OR (owner AND NOT suspended)
+	var (
+		called    int
+		admin     = suite.deny(&called, ErrUnauthorized)
+		owner     = suite.approve(&called)
+		suspended = suite.approve(&called)
+
+		a = AnyOf(
+			admin,
+			AllOf(owner, Not(suspended)),
+		)
+	)
+
+	err := suite.approveWith(a)
+	suite.ErrorIs(err, ErrUnauthorized)
+
+	errs := multierr.Errors(err)
+	suite.Require().Len(errs, 2)
+	suite.assertBranch(errs[0], CombinatorAnyOf, 0)
+
+	be := suite.assertBranch(errs[1], CombinatorAnyOf, 1)
+	be = suite.assertBranch(be.Err, CombinatorAllOf, 1)
+	suite.assertBranch(be.Err, CombinatorNot, 0)
+}
+
+func TestCombinators(t *testing.T) {
+	suite.Run(t, new(CombinatorsTestSuite))
+}
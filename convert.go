@@ -0,0 +1,514 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttributeTag is the struct tag consulted by DecodeAttributes.  If a field has
+// no such tag, its json tag is used.  Otherwise, the field name is the key.
+const AttributeTag = "bascule"
+
+var (
+	// ErrAttributeConversion indicates that an attribute value could not be
+	// converted to the requested type.  Every AttributeConversionError is
+	// considered equal to this error by errors.Is.
+	ErrAttributeConversion = errors.New("cannot convert attribute")
+
+	// ErrAttributeOverflow indicates that a numeric attribute value could not be
+	// represented by the requested type without loss.
+	ErrAttributeOverflow = errors.New("attribute value out of range")
+
+	// ErrInvalidDecodeTarget indicates that DecodeAttributes was not passed a
+	// non-nil pointer.
+	ErrInvalidDecodeTarget = errors.New("decode target must be a non-nil pointer")
+
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+	numberType   = reflect.TypeFor[json.Number]()
+)
+
+// AttributeConversionError describes a failure to convert an attribute value.
+type AttributeConversionError struct {
+	// Path locates the value that failed within the attribute being converted,
+	// e.g. "roles[2]" or "address.city".  This field is empty when the attribute
+	// itself could not be converted.
+	Path string
+
+	// Value is the value that could not be converted.
+	Value any
+
+	// Type is the type to which conversion was attempted.
+	Type reflect.Type
+
+	// Err is the underlying cause, if any, such as ErrAttributeOverflow or a
+	// time parsing error.
+	Err error
+}
+
+// Unwrap returns the underlying cause of this error.
+func (ace *AttributeConversionError) Unwrap() error {
+	return ace.Err
+}
+
+// Is returns true if target is ErrAttributeConversion.
+func (ace *AttributeConversionError) Is(target error) bool {
+	return target == ErrAttributeConversion
+}
+
+func (ace *AttributeConversionError) Error() string {
+	var o strings.Builder
+	o.WriteString("cannot convert attribute")
+	if len(ace.Path) > 0 {
+		o.WriteString(" at ")
+		o.WriteString(ace.Path)
+	}
+
+	o.WriteString(" of type ")
+	if ace.Value == nil {
+		o.WriteString("nil")
+	} else {
+		o.WriteString(reflect.TypeOf(ace.Value).String())
+	}
+
+	o.WriteString(" to ")
+	o.WriteString(ace.Type.String())
+	if ace.Err != nil {
+		o.WriteString(": ")
+		o.WriteString(ace.Err.Error())
+	}
+
+	return o.String()
+}
+
+// ConvertAttribute converts a raw attribute value into a T.  This function is used
+// by GetAttribute and DecodeAttributes when an attribute is not already of the
+// requested type.  The following conversions are supported:
+//
+// (1) Any value assignable to T is returned as is.
+//
+// (2) Numeric values, including json.Number, convert to any numeric type as long as
+// the value is within the range of that type.  Integer types additionally require an
+// integral value.  For example, float64(3) converts to int, but float64(3.5) and 300
+// do not convert to int8.
+//
+// (3) Strings are parsed as RFC 3339 times when T is time.Time, and as Go durations
+// when T is time.Duration.  Numbers convert to time.Time as seconds since the Unix
+// epoch, as with JWT NumericDate claims, and to time.Duration as seconds.
+//
+// (4) Slices and arrays convert to slices of T's element type, converting each element.
+//
+// (5) Maps with string keys convert to maps with string keys, converting each value.
+//
+// (6) Maps with string keys, as well as AttributesAccessor values, convert to structs.
+// See DecodeAttributes for how fields are matched to keys.
+//
+// (7) When T is a pointer, the value is converted to the pointed-to type.
+//
+// (8) A nil value, such as a JSON null, converts to the zero value of T when T is a
+// pointer, interface, slice, map, channel, or function.  Otherwise, it never converts.
+//
+// Named types are supported wherever their underlying kind is.  Any failure is reported
+// as an *AttributeConversionError.
+func ConvertAttribute[T any](raw any) (v T, err error) {
+	var rv reflect.Value
+	if rv, err = convertValue("", raw, reflect.TypeFor[T]()); err == nil {
+		// a nil interface fails a type assertion, even to any, so v is
+		// left as the zero value in that case
+		v, _ = rv.Interface().(T)
+	}
+
+	return
+}
+
+// DecodeAttributes populates the struct pointed to by dst from a set of attributes.
+// If keys are supplied, the nested attribute they identify is decoded instead, just
+// as with GetAttribute.  dst may also point to any other type that ConvertAttribute
+// supports when keys are supplied.
+//
+// Struct fields are matched to attribute keys using the AttributeTag struct tag, then
+// the json struct tag, then the field name.  A tag name of "-" skips the field.
+// Unexported fields are skipped, as are fields whose keys are absent.  Nested structs
+// are decoded from nested map[string]any or AttributesAccessor values.  A nil value,
+// such as a JSON null, sets a pointer, interface, slice, or map field to nil.
+func DecodeAttributes(a AttributesAccessor, dst any, keys ...string) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return ErrInvalidDecodeTarget
+	}
+
+	var source any = a
+	if len(keys) > 0 {
+		raw, ok := GetAttribute[any](a, keys...)
+		if !ok {
+			return &AttributeConversionError{
+				Path: strings.Join(keys, "."),
+				Type: ptr.Elem().Type(),
+			}
+		}
+
+		source = raw
+	}
+
+	rv, err := convertValue("", source, ptr.Elem().Type())
+	if err == nil {
+		ptr.Elem().Set(rv)
+	}
+
+	return err
+}
+
+// conversionError is a convenience for creating an *AttributeConversionError.
+func conversionError(path string, raw any, t reflect.Type, cause error) error {
+	return &AttributeConversionError{
+		Path:  path,
+		Value: raw,
+		Type:  t,
+		Err:   cause,
+	}
+}
+
+// joinPath appends a field name to a path.
+func joinPath(path, name string) string {
+	if len(path) == 0 {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// convertValue is the reflection-based workhorse for attribute conversion.
+// The returned value, if no error, is always of type t.
+func convertValue(path string, raw any, t reflect.Type) (reflect.Value, error) {
+	if raw == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+			return reflect.Zero(t), nil
+
+		default:
+			return reflect.Value{}, conversionError(path, raw, t, nil)
+		}
+	}
+
+	rv := reflect.ValueOf(raw)
+	if rv.Type().AssignableTo(t) {
+		return rv, nil
+	}
+
+	switch {
+	case t == timeType:
+		return convertTime(path, raw, rv, t)
+
+	case t == durationType:
+		return convertDuration(path, raw, rv, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := convertValue(path, raw, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		p := reflect.New(t.Elem())
+		p.Elem().Set(elem)
+		return p, nil
+
+	case reflect.Bool:
+		if rv.Kind() == reflect.Bool {
+			return rv.Convert(t), nil
+		}
+
+	case reflect.String:
+		if rv.Kind() == reflect.String {
+			return rv.Convert(t), nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return convertNumber(path, raw, rv, t)
+
+	case reflect.Slice:
+		return convertSlice(path, raw, rv, t)
+
+	case reflect.Map:
+		return convertMap(path, raw, rv, t)
+
+	case reflect.Struct:
+		return convertStruct(path, raw, rv, t)
+	}
+
+	return reflect.Value{}, conversionError(path, raw, t, nil)
+}
+
+// numericValue extracts a numeric value from any integer, unsigned, float, or
+// json.Number value.  Exactly one of the returned int64, uint64, or float64 values
+// is meaningful, as indicated by the returned kind.
+func numericValue(rv reflect.Value) (i int64, u uint64, f float64, kind reflect.Kind, err error) {
+	if rv.Type() == numberType {
+		n := rv.Interface().(json.Number)
+		if i, err = n.Int64(); err == nil {
+			kind = reflect.Int64
+		} else if f, err = n.Float64(); err == nil {
+			kind = reflect.Float64
+		}
+
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, kind = rv.Int(), reflect.Int64
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, kind = rv.Uint(), reflect.Uint64
+
+	case reflect.Float32, reflect.Float64:
+		f, kind = rv.Float(), reflect.Float64
+
+	default:
+		kind = reflect.Invalid
+	}
+
+	return
+}
+
+// convertNumber handles conversions to the numeric kinds, with overflow checks.
+func convertNumber(path string, raw any, rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	i, u, f, kind, err := numericValue(rv)
+	if err != nil || kind == reflect.Invalid {
+		return reflect.Value{}, conversionError(path, raw, t, err)
+	}
+
+	out := reflect.New(t).Elem()
+	overflow := false
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch kind {
+		case reflect.Uint64:
+			overflow = u > math.MaxInt64
+			i = int64(u) //nolint:gosec // checked above
+
+		case reflect.Float64:
+			overflow = f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64
+			i = int64(f)
+		}
+
+		overflow = overflow || out.OverflowInt(i)
+		if !overflow {
+			out.SetInt(i)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch kind {
+		case reflect.Int64:
+			overflow = i < 0
+			u = uint64(i) //nolint:gosec // checked above
+
+		case reflect.Float64:
+			overflow = f != math.Trunc(f) || f < 0 || f >= math.MaxUint64
+			u = uint64(f)
+		}
+
+		overflow = overflow || out.OverflowUint(u)
+		if !overflow {
+			out.SetUint(u)
+		}
+
+	default: // floats
+		switch kind {
+		case reflect.Int64:
+			f = float64(i)
+			overflow = int64(f) != i
+
+		case reflect.Uint64:
+			f = float64(u)
+			overflow = uint64(f) != u
+		}
+
+		overflow = overflow || out.OverflowFloat(f)
+		if !overflow {
+			out.SetFloat(f)
+		}
+	}
+
+	if overflow {
+		return reflect.Value{}, conversionError(path, raw, t, ErrAttributeOverflow)
+	}
+
+	return out, nil
+}
+
+// convertTime produces a time.Time from either an RFC 3339 string or a number
+// of seconds since the Unix epoch.
+func convertTime(path string, raw any, rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if rv.Kind() == reflect.String && rv.Type() != numberType {
+		tm, err := time.Parse(time.RFC3339Nano, rv.String())
+		if err != nil {
+			return reflect.Value{}, conversionError(path, raw, t, err)
+		}
+
+		return reflect.ValueOf(tm), nil
+	}
+
+	seconds, err := convertNumber(path, raw, rv, reflect.TypeFor[float64]())
+	if err != nil {
+		return reflect.Value{}, conversionError(path, raw, t, errors.Unwrap(err))
+	}
+
+	whole, frac := math.Modf(seconds.Float())
+	return reflect.ValueOf(
+		time.Unix(int64(whole), int64(frac*float64(time.Second))).UTC(),
+	), nil
+}
+
+// convertDuration produces a time.Duration from either a Go duration string or a
+// number of seconds.
+func convertDuration(path string, raw any, rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if rv.Kind() == reflect.String && rv.Type() != numberType {
+		d, err := time.ParseDuration(rv.String())
+		if err != nil {
+			return reflect.Value{}, conversionError(path, raw, t, err)
+		}
+
+		return reflect.ValueOf(d), nil
+	}
+
+	seconds, err := convertNumber(path, raw, rv, reflect.TypeFor[float64]())
+	if err != nil {
+		return reflect.Value{}, conversionError(path, raw, t, errors.Unwrap(err))
+	}
+
+	d := seconds.Float() * float64(time.Second)
+	if d >= math.MaxInt64 || d < math.MinInt64 {
+		return reflect.Value{}, conversionError(path, raw, t, ErrAttributeOverflow)
+	}
+
+	return reflect.ValueOf(time.Duration(d)), nil
+}
+
+// convertSlice converts each element of a slice or array.
+func convertSlice(path string, raw any, rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return reflect.Value{}, conversionError(path, raw, t, nil)
+	}
+
+	out := reflect.MakeSlice(t, rv.Len(), rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elem, err := convertValue(
+			path+"["+strconv.Itoa(i)+"]",
+			rv.Index(i).Interface(),
+			t.Elem(),
+		)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out.Index(i).Set(elem)
+	}
+
+	return out, nil
+}
+
+// convertMap converts each value of a map with string keys.
+func convertMap(path string, raw any, rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String || t.Key().Kind() != reflect.String {
+		return reflect.Value{}, conversionError(path, raw, t, nil)
+	}
+
+	out := reflect.MakeMapWithSize(t, rv.Len())
+	for iter := rv.MapRange(); iter.Next(); {
+		key := iter.Key().String()
+		value, err := convertValue(joinPath(path, key), iter.Value().Interface(), t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), value)
+	}
+
+	return out, nil
+}
+
+// fieldKey determines the attribute key for a struct field.  This function
+// returns false if the field should be skipped.
+func fieldKey(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	for _, tagName := range []string{AttributeTag, "json"} {
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			switch name {
+			case "-":
+				return "", false
+
+			case "":
+				// e.g. `json:",omitempty"`, so keep looking
+
+			default:
+				return name, true
+			}
+		}
+	}
+
+	return field.Name, true
+}
+
+// convertStruct populates a struct from a map with string keys or an AttributesAccessor.
+func convertStruct(path string, raw any, rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	var get func(string) (any, bool)
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		get = func(key string) (any, bool) {
+			v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+			if !v.IsValid() {
+				return nil, false
+			}
+
+			return v.Interface(), true
+		}
+
+	default:
+		aa, ok := raw.(AttributesAccessor)
+		if !ok {
+			return reflect.Value{}, conversionError(path, raw, t, nil)
+		}
+
+		get = aa.Get
+	}
+
+	out := reflect.New(t).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+
+		value, exists := get(key)
+		if !exists {
+			continue
+		}
+
+		converted, err := convertValue(joinPath(path, key), value, field.Type)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out.Field(i).Set(converted)
+	}
+
+	return out, nil
+}
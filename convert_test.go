@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testRole string
+
+type testAddress struct {
+	City string `json:"city"`
+	Zip  int    `bascule:"zip"`
+}
+
+type testProfile struct {
+	Name     string        `bascule:"name"`
+	Age      uint8         `json:"age,omitempty"`
+	Score    float32       `json:"score"`
+	Roles    []testRole    `bascule:"roles"`
+	Joined   time.Time     `bascule:"joined"`
+	Timeout  time.Duration `bascule:"timeout"`
+	Address  *testAddress  `bascule:"address"`
+	Labels   map[string]int
+	Skipped  string `bascule:"-"`
+	Fallback string `json:",omitempty"`
+	Missing  string `bascule:"missing"`
+
+	unexported string //nolint:unused
+}
+
+type ConvertTestSuite struct {
+	suite.Suite
+}
+
+// assertConverted asserts that raw converts to the expected value of type T.
+func assertConverted[T any](suite *ConvertTestSuite, raw any, expected T) {
+	actual, err := ConvertAttribute[T](raw)
+	suite.Require().NoError(err)
+	suite.Equal(expected, actual)
+}
+
+// assertNotConverted asserts that raw does not convert to T.
+func assertNotConverted[T any](suite *ConvertTestSuite, raw any) *AttributeConversionError {
+	actual, err := ConvertAttribute[T](raw)
+	suite.Zero(actual)
+	suite.ErrorIs(err, ErrAttributeConversion)
+
+	var ace *AttributeConversionError
+	suite.Require().ErrorAs(err, &ace)
+	suite.NotEmpty(ace.Error())
+	return ace
+}
+
+func (suite *ConvertTestSuite) TestAssignable() {
+	assertConverted(suite, "value", "value")
+	assertConverted[any](suite, 123, 123)
+	assertConverted[fmt.Stringer](suite, time.Second, fmt.Stringer(time.Second))
+	assertNotConverted[string](suite, nil)
+	assertNotConverted[int](suite, nil)
+	assertNotConverted[testAddress](suite, nil)
+}
+
+func (suite *ConvertTestSuite) TestNil() {
+	assertConverted[any](suite, nil, nil)
+	assertConverted[fmt.Stringer](suite, nil, nil)
+	assertConverted[*int](suite, nil, nil)
+	assertConverted[[]string](suite, nil, nil)
+	assertConverted[map[string]any](suite, nil, nil)
+	a := "a"
+	assertConverted(suite, []any{"a", nil}, []*string{&a, nil})
+	assertConverted(suite, map[string]any{"a": nil}, map[string][]int{"a": nil})
+}
+
+func (suite *ConvertTestSuite) TestNumeric() {
+	suite.Run("Valid", func() {
+		assertConverted(suite, float64(3), 3)
+		assertConverted(suite, float64(-3), int8(-3))
+		assertConverted(suite, float64(255), uint8(255))
+		assertConverted(suite, 3, float64(3))
+		assertConverted(suite, uint(3), int64(3))
+		assertConverted(suite, int64(3), uint16(3))
+		assertConverted(suite, uint64(math.MaxUint64), uint64(math.MaxUint64))
+		assertConverted(suite, float64(1.5), float32(1.5))
+		assertConverted(suite, json.Number("42"), 42)
+		assertConverted(suite, json.Number("4.5"), 4.5)
+		assertConverted(suite, json.Number("42"), "42")
+	})
+
+	suite.Run("Overflow", func() {
+		suite.ErrorIs(assertNotConverted[int8](suite, 300), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[int](suite, 3.5), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[uint](suite, -1), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[uint](suite, float64(-1)), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[int64](suite, uint64(math.MaxUint64)), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[int64](suite, math.Pow(2, 63)), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[float32](suite, math.MaxFloat64), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[float64](suite, int64(math.MaxInt64)), ErrAttributeOverflow)
+		suite.ErrorIs(assertNotConverted[uint8](suite, json.Number("256")), ErrAttributeOverflow)
+	})
+
+	suite.Run("Invalid", func() {
+		assertNotConverted[int](suite, "3")
+		assertNotConverted[int](suite, true)
+		assertNotConverted[int](suite, json.Number("not a number"))
+		assertNotConverted[bool](suite, 1)
+		assertNotConverted[string](suite, 1)
+	})
+}
+
+func (suite *ConvertTestSuite) TestNamedTypes() {
+	assertConverted(suite, "admin", testRole("admin"))
+	assertConverted(suite, testRole("admin"), "admin")
+
+	type flag bool
+	assertConverted(suite, true, flag(true))
+}
+
+func (suite *ConvertTestSuite) TestTime() {
+	expected := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+	assertConverted(suite, "2024-03-01T12:30:00Z", expected)
+	assertConverted(suite, float64(expected.Unix()), expected)
+	assertConverted(suite, expected.Unix(), expected)
+	assertConverted(suite, json.Number("1709296200"), expected)
+	assertConverted(suite, 1709296200.5, expected.Add(500*time.Millisecond))
+	assertConverted(suite, expected, expected)
+
+	ace := assertNotConverted[time.Time](suite, "yesterday")
+	suite.Error(ace.Err)
+	assertNotConverted[time.Time](suite, true)
+}
+
+func (suite *ConvertTestSuite) TestDuration() {
+	assertConverted(suite, "1m30s", 90*time.Second)
+	assertConverted(suite, 90, 90*time.Second)
+	assertConverted(suite, 0.5, 500*time.Millisecond)
+	assertConverted(suite, time.Minute, time.Minute)
+
+	assertNotConverted[time.Duration](suite, "soon")
+	suite.ErrorIs(assertNotConverted[time.Duration](suite, math.MaxFloat64), ErrAttributeOverflow)
+}
+
+func (suite *ConvertTestSuite) TestSlice() {
+	assertConverted(suite, []any{"a", "b"}, []string{"a", "b"})
+	assertConverted(suite, []any{1.0, 2.0}, []int{1, 2})
+	assertConverted(suite, [2]any{"a", "b"}, []testRole{"a", "b"})
+	assertConverted(suite, []any{}, []string{})
+	assertConverted(suite, []any{[]any{1.0}}, [][]int{{1}})
+
+	ace := assertNotConverted[[]int](suite, []any{1.0, "two"})
+	suite.Equal("[1]", ace.Path)
+	suite.Equal("two", ace.Value)
+
+	assertNotConverted[[]string](suite, "a")
+}
+
+func (suite *ConvertTestSuite) TestMap() {
+	assertConverted(suite, map[string]any{"a": 1.0}, map[string]int{"a": 1})
+	assertConverted(suite, map[testRole]any{"a": "x"}, map[string]string{"a": "x"})
+
+	ace := assertNotConverted[map[string]int](suite, map[string]any{"a": "x"})
+	suite.Equal("a", ace.Path)
+
+	assertNotConverted[map[string]int](suite, map[int]any{1: 1})
+	assertNotConverted[map[int]int](suite, map[string]any{"1": 1})
+	assertNotConverted[map[string]int](suite, []any{})
+}
+
+func (suite *ConvertTestSuite) TestPointer() {
+	p, err := ConvertAttribute[*int](3.0)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(p)
+	suite.Equal(3, *p)
+
+	assertNotConverted[*int](suite, "x")
+}
+
+func (suite *ConvertTestSuite) testRaw() map[string]any {
+	return map[string]any{
+		"name":     "joe",
+		"age":      float64(42),
+		"score":    json.Number("9.5"),
+		"roles":    []any{"admin", "user"},
+		"joined":   "2024-03-01T12:30:00Z",
+		"timeout":  "30s",
+		"address":  map[string]any{"city": "Philadelphia", "zip": float64(19103)},
+		"Labels":   map[string]any{"a": float64(1)},
+		"Skipped":  "should not be set",
+		"Fallback": "by field name",
+	}
+}
+
+func (suite *ConvertTestSuite) expectedProfile() testProfile {
+	return testProfile{
+		Name:     "joe",
+		Age:      42,
+		Score:    9.5,
+		Roles:    []testRole{"admin", "user"},
+		Joined:   time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC),
+		Timeout:  30 * time.Second,
+		Address:  &testAddress{City: "Philadelphia", Zip: 19103},
+		Labels:   map[string]int{"a": 1},
+		Fallback: "by field name",
+	}
+}
+
+func (suite *ConvertTestSuite) TestStruct() {
+	assertConverted(suite, suite.testRaw(), suite.expectedProfile())
+	assertConverted(suite, testAttributes(suite.testRaw()), suite.expectedProfile())
+
+	raw := suite.testRaw()
+	raw["address"] = map[string]any{"zip": "not a zip"}
+	ace := assertNotConverted[testProfile](suite, raw)
+	suite.Equal("address.zip", ace.Path)
+
+	assertNotConverted[testProfile](suite, "not a struct")
+}
+
+func (suite *ConvertTestSuite) TestDecodeAttributes() {
+	suite.Run("Root", func() {
+		var actual testProfile
+		suite.Require().NoError(DecodeAttributes(testAttributes(suite.testRaw()), &actual))
+		suite.Equal(suite.expectedProfile(), actual)
+	})
+
+	suite.Run("Nested", func() {
+		a := testAttributes{
+			"profile": suite.testRaw(),
+		}
+
+		var actual testAddress
+		suite.Require().NoError(DecodeAttributes(a, &actual, "profile", "address"))
+		suite.Equal(testAddress{City: "Philadelphia", Zip: 19103}, actual)
+
+		var roles []string
+		suite.Require().NoError(DecodeAttributes(a, &roles, "profile", "roles"))
+		suite.Equal([]string{"admin", "user"}, roles)
+	})
+
+	suite.Run("Missing", func() {
+		var actual testAddress
+		err := DecodeAttributes(testAttributes{}, &actual, "missing")
+		suite.ErrorIs(err, ErrAttributeConversion)
+	})
+
+	suite.Run("InvalidTarget", func() {
+		var actual testAddress
+		suite.ErrorIs(DecodeAttributes(testAttributes{}, actual), ErrInvalidDecodeTarget)
+		suite.ErrorIs(DecodeAttributes(testAttributes{}, (*testAddress)(nil)), ErrInvalidDecodeTarget)
+		suite.ErrorIs(DecodeAttributes(testAttributes{}, nil), ErrInvalidDecodeTarget)
+	})
+
+	suite.Run("Null", func() {
+		raw := suite.testRaw()
+		raw["address"] = nil
+		raw["roles"] = nil
+		raw["Labels"] = nil
+
+		actual := testProfile{Address: &testAddress{City: "overwritten"}}
+		suite.Require().NoError(DecodeAttributes(testAttributes(raw), &actual))
+
+		expected := suite.expectedProfile()
+		expected.Address = nil
+		expected.Roles = nil
+		expected.Labels = nil
+		suite.Equal(expected, actual)
+
+		raw["name"] = nil
+		suite.ErrorIs(DecodeAttributes(testAttributes(raw), &actual), ErrAttributeConversion)
+	})
+
+	suite.Run("Error", func() {
+		actual := testAddress{City: "unchanged"}
+		err := DecodeAttributes(testAttributes{"city": 123}, &actual)
+		suite.ErrorIs(err, ErrAttributeConversion)
+		suite.Equal("unchanged", actual.City)
+	})
+}
+
+func (suite *ConvertTestSuite) TestGetAttribute() {
+	a := testAttributes{
+		"count":   float64(3),
+		"expires": "2024-03-01T12:30:00Z",
+		"nested":  map[string]any{"roles": []any{"a"}},
+	}
+
+	count, ok := GetAttribute[int](a, "count")
+	suite.True(ok)
+	suite.Equal(3, count)
+
+	expires, ok := GetAttribute[time.Time](a, "expires")
+	suite.True(ok)
+	suite.Equal(2024, expires.Year())
+
+	roles, ok := GetAttribute[[]string](a, "nested", "roles")
+	suite.True(ok)
+	suite.Equal([]string{"a"}, roles)
+
+	_, ok = GetAttribute[int8](testAttributes{"big": 1000.0}, "big")
+	suite.False(ok)
+}
+
+func TestConvert(t *testing.T) {
+	suite.Run(t, new(ConvertTestSuite))
+}
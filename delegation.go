@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Actor is a party that acts on behalf of a token's principal.  Actors mirror
+// the members of the RFC 8693 "act" claim.
+type Actor struct {
+	// Subject is the actor's principal, i.e. the "sub" member of an act claim.
+	Subject string
+
+	// Issuer is the optional issuer of the actor's identity, i.e. the "iss"
+	// member of an act claim.
+	Issuer string
+}
+
+// String returns the actor's subject, qualified by its issuer if one is present.
+func (a Actor) String() string {
+	if len(a.Issuer) > 0 {
+		return a.Issuer + "/" + a.Subject
+	}
+
+	return a.Subject
+}
+
+// DelegationAccessor is implemented by tokens that represent one party acting
+// on behalf of another.
+type DelegationAccessor interface {
+	// Actors returns the actor chain.  The first actor is the current actor, i.e.
+	// the party presenting the token.  Each subsequent actor is a prior actor, in
+	// the same order as nested RFC 8693 act claims.
+	Actors() []Actor
+}
+
+// GetActors returns the actor chain for a token.  If the token tree contains no
+// DelegationAccessor, or if the chain is empty, this function returns false.
+func GetActors(t Token) (actors []Actor, ok bool) {
+	var da DelegationAccessor
+	if TokenAs(t, &da) {
+		actors = da.Actors()
+		ok = len(actors) > 0
+	}
+
+	return
+}
+
+// DelegationToken is a Token that records an actor acting on behalf of a subject.
+// Its principal is the subject's principal, and it unwraps to the subject token so
+// that TokenAs finds the subject's capabilities, attributes, and so on.
+type DelegationToken struct {
+	subject Token
+	actors  []Actor
+}
+
+// NewDelegationToken creates a token in which the actors act on behalf of the
+// subject token.  The first actor is the current actor.  If no actors are supplied,
+// the subject is returned as is.
+func NewDelegationToken(subject Token, actors ...Actor) Token {
+	if len(actors) == 0 {
+		return subject
+	}
+
+	return &DelegationToken{
+		subject: subject,
+		actors:  slices.Clone(actors),
+	}
+}
+
+// Principal returns the subject's principal.
+func (dt *DelegationToken) Principal() string {
+	return dt.subject.Principal()
+}
+
+// Actor returns the current actor, i.e. the party presenting this token.
+func (dt *DelegationToken) Actor() Actor {
+	return dt.actors[0]
+}
+
+// Actors returns a copy of the actor chain, beginning with the current actor.
+func (dt *DelegationToken) Actors() []Actor {
+	return slices.Clone(dt.actors)
+}
+
+// Unwrap returns the subject token.
+func (dt *DelegationToken) Unwrap() Token {
+	return dt.subject
+}
+
+// ImpersonationDeniedError is returned by an impersonation Approver when an actor
+// is not permitted to act on behalf of a principal.  This error always has
+// ErrUnauthorized in its chain.
+type ImpersonationDeniedError struct {
+	// Actor is the actor that was denied.
+	Actor Actor
+
+	// Principal is the principal the actor attempted to act on behalf of.
+	Principal string
+}
+
+// Unwrap returns ErrUnauthorized.
+func (ide *ImpersonationDeniedError) Unwrap() error {
+	return ErrUnauthorized
+}
+
+func (ide *ImpersonationDeniedError) Error() string {
+	var o strings.Builder
+	o.WriteString("actor ")
+	o.WriteString(ide.Actor.String())
+	if len(ide.Principal) > 0 {
+		o.WriteString(" may not act on behalf of ")
+		o.WriteString(ide.Principal)
+	} else {
+		o.WriteString(" may not act on behalf of another principal")
+	}
+
+	return o.String()
+}
+
+// ImpersonationRule decides whether an actor may act on behalf of a principal.
+type ImpersonationRule func(ctx context.Context, actor Actor, principal string) bool
+
+// AllowActors is an ImpersonationRule that allows the given actor subjects to act
+// on behalf of any principal.
+func AllowActors(subjects ...string) ImpersonationRule {
+	allowed := slices.Clone(subjects)
+	return func(_ context.Context, actor Actor, _ string) bool {
+		return slices.Contains(allowed, actor.Subject)
+	}
+}
+
+// AllowActorFor is an ImpersonationRule that allows a single actor subject to act
+// on behalf of only the given principals.
+func AllowActorFor(subject string, principals ...string) ImpersonationRule {
+	allowed := slices.Clone(principals)
+	return func(_ context.Context, actor Actor, principal string) bool {
+		return actor.Subject == subject && slices.Contains(allowed, principal)
+	}
+}
+
+// NewImpersonationApprover creates an Approver that restricts which actors may act
+// on behalf of which principals.  Tokens without an actor chain are approved.  For a
+// delegated token, the current actor must be allowed by at least one rule to act on
+// behalf of the token's principal.  Otherwise, an *ImpersonationDeniedError is returned.
+//
+// Per RFC 8693 section 4.1, prior actors are informational and are not consulted.
+// Use NewChainImpersonationApprover to require that every actor be allowed.
+//
+// With no rules, this approver denies every delegated token.
+func NewImpersonationApprover[R any](rules ...ImpersonationRule) Approver[R] {
+	return newImpersonationApprover[R](false, rules)
+}
+
+// NewChainImpersonationApprover is like NewImpersonationApprover, except that every
+// actor in the chain, including prior actors, must be allowed by at least one rule to
+// act on behalf of the token's principal.  An *ImpersonationDeniedError is returned
+// for the first actor that was not allowed.
+func NewChainImpersonationApprover[R any](rules ...ImpersonationRule) Approver[R] {
+	return newImpersonationApprover[R](true, rules)
+}
+
+func newImpersonationApprover[R any](chain bool, rules []ImpersonationRule) Approver[R] {
+	rules = slices.Clone(rules)
+	return ApproverFunc[R](func(ctx context.Context, _ R, token Token) error {
+		actors, delegated := GetActors(token)
+		if !delegated {
+			return nil
+		}
+
+		if !chain {
+			actors = actors[:1]
+		}
+
+		principal := token.Principal()
+		for _, actor := range actors {
+			allowed := false
+			for i := 0; !allowed && i < len(rules); i++ {
+				allowed = rules[i](ctx, actor, principal)
+			}
+
+			if !allowed {
+				return &ImpersonationDeniedError{
+					Actor:     actor,
+					Principal: principal,
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// NewMaxDelegationDepthApprover creates an Approver that denies tokens whose actor
+// chain is longer than maxDepth.  A maxDepth of zero denies every delegated token,
+// while a maxDepth of one allows a single actor but no prior actors.  A negative
+// maxDepth is treated as zero.
+func NewMaxDelegationDepthApprover[R any](maxDepth int) Approver[R] {
+	maxDepth = max(maxDepth, 0)
+	return ApproverFunc[R](func(_ context.Context, _ R, token Token) error {
+		if actors, _ := GetActors(token); len(actors) > maxDepth {
+			return &ImpersonationDeniedError{
+				Actor:     actors[maxDepth],
+				Principal: token.Principal(),
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DelegationTestSuite struct {
+	TestSuite
+}
+
+// newSubject creates a subject token with capabilities.
+func (suite *DelegationTestSuite) newSubject() Token {
+	t := new(mockTokenWithCapabilities)
+	t.ExpectPrincipal("customer").Maybe()
+	t.ExpectCapabilities("doc:read").Maybe()
+	return t
+}
+
+func (suite *DelegationTestSuite) TestActor() {
+	suite.Equal("support", Actor{Subject: "support"}.String())
+	suite.Equal("https://issuer/support", Actor{Subject: "support", Issuer: "https://issuer"}.String())
+}
+
+func (suite *DelegationTestSuite) TestNewDelegationToken() {
+	suite.Run("NoActors", func() {
+		subject := StubToken("customer")
+		suite.Equal(subject, NewDelegationToken(subject))
+
+		_, ok := GetActors(subject)
+		suite.False(ok)
+	})
+
+	suite.Run("Actors", func() {
+		var (
+			subject = suite.newSubject()
+			actors  = []Actor{{Subject: "support-tool"}, {Subject: "admin", Issuer: "corp"}}
+			t       = NewDelegationToken(subject, actors...)
+		)
+
+		suite.Equal("customer", t.Principal())
+
+		dt, ok := t.(*DelegationToken)
+		suite.Require().True(ok)
+		suite.Equal(actors[0], dt.Actor())
+		suite.Equal(actors, dt.Actors())
+		suite.Same(subject, dt.Unwrap())
+
+		// the chain is copied
+		actors[0].Subject = "changed"
+		suite.Equal("support-tool", dt.Actor().Subject)
+		dt.Actors()[0].Subject = "changed"
+		suite.Equal("support-tool", dt.Actor().Subject)
+
+		chain, ok := GetActors(t)
+		suite.True(ok)
+		suite.Equal([]Actor{{Subject: "support-tool"}, {Subject: "admin", Issuer: "corp"}}, chain)
+
+		// TokenAs still finds the subject's accessors
+		var ca CapabilitiesAccessor
+		suite.Require().True(TokenAs(t, &ca))
+		suite.Equal([]string{"doc:read"}, ca.Capabilities())
+
+		cs, ok := GetCapabilitySet(t)
+		suite.True(ok)
+		suite.True(cs.Implies(MustParsePermission("doc:read")))
+	})
+}
+
+func (suite *DelegationTestSuite) TestImpersonationDeniedError() {
+	err := &ImpersonationDeniedError{Actor: Actor{Subject: "support"}, Principal: "customer"}
+	suite.ErrorIs(err, ErrUnauthorized)
+	suite.Equal("actor support may not act on behalf of customer", err.Error())
+
+	err = &ImpersonationDeniedError{Actor: Actor{Subject: "support"}}
+	suite.Equal("actor support may not act on behalf of another principal", err.Error())
+}
+
+func (suite *DelegationTestSuite) TestImpersonationApprover() {
+	var (
+		ctx      = suite.testContext()
+		support  = Actor{Subject: "support-tool"}
+		admin    = Actor{Subject: "admin"}
+		intruder = Actor{Subject: "intruder"}
+
+		rules = []ImpersonationRule{
+			AllowActors("support-tool"),
+			AllowActorFor("admin", "customer", "other"),
+		}
+
+		approver      = NewImpersonationApprover[string](rules...)
+		chainApprover = NewChainImpersonationApprover[string](rules...)
+	)
+
+	testCases := []struct {
+		name    string
+		token   Token
+		denied  *Actor
+		approve Approver[string]
+	}{
+		{
+			name:  "NotDelegated",
+			token: StubToken("customer"),
+		},
+		{
+			name:  "AnyPrincipal",
+			token: NewDelegationToken(StubToken("anyone"), support),
+		},
+		{
+			name:  "SpecificPrincipal",
+			token: NewDelegationToken(StubToken("customer"), admin),
+		},
+		{
+			name:   "WrongPrincipal",
+			token:  NewDelegationToken(StubToken("anyone"), admin),
+			denied: &admin,
+		},
+		{
+			name:   "UnknownActor",
+			token:  NewDelegationToken(StubToken("customer"), intruder),
+			denied: &intruder,
+		},
+		{
+			name:  "PriorActorIgnored",
+			token: NewDelegationToken(StubToken("customer"), support, intruder),
+		},
+		{
+			name:   "CurrentActorDenied",
+			token:  NewDelegationToken(StubToken("customer"), intruder, support),
+			denied: &intruder,
+		},
+		{
+			name:  "Chain",
+			token: NewDelegationToken(StubToken("customer"), support, admin),
+		},
+		{
+			name:    "ChainPriorActorDenied",
+			token:   NewDelegationToken(StubToken("customer"), support, intruder),
+			denied:  &intruder,
+			approve: chainApprover,
+		},
+		{
+			name:    "ChainAllowed",
+			token:   NewDelegationToken(StubToken("customer"), support, admin),
+			approve: chainApprover,
+		},
+		{
+			name:    "NoRules",
+			token:   NewDelegationToken(StubToken("customer"), support),
+			denied:  &support,
+			approve: NewImpersonationApprover[string](),
+		},
+		{
+			name:    "ChainNoRules",
+			token:   NewDelegationToken(StubToken("customer"), support),
+			denied:  &support,
+			approve: NewChainImpersonationApprover[string](),
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			a := testCase.approve
+			if a == nil {
+				a = approver
+			}
+
+			err := a.Approve(ctx, "resource", testCase.token)
+			if testCase.denied == nil {
+				suite.NoError(err)
+				return
+			}
+
+			suite.ErrorIs(err, ErrUnauthorized)
+			var ide *ImpersonationDeniedError
+			suite.Require().ErrorAs(err, &ide)
+			suite.Equal(*testCase.denied, ide.Actor)
+			suite.Equal(testCase.token.Principal(), ide.Principal)
+		})
+	}
+}
+
+func (suite *DelegationTestSuite) TestImpersonationApproverContext() {
+	type ctxKey struct{}
+	var (
+		ctx      = context.WithValue(suite.testContext(), ctxKey{}, "allowed")
+		approver = NewImpersonationApprover[string](
+			func(ctx context.Context, actor Actor, _ string) bool {
+				return ctx.Value(ctxKey{}) == "allowed"
+			},
+		)
+
+		token = NewDelegationToken(StubToken("customer"), Actor{Subject: "support"})
+	)
+
+	suite.NoError(approver.Approve(ctx, "resource", token))
+	suite.ErrorIs(approver.Approve(suite.testContext(), "resource", token), ErrUnauthorized)
+}
+
+func (suite *DelegationTestSuite) TestMaxDelegationDepthApprover() {
+	var (
+		ctx    = suite.testContext()
+		first  = Actor{Subject: "first"}
+		second = Actor{Subject: "second"}
+	)
+
+	suite.Run("Zero", func() {
+		a := NewMaxDelegationDepthApprover[string](0)
+		suite.NoError(a.Approve(ctx, "resource", StubToken("customer")))
+		suite.ErrorIs(a.Approve(ctx, "resource", NewDelegationToken(StubToken("customer"), first)), ErrUnauthorized)
+	})
+
+	suite.Run("Negative", func() {
+		a := NewMaxDelegationDepthApprover[string](-1)
+		suite.NoError(a.Approve(ctx, "resource", StubToken("customer")))
+
+		err := a.Approve(ctx, "resource", NewDelegationToken(StubToken("customer"), first))
+		var ide *ImpersonationDeniedError
+		suite.Require().ErrorAs(err, &ide)
+		suite.Equal(first, ide.Actor)
+	})
+
+	suite.Run("One", func() {
+		a := NewMaxDelegationDepthApprover[string](1)
+		suite.NoError(a.Approve(ctx, "resource", NewDelegationToken(StubToken("customer"), first)))
+
+		err := a.Approve(ctx, "resource", NewDelegationToken(StubToken("customer"), first, second))
+		var ide *ImpersonationDeniedError
+		suite.Require().ErrorAs(err, &ide)
+		suite.Equal(second, ide.Actor)
+	})
+}
+
+func TestDelegation(t *testing.T) {
+	suite.Run(t, new(DelegationTestSuite))
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import "errors"
+
+// ErrorCategory is a coarse, low-cardinality classification of a workflow error.
+// Categories are suitable for use in logs, metrics labels, and trace attributes.
+type ErrorCategory string
+
+const (
+	// CategoryNone is the category of a nil error.
+	CategoryNone ErrorCategory = ""
+
+	// CategoryMissingCredentials indicates an error with ErrMissingCredentials in its chain.
+	CategoryMissingCredentials ErrorCategory = "missing_credentials"
+
+	// CategoryInvalidCredentials indicates an error with ErrInvalidCredentials in its chain.
+	CategoryInvalidCredentials ErrorCategory = "invalid_credentials"
+
+	// CategoryBadCredentials indicates an error with ErrBadCredentials in its chain.
+	CategoryBadCredentials ErrorCategory = "bad_credentials"
+
+	// CategoryTokenExpired indicates an error with ErrTokenExpired in its chain.
+	CategoryTokenExpired ErrorCategory = "token_expired"
+
+	// CategoryTokenNotYetValid indicates an error with ErrTokenNotYetValid in its chain.
+	CategoryTokenNotYetValid ErrorCategory = "token_not_yet_valid"
+
+	// CategoryRevokedCredentials indicates an error with ErrRevokedCredentials in its chain.
+	CategoryRevokedCredentials ErrorCategory = "revoked_credentials"
+
+	// CategoryPanic indicates an error with ErrPanic in its chain.
+	CategoryPanic ErrorCategory = "panic"
+
+	// CategoryLockedOut indicates an error with ErrLockedOut in its chain.
+	CategoryLockedOut ErrorCategory = "locked_out"
+
+	// CategoryUnauthorized indicates an error with ErrUnauthorized in its chain.
+	CategoryUnauthorized ErrorCategory = "unauthorized"
+
+	// CategoryNoTokenParsers indicates an error with ErrNoTokenParsers in its chain.
+	CategoryNoTokenParsers ErrorCategory = "no_token_parsers"
+
+	// CategoryOther is the category for any non-nil error that doesn't fit
+	// into one of the other categories.
+	CategoryOther ErrorCategory = "other"
+)
+
+// String returns the string form of this category.
+func (ec ErrorCategory) String() string {
+	return string(ec)
+}
+
+// CategorizeError classifies an error returned by a bascule workflow.  The more
+// specific categories are checked first, so an error that wraps both ErrTokenExpired
+// and ErrBadCredentials is categorized as CategoryTokenExpired.
+//
+// ErrMissingCredentials has the lowest precedence of all the sentinels, since it only
+// indicates that a parser found nothing to parse.  An aggregate error, such as one from
+// TokenParsers.All, that wraps both ErrMissingCredentials and ErrBadCredentials is
+// categorized as CategoryBadCredentials.
+func CategorizeError(err error) ErrorCategory {
+	switch {
+	case err == nil:
+		return CategoryNone
+
+	case errors.Is(err, ErrPanic):
+		return CategoryPanic
+
+	case errors.Is(err, ErrLockedOut):
+		return CategoryLockedOut
+
+	case errors.Is(err, ErrTokenExpired):
+		return CategoryTokenExpired
+
+	case errors.Is(err, ErrTokenNotYetValid):
+		return CategoryTokenNotYetValid
+
+	case errors.Is(err, ErrRevokedCredentials):
+		return CategoryRevokedCredentials
+
+	case errors.Is(err, ErrInvalidCredentials):
+		return CategoryInvalidCredentials
+
+	case errors.Is(err, ErrBadCredentials):
+		return CategoryBadCredentials
+
+	case errors.Is(err, ErrUnauthorized):
+		return CategoryUnauthorized
+
+	case errors.Is(err, ErrNoTokenParsers):
+		return CategoryNoTokenParsers
+
+	case errors.Is(err, ErrMissingCredentials):
+		return CategoryMissingCredentials
+
+	default:
+		return CategoryOther
+	}
+}
+
+// OnlyMissingCredentials tests if err reports missing credentials and nothing else.
+// Errors that aggregate other errors, e.g. via errors.Join or an 'Unwrap() []error'
+// method, satisfy this function only when every aggregated error does.  Any other
+// error in the tree, including ones bascule does not recognize, causes this function
+// to return false.
+//
+// This is a stricter test than comparing CategorizeError to CategoryMissingCredentials,
+// and should be used when missing credentials are allowed to proceed, such as with
+// anonymous access.
+func OnlyMissingCredentials(err error) bool {
+	switch u := err.(type) {
+	case nil:
+		return false
+
+	case interface{ Unwrap() []error }:
+		errs := u.Unwrap()
+		for _, e := range errs {
+			if !OnlyMissingCredentials(e) {
+				return false
+			}
+		}
+
+		return len(errs) > 0
+
+	case interface{ Unwrap() error }:
+		return OnlyMissingCredentials(u.Unwrap())
+
+	default:
+		return errors.Is(err, ErrMissingCredentials)
+	}
+}
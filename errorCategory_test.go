@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorCategoryTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ErrorCategoryTestSuite) TestCategorizeError() {
+	testCases := []struct {
+		err      error
+		expected ErrorCategory
+	}{
+		{err: nil, expected: CategoryNone},
+		{err: ErrMissingCredentials, expected: CategoryMissingCredentials},
+		{err: ErrInvalidCredentials, expected: CategoryInvalidCredentials},
+		{err: ErrBadCredentials, expected: CategoryBadCredentials},
+		{err: ErrTokenExpired, expected: CategoryTokenExpired},
+		{err: ErrTokenNotYetValid, expected: CategoryTokenNotYetValid},
+		{err: ErrRevokedCredentials, expected: CategoryRevokedCredentials},
+		{err: ErrUnauthorized, expected: CategoryUnauthorized},
+		{err: &LockoutError{RetryAfter: time.Second}, expected: CategoryLockedOut},
+		{err: ErrNoTokenParsers, expected: CategoryNoTokenParsers},
+		{err: &PanicError{Value: ErrBadCredentials}, expected: CategoryPanic},
+		{err: errors.New("expected"), expected: CategoryOther},
+		{err: fmt.Errorf("wrapped: %w", ErrBadCredentials), expected: CategoryBadCredentials},
+		{err: errors.Join(ErrBadCredentials, ErrTokenExpired), expected: CategoryTokenExpired},
+		{err: errors.Join(ErrMissingCredentials, ErrInvalidCredentials), expected: CategoryInvalidCredentials},
+		{err: errors.Join(ErrMissingCredentials, ErrBadCredentials), expected: CategoryBadCredentials},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(string(testCase.expected), func() {
+			actual := CategorizeError(testCase.err)
+			suite.Equal(testCase.expected, actual)
+			suite.Equal(string(testCase.expected), actual.String())
+		})
+	}
+}
+
+func (suite *ErrorCategoryTestSuite) TestOnlyMissingCredentials() {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil", err: nil, expected: false},
+		{name: "Missing", err: ErrMissingCredentials, expected: true},
+		{name: "Wrapped", err: fmt.Errorf("wrapped: %w", ErrMissingCredentials), expected: true},
+		{name: "Joined", err: errors.Join(ErrMissingCredentials, fmt.Errorf("wrapped: %w", ErrMissingCredentials)), expected: true},
+		{name: "EmptyJoin", err: errors.Join(ErrMissingCredentials, errors.Join()), expected: true},
+		{name: "Bad", err: ErrBadCredentials, expected: false},
+		{name: "MissingAndBad", err: errors.Join(ErrMissingCredentials, ErrBadCredentials), expected: false},
+		{name: "MissingAndOther", err: errors.Join(ErrMissingCredentials, errors.New("expected")), expected: false},
+		{name: "MissingWithCause", err: fmt.Errorf("%w: %w", ErrMissingCredentials, ErrTokenExpired), expected: false},
+		{name: "ParseError", err: &ParseError{Attempts: []ParseAttempt{{Err: ErrMissingCredentials}}}, expected: true},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.Equal(testCase.expected, OnlyMissingCredentials(testCase.err))
+		})
+	}
+}
+
+func TestErrorCategory(t *testing.T) {
+	suite.Run(t, new(ErrorCategoryTestSuite))
+}
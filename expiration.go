@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrTokenExpired indicates that a token's expiration time has passed.
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrTokenNotYetValid indicates that a token cannot be used yet, either because
+	// its not-before time is in the future or because it was issued in the future.
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+
+	// ErrMissingExpiration indicates that a token was required to have an expiration
+	// time, but did not expose one.  This error wraps ErrInvalidCredentials, since such
+	// a token can never be acceptable.
+	ErrMissingExpiration = fmt.Errorf("missing expiration: %w", ErrInvalidCredentials)
+
+	// ErrNegativeClockSkew is returned by NewTimeWindowValidator when a negative
+	// clock skew is configured.
+	ErrNegativeClockSkew = errors.New("clock skew cannot be negative")
+)
+
+// ExpirationAccessor is an optional interface that a Token may implement to
+// expose the time after which it must no longer be accepted.
+type ExpirationAccessor interface {
+	// Expiration returns the expiry time of this token.  A zero time
+	// indicates that the token does not expire.
+	Expiration() time.Time
+}
+
+// NotBeforeAccessor is an optional interface that a Token may implement to
+// expose the time before which it must not be accepted.
+type NotBeforeAccessor interface {
+	// NotBefore returns the time at which this token becomes valid.  A zero
+	// time indicates that the token is valid immediately.
+	NotBefore() time.Time
+}
+
+// IssuedAtAccessor is an optional interface that a Token may implement to
+// expose the time at which it was issued.
+type IssuedAtAccessor interface {
+	// IssuedAt returns the time this token was issued.  A zero time indicates
+	// that the issue time is unknown.
+	IssuedAt() time.Time
+}
+
+// GetExpiration returns the expiration time of the given Token.
+//
+// If the token, or any token in its tree, implements ExpirationAccessor and
+// reports a non-zero time, that time is returned along with true.  Otherwise,
+// this function returns the zero time and false.
+func GetExpiration(t Token) (exp time.Time, exists bool) {
+	var ea ExpirationAccessor
+	if TokenAs(t, &ea) {
+		exp = ea.Expiration()
+		exists = !exp.IsZero()
+	}
+
+	return
+}
+
+// GetNotBefore returns the not-before time of the given Token.  The semantics
+// of this function are the same as GetExpiration.
+func GetNotBefore(t Token) (nbf time.Time, exists bool) {
+	var nba NotBeforeAccessor
+	if TokenAs(t, &nba) {
+		nbf = nba.NotBefore()
+		exists = !nbf.IsZero()
+	}
+
+	return
+}
+
+// GetIssuedAt returns the issued-at time of the given Token.  The semantics
+// of this function are the same as GetExpiration.
+func GetIssuedAt(t Token) (iat time.Time, exists bool) {
+	var iaa IssuedAtAccessor
+	if TokenAs(t, &iaa) {
+		iat = iaa.IssuedAt()
+		exists = !iat.IsZero()
+	}
+
+	return
+}
+
+// TimeWindowOption is a configurable option for a time window Validator.
+type TimeWindowOption interface {
+	apply(*timeWindow) error
+}
+
+type timeWindowOptionFunc func(*timeWindow) error
+
+func (twof timeWindowOptionFunc) apply(tw *timeWindow) error { return twof(tw) }
+
+// WithClockSkew sets the amount of clock skew tolerated when comparing token
+// times to the current time.  By default, no skew is tolerated.  A negative
+// skew results in ErrNegativeClockSkew.
+func WithClockSkew(skew time.Duration) TimeWindowOption {
+	return timeWindowOptionFunc(func(tw *timeWindow) error {
+		if skew < 0 {
+			return ErrNegativeClockSkew
+		}
+
+		tw.skew = skew
+		return nil
+	})
+}
+
+// WithClock sets the closure used to obtain the current time.  By default,
+// time.Now is used.  A nil closure restores the default.
+func WithClock(now func() time.Time) TimeWindowOption {
+	return timeWindowOptionFunc(func(tw *timeWindow) error {
+		tw.now = now
+		return nil
+	})
+}
+
+// WithRequireExpiration causes tokens that do not expose an expiration time to
+// be rejected with ErrMissingExpiration.  By default, tokens without an expiration
+// time are considered to never expire.
+func WithRequireExpiration() TimeWindowOption {
+	return timeWindowOptionFunc(func(tw *timeWindow) error {
+		tw.requireExpiration = true
+		return nil
+	})
+}
+
+// timeWindow holds the source-independent configuration and logic for
+// enforcing a token's validity window.
+type timeWindow struct {
+	now               func() time.Time
+	skew              time.Duration
+	requireExpiration bool
+}
+
+func (tw *timeWindow) check(t Token) error {
+	now := tw.now()
+	exp, hasExp := GetExpiration(t)
+	switch {
+	case hasExp && now.After(exp.Add(tw.skew)):
+		return ErrTokenExpired
+
+	case !hasExp && tw.requireExpiration:
+		return ErrMissingExpiration
+	}
+
+	if nbf, ok := GetNotBefore(t); ok && now.Add(tw.skew).Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+
+	if iat, ok := GetIssuedAt(t); ok && now.Add(tw.skew).Before(iat) {
+		return ErrTokenNotYetValid
+	}
+
+	return nil
+}
+
+// timeWindowValidator adapts a timeWindow onto the Validator interface.
+type timeWindowValidator[S any] struct {
+	tw timeWindow
+}
+
+func (twv *timeWindowValidator[S]) Validate(_ context.Context, _ S, t Token) (Token, error) {
+	return t, twv.tw.check(t)
+}
+
+// NewTimeWindowValidator creates a Validator that enforces a token's expiration,
+// not-before, and issued-at times, as exposed by ExpirationAccessor, NotBeforeAccessor,
+// and IssuedAtAccessor.  Tokens that implement none of these interfaces are considered
+// valid unless WithRequireExpiration is used.
+//
+// A token whose expiration time has passed results in ErrTokenExpired.  A token whose
+// not-before or issued-at time is in the future results in ErrTokenNotYetValid.  A token
+// without an expiration time results in ErrMissingExpiration if WithRequireExpiration is used.
+func NewTimeWindowValidator[S any](opts ...TimeWindowOption) (Validator[S], error) {
+	twv := new(timeWindowValidator[S])
+	for _, o := range opts {
+		if err := o.apply(&twv.tw); err != nil {
+			return nil, err
+		}
+	}
+
+	if twv.tw.now == nil {
+		twv.tw.now = time.Now
+	}
+
+	return twv, nil
+}
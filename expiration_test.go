@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type timeWindowToken struct {
+	exp, nbf, iat time.Time
+}
+
+func (twt timeWindowToken) Principal() string { return "test" }
+
+func (twt timeWindowToken) Expiration() time.Time { return twt.exp }
+
+func (twt timeWindowToken) NotBefore() time.Time { return twt.nbf }
+
+func (twt timeWindowToken) IssuedAt() time.Time { return twt.iat }
+
+type ExpirationTestSuite struct {
+	TestSuite
+
+	now time.Time
+}
+
+func (suite *ExpirationTestSuite) SetupSuite() {
+	suite.now = time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *ExpirationTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *ExpirationTestSuite) newValidator(opts ...TimeWindowOption) Validator[string] {
+	v, err := NewTimeWindowValidator[string](
+		append([]TimeWindowOption{WithClock(suite.clock)}, opts...)...,
+	)
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(v)
+	return v
+}
+
+func (suite *ExpirationTestSuite) TestGetters() {
+	suite.Run("NoAccessors", func() {
+		t := StubToken("test")
+
+		_, ok := GetExpiration(t)
+		suite.False(ok)
+
+		_, ok = GetNotBefore(t)
+		suite.False(ok)
+
+		_, ok = GetIssuedAt(t)
+		suite.False(ok)
+	})
+
+	suite.Run("ZeroTimes", func() {
+		t := timeWindowToken{}
+
+		_, ok := GetExpiration(t)
+		suite.False(ok)
+
+		_, ok = GetNotBefore(t)
+		suite.False(ok)
+
+		_, ok = GetIssuedAt(t)
+		suite.False(ok)
+	})
+
+	suite.Run("Nested", func() {
+		expected := timeWindowToken{
+			exp: suite.now.Add(time.Hour),
+			nbf: suite.now.Add(-time.Hour),
+			iat: suite.now.Add(-time.Minute),
+		}
+
+		t := JoinTokens(StubToken("first"), expected)
+
+		exp, ok := GetExpiration(t)
+		suite.True(ok)
+		suite.Equal(expected.exp, exp)
+
+		nbf, ok := GetNotBefore(t)
+		suite.True(ok)
+		suite.Equal(expected.nbf, nbf)
+
+		iat, ok := GetIssuedAt(t)
+		suite.True(ok)
+		suite.Equal(expected.iat, iat)
+	})
+}
+
+func (suite *ExpirationTestSuite) TestNewTimeWindowValidator() {
+	suite.Run("NegativeSkew", func() {
+		v, err := NewTimeWindowValidator[string](WithClockSkew(-time.Second))
+		suite.ErrorIs(err, ErrNegativeClockSkew)
+		suite.Nil(v)
+	})
+
+	suite.Run("DefaultClock", func() {
+		v, err := NewTimeWindowValidator[string](WithClock(nil))
+		suite.Require().NoError(err)
+
+		next, err := v.Validate(suite.testContext(), "source", timeWindowToken{
+			exp: time.Now().Add(time.Hour),
+		})
+
+		suite.NoError(err)
+		suite.NotNil(next)
+	})
+}
+
+func (suite *ExpirationTestSuite) TestValidate() {
+	testCases := []struct {
+		name        string
+		token       Token
+		opts        []TimeWindowOption
+		expectedErr error
+	}{
+		{
+			name:  "NoTimes",
+			token: StubToken("test"),
+		},
+		{
+			name:        "NoTimesRequireExpiration",
+			token:       StubToken("test"),
+			opts:        []TimeWindowOption{WithRequireExpiration()},
+			expectedErr: ErrMissingExpiration,
+		},
+		{
+			name: "Valid",
+			token: timeWindowToken{
+				exp: suite.now.Add(time.Hour),
+				nbf: suite.now.Add(-time.Hour),
+				iat: suite.now.Add(-time.Hour),
+			},
+		},
+		{
+			name:        "Expired",
+			token:       timeWindowToken{exp: suite.now.Add(-time.Second)},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name:  "ExpiredWithinSkew",
+			token: timeWindowToken{exp: suite.now.Add(-time.Second)},
+			opts:  []TimeWindowOption{WithClockSkew(time.Minute)},
+		},
+		{
+			name:        "ExpiredBeyondSkew",
+			token:       timeWindowToken{exp: suite.now.Add(-2 * time.Minute)},
+			opts:        []TimeWindowOption{WithClockSkew(time.Minute)},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name:        "NotBefore",
+			token:       timeWindowToken{nbf: suite.now.Add(time.Second)},
+			expectedErr: ErrTokenNotYetValid,
+		},
+		{
+			name:  "NotBeforeWithinSkew",
+			token: timeWindowToken{nbf: suite.now.Add(time.Second)},
+			opts:  []TimeWindowOption{WithClockSkew(time.Minute)},
+		},
+		{
+			name:        "IssuedInFuture",
+			token:       timeWindowToken{iat: suite.now.Add(time.Second)},
+			expectedErr: ErrTokenNotYetValid,
+		},
+		{
+			name:  "IssuedInFutureWithinSkew",
+			token: timeWindowToken{iat: suite.now.Add(time.Second)},
+			opts:  []TimeWindowOption{WithClockSkew(time.Minute)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			v := suite.newValidator(testCase.opts...)
+			next, err := v.Validate(suite.testContext(), "source", testCase.token)
+			suite.Equal(testCase.token, next)
+			suite.ErrorIs(err, testCase.expectedErr)
+			if testCase.expectedErr == nil {
+				suite.NoError(err)
+			}
+		})
+	}
+}
+
+func (suite *ExpirationTestSuite) TestMissingExpiration() {
+	v := suite.newValidator(WithRequireExpiration())
+	_, err := v.Validate(suite.testContext(), "source", StubToken("test"))
+	suite.ErrorIs(err, ErrMissingExpiration)
+	suite.ErrorIs(err, ErrInvalidCredentials)
+	suite.NotErrorIs(err, ErrTokenExpired)
+	suite.Equal(CategoryInvalidCredentials, CategorizeError(err))
+}
+
+func TestExpiration(t *testing.T) {
+	suite.Run(t, new(ExpirationTestSuite))
+}
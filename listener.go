@@ -3,7 +3,9 @@
 
 package bascule
 
-// Listener is a sink for bascule events.
+// Listener is a sink for bascule events.  A Listener that might block or panic
+// can be wrapped with NewAsyncListener.  A Listener that might panic can also be
+// decorated with Listeners.Recover.
 type Listener[E any] interface {
 	// OnEvent receives a bascule event.  This method must not block or panic.
 	OnEvent(E)
@@ -44,3 +46,20 @@ func (ls Listeners[E]) OnEvent(e E) {
 		l.OnEvent(e)
 	}
 }
+
+// Recover returns a copy of this aggregate in which each listener is decorated
+// to recover panics.  Each panic is converted into a *PanicError and reported to
+// the given hook, which may be nil.  A panicking listener does not prevent the
+// remaining listeners from receiving the event.
+func (ls Listeners[E]) Recover(hook PanicHook) Listeners[E] {
+	if len(ls) == 0 {
+		return nil
+	}
+
+	recovered := make(Listeners[E], len(ls))
+	for i, l := range ls {
+		recovered[i] = recoverListener[E]{next: l, hook: hook}
+	}
+
+	return recovered
+}
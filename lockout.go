@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrLockedOut indicates that authentication was refused because too many
+	// recent attempts failed, e.g. for the same principal or from the same client.
+	// No credential checks are performed while locked out.
+	ErrLockedOut = errors.New("locked out")
+)
+
+// LockoutError is returned when authentication is refused due to a temporary
+// lockout.  This error always has ErrLockedOut in its chain.
+type LockoutError struct {
+	// RetryAfter is the amount of time until the lockout expires.
+	RetryAfter time.Duration
+}
+
+// Unwrap returns ErrLockedOut.
+func (le *LockoutError) Unwrap() error {
+	return ErrLockedOut
+}
+
+// RetryAfterSeconds returns RetryAfter rounded up to whole seconds, as
+// required by protocols such as the HTTP Retry-After header.  The result
+// is never less than 1.
+func (le *LockoutError) RetryAfterSeconds() int64 {
+	seconds := int64((le.RetryAfter + time.Second - 1) / time.Second)
+	return max(seconds, 1)
+}
+
+func (le *LockoutError) Error() string {
+	var o strings.Builder
+	o.WriteString("locked out: retry after ")
+	o.WriteString(strconv.FormatInt(le.RetryAfterSeconds(), 10))
+	o.WriteString("s")
+	return o.String()
+}
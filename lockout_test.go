@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LockoutErrorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LockoutErrorTestSuite) TestLockoutError() {
+	testCases := []struct {
+		retryAfter time.Duration
+		expected   int64
+	}{
+		{retryAfter: 0, expected: 1},
+		{retryAfter: 10 * time.Millisecond, expected: 1},
+		{retryAfter: time.Second, expected: 1},
+		{retryAfter: 1500 * time.Millisecond, expected: 2},
+		{retryAfter: time.Minute, expected: 60},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.retryAfter.String(), func() {
+			le := &LockoutError{RetryAfter: testCase.retryAfter}
+			suite.ErrorIs(le, ErrLockedOut)
+			suite.Equal(testCase.expected, le.RetryAfterSeconds())
+			suite.Contains(le.Error(), "locked out")
+		})
+	}
+}
+
+func TestLockoutError(t *testing.T) {
+	suite.Run(t, new(LockoutErrorTestSuite))
+}
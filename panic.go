@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+var (
+	// ErrPanic indicates that a workflow component panicked.  When panic recovery
+	// is enabled, the panic is converted into a *PanicError, which has this error
+	// in its chain.
+	ErrPanic = errors.New("component panicked")
+)
+
+// PanicError is the error produced when panic recovery is enabled and a workflow
+// component, such as a parser, validator, approver, or listener, panics.  A PanicError
+// always fails the workflow step in which it occurred.
+type PanicError struct {
+	// Component is the name of the component that panicked, as reported by ComponentName.
+	Component string
+
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Unwrap returns ErrPanic along with the panic value, if that value is an error.
+func (pe *PanicError) Unwrap() []error {
+	if err, ok := pe.Value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+
+	return []error{ErrPanic}
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", pe.Component, pe.Value)
+}
+
+// PanicHook is a closure that is notified of each recovered panic.  A PanicHook
+// is typically used to log the panic's stack trace.  A PanicHook must not panic.
+type PanicHook func(*PanicError)
+
+// recoverPanic converts a panic into a *PanicError, which is reported to the hook and
+// stored in err.  This function must be invoked via defer.
+func recoverPanic(component any, hook PanicHook, err *error) {
+	if r := recover(); r != nil {
+		pe := &PanicError{
+			Component: ComponentName(component),
+			Value:     r,
+			Stack:     debug.Stack(),
+		}
+
+		if hook != nil {
+			hook(pe)
+		}
+
+		if err != nil {
+			*err = pe
+		}
+	}
+}
+
+// recoverTokenParser is a TokenParser decorator that recovers panics.
+type recoverTokenParser[S any] struct {
+	next TokenParser[S]
+	hook PanicHook
+}
+
+// Name reports the name of the decorated parser, so that events are unaffected
+// by panic recovery.
+func (r recoverTokenParser[S]) Name() string {
+	return ComponentName(r.next)
+}
+
+// Unwrap returns the decorated parser.
+func (r recoverTokenParser[S]) Unwrap() TokenParser[S] {
+	return r.next
+}
+
+func (r recoverTokenParser[S]) Parse(ctx context.Context, source S) (t Token, err error) {
+	defer recoverPanic(r.next, r.hook, &err)
+	t, err = r.next.Parse(ctx, source)
+	return
+}
+
+// recoverValidator is a Validator decorator that recovers panics.
+type recoverValidator[S any] struct {
+	next Validator[S]
+	hook PanicHook
+}
+
+// Name reports the name of the decorated validator, so that events are unaffected
+// by panic recovery.
+func (r recoverValidator[S]) Name() string {
+	return ComponentName(r.next)
+}
+
+func (r recoverValidator[S]) Validate(ctx context.Context, source S, t Token) (next Token, err error) {
+	defer recoverPanic(r.next, r.hook, &err)
+	next, err = r.next.Validate(ctx, source, t)
+	return
+}
+
+// recoverApprover is an Approver decorator that recovers panics.
+type recoverApprover[R any] struct {
+	next Approver[R]
+	hook PanicHook
+}
+
+// Name reports the name of the decorated approver, so that events are unaffected
+// by panic recovery.
+func (r recoverApprover[R]) Name() string {
+	return ComponentName(r.next)
+}
+
+func (r recoverApprover[R]) Approve(ctx context.Context, resource R, token Token) (err error) {
+	defer recoverPanic(r.next, r.hook, &err)
+	err = r.next.Approve(ctx, resource, token)
+	return
+}
+
+// recoverListener is a Listener decorator that recovers panics.
+type recoverListener[E any] struct {
+	next Listener[E]
+	hook PanicHook
+}
+
+// Name reports the name of the decorated listener.
+func (r recoverListener[E]) Name() string {
+	return ComponentName(r.next)
+}
+
+func (r recoverListener[E]) OnEvent(e E) {
+	defer recoverPanic(r.next, r.hook, nil)
+	r.next.OnEvent(e)
+}
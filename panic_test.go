@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PanicTestSuite struct {
+	TestSuite
+
+	panics []*PanicError
+}
+
+func (suite *PanicTestSuite) SetupTest() {
+	suite.panics = nil
+}
+
+func (suite *PanicTestSuite) hook(pe *PanicError) {
+	suite.panics = append(suite.panics, pe)
+}
+
+// assertPanic asserts that exactly one panic was reported and that err is that panic.
+func (suite *PanicTestSuite) assertPanic(err error, component string, value any) {
+	suite.Require().Len(suite.panics, 1)
+	pe := suite.panics[0]
+	suite.Equal(component, pe.Component)
+	suite.Equal(value, pe.Value)
+	suite.NotEmpty(pe.Stack)
+
+	if err != nil {
+		var actual *PanicError
+		suite.Require().ErrorAs(err, &actual)
+		suite.Same(pe, actual)
+		suite.ErrorIs(err, ErrPanic)
+	}
+}
+
+func (suite *PanicTestSuite) TestPanicError() {
+	suite.Run("NotAnError", func() {
+		pe := &PanicError{Component: "test", Value: "expected"}
+		suite.ErrorIs(pe, ErrPanic)
+		suite.Equal("panic in test: expected", pe.Error())
+	})
+
+	suite.Run("Error", func() {
+		pe := &PanicError{Component: "test", Value: ErrBadCredentials}
+		suite.ErrorIs(pe, ErrPanic)
+		suite.ErrorIs(pe, ErrBadCredentials)
+		suite.Equal("panic in test: bad credentials", pe.Error())
+	})
+}
+
+func (suite *PanicTestSuite) TestAuthenticatorParser() {
+	var event AuthenticateEvent[string]
+	a, err := NewAuthenticator(
+		WithTokenParsers[string](
+			AsTokenParser[string](func(context.Context, string) (Token, error) {
+				panic("expected")
+			}),
+		),
+		WithAuthenticateListenerFuncs(func(e AuthenticateEvent[string]) { event = e }),
+		WithAuthenticatePanicRecovery[string](suite.hook),
+	)
+
+	suite.Require().NoError(err)
+
+	token, err := a.Authenticate(suite.testContext(), "source")
+	suite.Nil(token)
+	suite.assertPanic(err, "bascule.tokenParserFunc[string]", "expected")
+
+	// the recovery decorator must not change what events report
+	suite.Require().Len(event.Attempts, 1)
+	suite.Equal("bascule.tokenParserFunc[string]", event.Attempts[0].Name)
+	suite.Equal(ParseFailed, event.Attempts[0].Outcome())
+}
+
+func (suite *PanicTestSuite) TestAuthenticatorValidator() {
+	var event AuthenticateEvent[string]
+	a, err := NewAuthenticator(
+		WithAuthenticatePanicRecovery[string](suite.hook),
+		WithTokenParsers[string](
+			AsTokenParser[string](func(context.Context, string) (Token, error) {
+				return suite.testToken(), nil
+			}),
+		),
+		WithValidators(
+			AsValidator[string](func(Token) error {
+				panic(ErrBadCredentials)
+			}),
+		),
+		WithAuthenticateListenerFuncs(func(e AuthenticateEvent[string]) { event = e }),
+	)
+
+	suite.Require().NoError(err)
+
+	_, err = a.Authenticate(suite.testContext(), "source")
+	suite.ErrorIs(err, ErrBadCredentials)
+	suite.assertPanic(err, "bascule.validatorFunc[string]", ErrBadCredentials)
+
+	suite.Require().Len(event.Validations, 1)
+	suite.Equal("bascule.validatorFunc[string]", event.Validations[0].Name)
+	suite.ErrorIs(event.Validations[0].Err, ErrPanic)
+}
+
+func (suite *PanicTestSuite) TestAuthenticatorListener() {
+	var called bool
+	a, err := NewAuthenticator(
+		WithTokenParsers[string](
+			AsTokenParser[string](func(context.Context, string) (Token, error) {
+				return suite.testToken(), nil
+			}),
+		),
+		WithAuthenticateListenerFuncs(
+			func(AuthenticateEvent[string]) { panic("expected") },
+			func(AuthenticateEvent[string]) { called = true },
+		),
+		WithAuthenticatePanicRecovery[string](suite.hook),
+	)
+
+	suite.Require().NoError(err)
+
+	token, err := a.Authenticate(suite.testContext(), "source")
+	suite.NoError(err)
+	suite.Equal(suite.testToken(), token)
+	suite.True(called)
+	suite.assertPanic(nil, "bascule.ListenerFunc[github.com/xmidt-org/bascule.AuthenticateEvent[string]]", "expected")
+}
+
+func (suite *PanicTestSuite) TestAuthenticatorDisabled() {
+	a, err := NewAuthenticator(
+		WithTokenParsers[string](
+			AsTokenParser[string](func(context.Context, string) (Token, error) {
+				panic("expected")
+			}),
+		),
+	)
+
+	suite.Require().NoError(err)
+	suite.PanicsWithValue("expected", func() {
+		a.Authenticate(suite.testContext(), "source")
+	})
+}
+
+func (suite *PanicTestSuite) TestAuthorizerApprover() {
+	var (
+		approver2 = new(mockApprover[string])
+		event     AuthorizeEvent[string]
+	)
+
+	a, err := NewAuthorizer(
+		WithApproverFuncs(func(context.Context, string, Token) error {
+			panic("expected")
+		}),
+		WithApprovers[string](approver2),
+		WithAuthorizeListenerFuncs(func(e AuthorizeEvent[string]) { event = e }),
+		WithAuthorizePanicRecovery[string](nil),
+		WithAuthorizePanicRecovery[string](suite.hook),
+	)
+
+	suite.Require().NoError(err)
+
+	err = a.Authorize(suite.testContext(), "resource", suite.testToken())
+	suite.assertPanic(err, "bascule.ApproverFunc[string]", "expected")
+
+	denied, ok := event.Denied()
+	suite.True(ok)
+	suite.Equal("bascule.ApproverFunc[string]", denied.Name)
+	approver2.AssertExpectations(suite.T())
+}
+
+func (suite *PanicTestSuite) TestAuthorizerNilHook() {
+	a, err := NewAuthorizer(
+		WithApproverFuncs(func(context.Context, string, Token) error {
+			panic("expected")
+		}),
+		WithAuthorizeListenerFuncs(func(AuthorizeEvent[string]) {
+			panic("expected")
+		}),
+		WithAuthorizePanicRecovery[string](nil),
+	)
+
+	suite.Require().NoError(err)
+
+	err = a.Authorize(suite.testContext(), "resource", suite.testToken())
+	suite.ErrorIs(err, ErrPanic)
+}
+
+func (suite *PanicTestSuite) TestListenersRecover() {
+	suite.Run("Empty", func() {
+		suite.Nil(Listeners[int]{}.Recover(suite.hook))
+	})
+
+	suite.Run("Panic", func() {
+		suite.SetupTest()
+		var received []int
+		ls := Listeners[int]{}.AppendFunc(
+			func(int) { panic(errors.New("expected")) },
+			func(e int) { received = append(received, e) },
+		).Recover(suite.hook)
+
+		suite.Len(ls, 2)
+		suite.NotPanics(func() { ls.OnEvent(1) })
+		suite.Equal([]int{1}, received)
+		suite.Require().Len(suite.panics, 1)
+		suite.Equal("bascule.ListenerFunc[int]", suite.panics[0].Component)
+		suite.EqualError(suite.panics[0].Value.(error), "expected")
+	})
+}
+
+func TestPanic(t *testing.T) {
+	suite.Run(t, new(PanicTestSuite))
+}
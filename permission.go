@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+const (
+	// PermissionWildcard is the subpart that matches any value.
+	PermissionWildcard = "*"
+
+	// PermissionPartSeparator separates the parts of a permission, e.g. domain:action:instance.
+	PermissionPartSeparator = ":"
+
+	// PermissionSubpartSeparator separates the alternatives within a single part, e.g. read,write.
+	PermissionSubpartSeparator = ","
+
+	// MaxPermissionCombinations is the largest number of single-valued permissions that
+	// a Permission may be composed of, i.e. the product of the number of subparts in
+	// each part.  For example, "doc,mail:read,write" is composed of 4.  Since capabilities
+	// are often attacker-supplied, this bounds the work needed to index or check them.
+	MaxPermissionCombinations = 1024
+)
+
+var (
+	// ErrInvalidPermission indicates that a permission string was empty, contained
+	// an empty part or subpart, or exceeded MaxPermissionCombinations.
+	ErrInvalidPermission = errors.New("invalid permission")
+)
+
+// Permission is a hierarchical, wildcard permission in the style of Apache Shiro.  A
+// permission consists of one or more parts separated by colons, conventionally
+// domain:action:instance.  Each part is a comma-separated list of subparts, or the
+// wildcard "*".  For example, "printer:print,query:lp7200" permits both printing
+// and querying a particular printer.
+//
+// Permissions are case sensitive.  The zero value is not a valid permission and
+// implies nothing.
+type Permission struct {
+	// parts holds the sorted, deduplicated subparts of each part.  A wildcard
+	// part is represented by a slice containing only PermissionWildcard.
+	parts [][]string
+}
+
+// ParsePermission parses the text form of a permission.  Whitespace around parts and
+// subparts is ignored.  A part that contains the wildcard among other subparts is
+// considered to be a wildcard.  A permission composed of more than MaxPermissionCombinations
+// single-valued permissions is rejected.
+func ParsePermission(s string) (Permission, error) {
+	var (
+		p            Permission
+		combinations = 1
+	)
+
+	for _, part := range strings.Split(s, PermissionPartSeparator) {
+		var subparts []string
+		for _, subpart := range strings.Split(part, PermissionSubpartSeparator) {
+			subpart = strings.TrimSpace(subpart)
+			if len(subpart) == 0 {
+				return Permission{}, ErrInvalidPermission
+			}
+
+			if subpart == PermissionWildcard {
+				subparts = []string{PermissionWildcard}
+				break
+			}
+
+			subparts = append(subparts, subpart)
+		}
+
+		slices.Sort(subparts)
+		subparts = slices.Compact(subparts)
+		if combinations *= len(subparts); combinations > MaxPermissionCombinations {
+			return Permission{}, ErrInvalidPermission
+		}
+
+		p.parts = append(p.parts, subparts)
+	}
+
+	return p, nil
+}
+
+// MustParsePermission is like ParsePermission, but panics on any error.
+func MustParsePermission(s string) Permission {
+	p, err := ParsePermission(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// IsZero tests if this is the zero value Permission.
+func (p Permission) IsZero() bool {
+	return len(p.parts) == 0
+}
+
+// String returns the canonical text form of this permission, with subparts sorted.
+func (p Permission) String() string {
+	var o strings.Builder
+	for i, part := range p.parts {
+		if i > 0 {
+			o.WriteString(PermissionPartSeparator)
+		}
+
+		o.WriteString(strings.Join(part, PermissionSubpartSeparator))
+	}
+
+	return o.String()
+}
+
+// isWildcard tests if a part is the wildcard.
+func isWildcard(part []string) bool {
+	return len(part) == 1 && part[0] == PermissionWildcard
+}
+
+// Implies tests if this permission grants everything that q grants.  Each part of
+// this permission must either be a wildcard or contain every subpart of the
+// corresponding part of q.  A permission with fewer parts implies any permission
+// that extends it, e.g. "printer:print" implies "printer:print:lp7200".  A permission
+// with more parts only implies a shorter permission if the extra parts are wildcards.
+func (p Permission) Implies(q Permission) bool {
+	if p.IsZero() || q.IsZero() {
+		return false
+	}
+
+	for i, part := range p.parts {
+		switch {
+		case isWildcard(part):
+			continue
+
+		case i >= len(q.parts):
+			return false
+
+		case isWildcard(q.parts[i]):
+			return false
+
+		default:
+			for _, subpart := range q.parts[i] {
+				if _, found := slices.BinarySearch(part, subpart); !found {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// expand calls f with each single-valued permission that this permission is
+// composed of, i.e. the cross product of all subparts.  Wildcard parts are not
+// expanded.  Each slice passed to f is reused, so f must not retain it.  ParsePermission
+// bounds the number of calls to MaxPermissionCombinations.
+func (p Permission) expand(f func([]string) bool) bool {
+	current := make([]string, len(p.parts))
+	var visit func(int) bool
+	visit = func(i int) bool {
+		if i == len(p.parts) {
+			return f(current)
+		}
+
+		for _, subpart := range p.parts[i] {
+			current[i] = subpart
+			if !visit(i + 1) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return visit(0)
+}
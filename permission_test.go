@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PermissionTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PermissionTestSuite) TestParsePermission() {
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{text: "printer", expected: "printer"},
+		{text: "printer:print", expected: "printer:print"},
+		{text: "printer:query,print:lp7200", expected: "printer:print,query:lp7200"},
+		{text: " printer : print , query ", expected: "printer:print,query"},
+		{text: "printer:print,print", expected: "printer:print"},
+		{text: "printer:*", expected: "printer:*"},
+		{text: "printer:print,*", expected: "printer:*"},
+		{text: "*", expected: "*"},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.text, func() {
+			p, err := ParsePermission(testCase.text)
+			suite.Require().NoError(err)
+			suite.False(p.IsZero())
+			suite.Equal(testCase.expected, p.String())
+			suite.Equal(p, MustParsePermission(testCase.expected))
+		})
+	}
+}
+
+func (suite *PermissionTestSuite) TestParsePermissionInvalid() {
+	for _, text := range []string{"", " ", ":", "printer:", ":print", "printer::lp7200", "printer:print,"} {
+		suite.Run(text, func() {
+			p, err := ParsePermission(text)
+			suite.ErrorIs(err, ErrInvalidPermission)
+			suite.True(p.IsZero())
+		})
+	}
+
+	suite.Panics(func() {
+		MustParsePermission("")
+	})
+}
+
+func (suite *PermissionTestSuite) TestParsePermissionCombinations() {
+	subparts := func(n int) string {
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = strconv.Itoa(i)
+		}
+
+		return strings.Join(parts, PermissionSubpartSeparator)
+	}
+
+	// 32 * 32 == MaxPermissionCombinations
+	p, err := ParsePermission(subparts(32) + ":" + subparts(32) + ":*")
+	suite.Require().NoError(err)
+	suite.False(p.IsZero())
+
+	p, err = ParsePermission(subparts(32) + ":" + subparts(32) + ":a,b")
+	suite.ErrorIs(err, ErrInvalidPermission)
+	suite.True(p.IsZero())
+
+	// duplicates do not count toward the limit
+	_, err = ParsePermission(subparts(MaxPermissionCombinations) + ":a,a,a")
+	suite.NoError(err)
+}
+
+func (suite *PermissionTestSuite) TestImplies() {
+	testCases := []struct {
+		p, q     string
+		expected bool
+	}{
+		{p: "printer", q: "printer", expected: true},
+		{p: "printer", q: "printer:print", expected: true},
+		{p: "printer", q: "printer:print:lp7200", expected: true},
+		{p: "printer", q: "scanner"},
+		{p: "printer:print", q: "printer"},
+		{p: "printer:*", q: "printer", expected: true},
+		{p: "printer:*:*", q: "printer", expected: true},
+		{p: "printer:*:lp7200", q: "printer"},
+		{p: "printer:*", q: "printer:print", expected: true},
+		{p: "printer:*", q: "printer:print,query:lp7200", expected: true},
+		{p: "printer:print", q: "printer:*"},
+		{p: "printer:print,query", q: "printer:print", expected: true},
+		{p: "printer:print,query", q: "printer:print,query", expected: true},
+		{p: "printer:print", q: "printer:print,query"},
+		{p: "printer:*:lp7200", q: "printer:print:lp7200", expected: true},
+		{p: "printer:*:lp7200", q: "printer:print:epson"},
+		{p: "*", q: "anything:at:all", expected: true},
+		{p: "*:print", q: "printer:print", expected: true},
+		{p: "*:print", q: "printer:query"},
+		{p: "Printer", q: "printer"},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.p+" => "+testCase.q, func() {
+			p, q := MustParsePermission(testCase.p), MustParsePermission(testCase.q)
+			suite.Equal(testCase.expected, p.Implies(q))
+
+			// a set with a single member must agree
+			suite.Equal(testCase.expected, NewCapabilitySet(p).Implies(q))
+		})
+	}
+}
+
+func (suite *PermissionTestSuite) TestZero() {
+	var zero Permission
+	suite.True(zero.IsZero())
+	suite.Empty(zero.String())
+	suite.False(zero.Implies(MustParsePermission("*")))
+	suite.False(MustParsePermission("*").Implies(zero))
+}
+
+func TestPermission(t *testing.T) {
+	suite.Run(t, new(PermissionTestSuite))
+}
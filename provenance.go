@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Namer is an optional interface that workflow components, such as parsers,
+// validators, and approvers, can implement to control the name that is
+// reported for them in events.
+type Namer interface {
+	// Name returns a short, human-readable name for this component.
+	Name() string
+}
+
+// ComponentName returns the name reported in events for a workflow component.
+// If the component implements Namer, that name is used.  Otherwise, the name is
+// the component's Go type, e.g. "*basculehttp.AuthorizationParser".
+func ComponentName(component any) string {
+	if n, ok := component.(Namer); ok {
+		return n.Name()
+	}
+
+	return fmt.Sprintf("%T", component)
+}
+
+// ValidatorRecord describes the execution of a single validator during authentication.
+type ValidatorRecord struct {
+	// Index is the zero-based position of the validator within the Authenticator.
+	Index int
+
+	// Name is the validator's name, as reported by ComponentName.
+	Name string
+
+	// Duration is how long the validator took to run.
+	Duration time.Duration
+
+	// Replaced is true if the validator returned a different token, which was then
+	// used for the remainder of the workflow.
+	Replaced bool
+
+	// Err is the error the validator returned, if any.
+	Err error
+}
+
+// ApproverRecord describes the execution of a single approver during authorization.
+type ApproverRecord struct {
+	// Index is the zero-based position of the approver within the Authorizer.
+	Index int
+
+	// Name is the approver's name, as reported by ComponentName.
+	Name string
+
+	// Duration is how long the approver took to run.
+	Duration time.Duration
+
+	// Err is the error the approver returned, if any.  A non-nil error means that
+	// this approver denied access.
+	Err error
+}
+
+// sameToken tests if two tokens are the same.  Unlike ==, this function never
+// panics for tokens with uncomparable types, such as MultiToken.
+func sameToken(a, b Token) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Type() == vb.Type() && sameValue(va, vb)
+}
+
+// sameValue tests if two values of the same type are the same.  Comparable values
+// are compared with ==.  Slices, maps, and funcs are compared by identity, and
+// uncomparable structs and arrays are compared element by element using these rules.
+// This allows a struct token that holds a slice or map to be recognized as unchanged.
+func sameValue(va, vb reflect.Value) bool {
+	switch {
+	case va.Comparable():
+		return va.Equal(vb)
+
+	case va.Kind() == reflect.Slice:
+		return va.Len() == vb.Len() && va.Pointer() == vb.Pointer()
+
+	case va.Kind() == reflect.Map || va.Kind() == reflect.Func:
+		return va.Pointer() == vb.Pointer()
+
+	case va.Kind() == reflect.Interface:
+		if va.IsNil() || vb.IsNil() {
+			return va.IsNil() && vb.IsNil()
+		}
+
+		ea, eb := va.Elem(), vb.Elem()
+		return ea.Type() == eb.Type() && sameValue(ea, eb)
+
+	case va.Kind() == reflect.Struct:
+		for i := range va.NumField() {
+			if !sameValue(va.Field(i), vb.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+
+	case va.Kind() == reflect.Array:
+		for i := range va.Len() {
+			if !sameValue(va.Index(i), vb.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testNamer struct{}
+
+func (testNamer) Name() string { return "test namer" }
+
+type ProvenanceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ProvenanceTestSuite) TestComponentName() {
+	suite.Equal("test namer", ComponentName(testNamer{}))
+	suite.Equal("*bascule.mockValidator[string]", ComponentName(new(mockValidator[string])))
+	suite.Equal("bascule.StubToken", ComponentName(StubToken("test")))
+	suite.Equal("<nil>", ComponentName(nil))
+}
+
+func (suite *ProvenanceTestSuite) TestSameToken() {
+	var (
+		stub  = StubToken("test")
+		multi = MultiToken{stub, StubToken("other")}
+	)
+
+	suite.True(sameToken(nil, nil))
+	suite.False(sameToken(stub, nil))
+	suite.False(sameToken(nil, stub))
+
+	suite.True(sameToken(stub, StubToken("test")))
+	suite.False(sameToken(stub, StubToken("other")))
+
+	suite.True(sameToken(multi, multi))
+	suite.False(sameToken(multi, MultiToken{stub, StubToken("other")}))
+	suite.False(sameToken(multi, stub))
+
+	suite.Run("UncomparableStruct", func() {
+		var (
+			caps   = capabilitiesToken{StubToken: "test", capabilities: []string{"doc:read"}}
+			copied = capabilitiesToken{StubToken: "test", capabilities: []string{"doc:read"}}
+			set    = WithCapabilitySet(caps)
+		)
+
+		suite.True(sameToken(caps, caps))
+		suite.False(sameToken(caps, copied))
+		suite.False(sameToken(caps, capabilitiesToken{StubToken: "other", capabilities: caps.capabilities}))
+		suite.True(sameToken(set, set))
+		suite.False(sameToken(set, WithCapabilitySet(caps)))
+	})
+}
+
+func (suite *ProvenanceTestSuite) TestPassThroughValidator() {
+	a, err := NewAuthenticator(
+		WithTokenParsers(TokenParser[string](StubTokenParser[string]{})),
+		WithValidators(
+			AsValidator[string](func(t Token) (Token, error) { return t, nil }),
+		),
+	)
+
+	suite.Require().NoError(err)
+
+	token := WithCapabilitySet(capabilitiesToken{StubToken: "test", capabilities: []string{"doc:read"}})
+	next, validations, err := a.validate(context.Background(), "source", token)
+	suite.Require().NoError(err)
+	suite.True(sameToken(token, next))
+	suite.Require().Len(validations, 1)
+	suite.False(validations[0].Replaced)
+}
+
+func TestProvenance(t *testing.T) {
+	suite.Run(t, new(ProvenanceTestSuite))
+}
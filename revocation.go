@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrRevokedCredentials indicates that a token was well-formed and otherwise valid,
+	// but has been revoked before its natural expiration.
+	ErrRevokedCredentials = errors.New("revoked credentials")
+
+	// ErrInvalidRevocationConfig indicates that a revocation validator or store
+	// was given an invalid configuration, e.g. a nil store.
+	ErrInvalidRevocationConfig = errors.New("invalid revocation configuration")
+
+	// ErrInvalidRevocation indicates that an attempt to revoke a key was rejected,
+	// e.g. because the key was empty or the TTL was negative.
+	ErrInvalidRevocation = errors.New("invalid revocation")
+)
+
+const (
+	// PrincipalRevocationKind is the kind of the keys produced by PrincipalRevocationKey.
+	PrincipalRevocationKind = "principal"
+
+	// RevocationKindSeparator separates the kind of a revocation key from its identifier.
+	RevocationKindSeparator = ":"
+)
+
+// RevocationKey produces the key checked against a RevocationStore for an identifier
+// of the given kind, e.g. "principal:joe".  Qualifying each identifier with its kind
+// gives each kind its own namespace within a store, so that revoking a principal never
+// revokes a token whose id happens to be the same string, and vice versa.
+func RevocationKey(kind, id string) string {
+	return kind + RevocationKindSeparator + id
+}
+
+// RevocationKeyFunc extracts the key of a token that is checked against a RevocationStore,
+// e.g. one based on a JWT's jti.  Keys should be produced with RevocationKey, using a kind
+// that is distinct from that of every other RevocationKeyFunc.  If the token carries no
+// such identifier, this closure must return false, in which case no check is made for
+// this closure.
+type RevocationKeyFunc func(Token) (string, bool)
+
+// PrincipalRevocationKey is a RevocationKeyFunc that uses a token's principal, qualified
+// by PrincipalRevocationKind.  This allows all tokens for a principal to be revoked at
+// once.  Tokens with an empty principal are not checked.
+func PrincipalRevocationKey(t Token) (string, bool) {
+	principal := t.Principal()
+	return RevocationKey(PrincipalRevocationKind, principal), len(principal) > 0
+}
+
+// RevocationStore is a deny list of revoked token identifiers.
+type RevocationStore interface {
+	// IsRevoked tests if the given key has been revoked.  If this method
+	// returns an error, validation fails with that error.  Stores are thus
+	// expected to fail closed.
+	IsRevoked(ctx context.Context, key string) (bool, error)
+}
+
+// RevocationEditor is a RevocationStore whose entries can be changed at runtime,
+// e.g. through an administrative API.
+type RevocationEditor interface {
+	RevocationStore
+
+	// Revoke adds a key to this store.  A positive ttl causes the revocation to
+	// lapse after that amount of time, which is typically the remaining lifetime
+	// of the revoked token.  A zero ttl never lapses.  A negative ttl or an empty
+	// key is rejected with ErrInvalidRevocation.
+	Revoke(ctx context.Context, key string, ttl time.Duration) error
+
+	// Unrevoke removes a key from this store.  Removing a key that is not
+	// present is not an error.
+	Unrevoke(ctx context.Context, key string) error
+}
+
+// RevocationValidatorOption is a configurable option for a revocation validator.
+type RevocationValidatorOption interface {
+	apply(*revocationValidatorConfig) error
+}
+
+type revocationValidatorOptionFunc func(*revocationValidatorConfig) error
+
+func (rvof revocationValidatorOptionFunc) apply(c *revocationValidatorConfig) error { return rvof(c) }
+
+// WithRevocationKeys sets the closures used to extract identifiers from tokens.
+// Multiple invocations of this option are cumulative.  A token is rejected if any
+// of its identifiers is revoked.  If this option is not supplied,
+// PrincipalRevocationKey is used.
+func WithRevocationKeys(kfs ...RevocationKeyFunc) RevocationValidatorOption {
+	return revocationValidatorOptionFunc(func(c *revocationValidatorConfig) error {
+		for _, kf := range kfs {
+			if kf == nil {
+				return ErrInvalidRevocationConfig
+			}
+		}
+
+		c.keyFuncs = append(c.keyFuncs, kfs...)
+		return nil
+	})
+}
+
+type revocationValidatorConfig struct {
+	keyFuncs []RevocationKeyFunc
+}
+
+type revocationValidator[S any] struct {
+	store    RevocationStore
+	keyFuncs []RevocationKeyFunc
+}
+
+// NewRevocationValidator creates a Validator that rejects tokens whose identifiers
+// are present in the given store.  A revoked token fails with ErrRevokedCredentials.
+// If the store returns an error, that error is returned as is and the token is
+// not validated.
+//
+// If the Authenticator also uses an AuthenticateCache, cached tokens are not checked
+// against the store until their cache entries expire.  Use InvalidateOnRevoke so that
+// revocations made through a RevocationEditor take effect immediately.  Stores that
+// change by other means, such as a FileRevocationStore, should be paired with a short
+// cache TTL.
+func NewRevocationValidator[S any](store RevocationStore, opts ...RevocationValidatorOption) (Validator[S], error) {
+	if store == nil {
+		return nil, ErrInvalidRevocationConfig
+	}
+
+	var c revocationValidatorConfig
+	for _, o := range opts {
+		if err := o.apply(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.keyFuncs) == 0 {
+		c.keyFuncs = []RevocationKeyFunc{PrincipalRevocationKey}
+	}
+
+	return &revocationValidator[S]{
+		store:    store,
+		keyFuncs: c.keyFuncs,
+	}, nil
+}
+
+func (rv *revocationValidator[S]) Validate(ctx context.Context, _ S, t Token) (Token, error) {
+	for _, kf := range rv.keyFuncs {
+		key, ok := kf(t)
+		if !ok {
+			continue
+		}
+
+		revoked, err := rv.store.IsRevoked(ctx, key)
+		switch {
+		case err != nil:
+			return nil, err
+
+		case revoked:
+			return nil, ErrRevokedCredentials
+		}
+	}
+
+	return nil, nil
+}
+
+// cacheInvalidator is a RevocationEditor decorator that removes revoked tokens
+// from an AuthenticateCache.
+type cacheInvalidator[S any] struct {
+	RevocationEditor
+	cache    *AuthenticateCache[S]
+	keyFuncs []RevocationKeyFunc
+}
+
+// InvalidateOnRevoke decorates a RevocationEditor so that each successful Revoke also
+// removes the affected tokens from an AuthenticateCache.  A cached token is removed if
+// any of the given closures produces the revoked key for it.  These closures should be
+// the same ones given to NewRevocationValidator via WithRevocationKeys.  If no closures
+// are supplied, PrincipalRevocationKey is used.
+//
+// The returned editor can be used anywhere the original can, e.g. with
+// basculehttp.NewRevocationHandler.
+func InvalidateOnRevoke[S any](editor RevocationEditor, cache *AuthenticateCache[S], kfs ...RevocationKeyFunc) (RevocationEditor, error) {
+	if editor == nil || cache == nil {
+		return nil, ErrInvalidRevocationConfig
+	}
+
+	for _, kf := range kfs {
+		if kf == nil {
+			return nil, ErrInvalidRevocationConfig
+		}
+	}
+
+	if len(kfs) == 0 {
+		kfs = []RevocationKeyFunc{PrincipalRevocationKey}
+	}
+
+	return &cacheInvalidator[S]{
+		RevocationEditor: editor,
+		cache:            cache,
+		keyFuncs:         append([]RevocationKeyFunc(nil), kfs...),
+	}, nil
+}
+
+// Revoke revokes the key, then removes any cached tokens with that key.
+func (ci *cacheInvalidator[S]) Revoke(ctx context.Context, key string, ttl time.Duration) error {
+	if err := ci.RevocationEditor.Revoke(ctx, key, ttl); err != nil {
+		return err
+	}
+
+	ci.cache.InvalidateFunc(func(t Token) bool {
+		for _, kf := range ci.keyFuncs {
+			if k, ok := kf(t); ok && k == key {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return nil
+}
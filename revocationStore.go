@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRevocationReloadInterval is the default minimum amount of time between
+	// checks for changes to a file-backed revocation store.
+	DefaultRevocationReloadInterval = 5 * time.Second
+)
+
+// RevocationStoreOption is a configurable option for the revocation stores in this package.
+type RevocationStoreOption interface {
+	apply(*revocationStoreConfig) error
+}
+
+type revocationStoreOptionFunc func(*revocationStoreConfig) error
+
+func (rsof revocationStoreOptionFunc) apply(c *revocationStoreConfig) error { return rsof(c) }
+
+// WithRevocationClock sets the closure used to obtain the current time.  By default,
+// time.Now is used.  A nil closure restores the default.
+func WithRevocationClock(now func() time.Time) RevocationStoreOption {
+	return revocationStoreOptionFunc(func(c *revocationStoreConfig) error {
+		c.now = now
+		return nil
+	})
+}
+
+// WithRevocationReloadInterval sets the minimum time between checks for changes to
+// the file backing a FileRevocationStore.  A zero interval checks the file on every
+// lookup.  This option has no effect on a MemoryRevocationStore.  If this option is
+// not supplied, DefaultRevocationReloadInterval is used.
+func WithRevocationReloadInterval(interval time.Duration) RevocationStoreOption {
+	return revocationStoreOptionFunc(func(c *revocationStoreConfig) error {
+		if interval < 0 {
+			return ErrInvalidRevocationConfig
+		}
+
+		c.reloadInterval = interval
+		return nil
+	})
+}
+
+type revocationStoreConfig struct {
+	now            func() time.Time
+	reloadInterval time.Duration
+}
+
+func newRevocationStoreConfig(opts []RevocationStoreOption) (c revocationStoreConfig, err error) {
+	c.reloadInterval = DefaultRevocationReloadInterval
+	for i := 0; err == nil && i < len(opts); i++ {
+		err = opts[i].apply(&c)
+	}
+
+	if c.now == nil {
+		c.now = time.Now
+	}
+
+	return
+}
+
+// MemoryRevocationStore is an in-process RevocationEditor.  Each revoked key may
+// have a TTL, after which the revocation lapses.  Lapsed entries are evicted lazily,
+// either when looked up or when another key is revoked.
+//
+// A MemoryRevocationStore is safe for concurrent use.
+type MemoryRevocationStore struct {
+	now func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]time.Time // the zero time means the entry never lapses
+}
+
+var _ RevocationEditor = (*MemoryRevocationStore)(nil)
+
+// NewMemoryRevocationStore creates an empty MemoryRevocationStore.
+func NewMemoryRevocationStore(opts ...RevocationStoreOption) (*MemoryRevocationStore, error) {
+	c, err := newRevocationStoreConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryRevocationStore{
+		now:     c.now,
+		entries: make(map[string]time.Time),
+	}, nil
+}
+
+// lapsed tests if an entry's expiry has passed.
+func lapsed(expires, now time.Time) bool {
+	return !expires.IsZero() && !now.Before(expires)
+}
+
+// IsRevoked tests if the key has been revoked and has not lapsed.  This method
+// never returns an error.
+func (mrs *MemoryRevocationStore) IsRevoked(_ context.Context, key string) (bool, error) {
+	mrs.lock.Lock()
+	defer mrs.lock.Unlock()
+
+	expires, revoked := mrs.entries[key]
+	if revoked && lapsed(expires, mrs.now()) {
+		delete(mrs.entries, key)
+		revoked = false
+	}
+
+	return revoked, nil
+}
+
+// Revoke adds a key to this store.  Revoking a key that is already present
+// replaces its TTL.
+func (mrs *MemoryRevocationStore) Revoke(_ context.Context, key string, ttl time.Duration) error {
+	if len(key) == 0 || ttl < 0 {
+		return ErrInvalidRevocation
+	}
+
+	mrs.lock.Lock()
+	defer mrs.lock.Unlock()
+
+	now := mrs.now()
+	for k, expires := range mrs.entries {
+		if lapsed(expires, now) {
+			delete(mrs.entries, k)
+		}
+	}
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = now.Add(ttl)
+	}
+
+	mrs.entries[key] = expires
+	return nil
+}
+
+// Unrevoke removes a key from this store.
+func (mrs *MemoryRevocationStore) Unrevoke(_ context.Context, key string) error {
+	mrs.lock.Lock()
+	delete(mrs.entries, key)
+	mrs.lock.Unlock()
+	return nil
+}
+
+// Len returns the number of entries in this store, including any lapsed entries
+// that have not yet been evicted.
+func (mrs *MemoryRevocationStore) Len() (n int) {
+	mrs.lock.Lock()
+	n = len(mrs.entries)
+	mrs.lock.Unlock()
+	return
+}
+
+// FileRevocationStore is a read-only RevocationStore backed by a text file with one
+// revoked key per line.  Keys are written in the kind-qualified form produced by
+// RevocationKey, e.g. "principal:joe".  Leading and trailing whitespace is ignored,
+// as are blank lines and lines beginning with '#'.
+//
+// The file is checked for changes to its size or modification time at most once per
+// reload interval, during a lookup.  If the file cannot be read, lookups fail with
+// that error until the file is readable again.  The previously loaded keys are
+// never used in that case, so that this store fails closed.
+//
+// A FileRevocationStore is safe for concurrent use.
+type FileRevocationStore struct {
+	path           string
+	now            func() time.Time
+	reloadInterval time.Duration
+
+	lock      sync.Mutex
+	keys      map[string]bool
+	modTime   time.Time
+	size      int64
+	nextCheck time.Time
+}
+
+var _ RevocationStore = (*FileRevocationStore)(nil)
+
+// NewFileRevocationStore creates a FileRevocationStore for the given path.  The file
+// is loaded immediately, and any error reading it is returned.
+func NewFileRevocationStore(path string, opts ...RevocationStoreOption) (*FileRevocationStore, error) {
+	c, err := newRevocationStoreConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	frs := &FileRevocationStore{
+		path:           path,
+		now:            c.now,
+		reloadInterval: c.reloadInterval,
+	}
+
+	if err := frs.Reload(); err != nil {
+		return nil, err
+	}
+
+	return frs, nil
+}
+
+// IsRevoked tests if the key is present in the file, reloading the file first if
+// it has changed.
+func (frs *FileRevocationStore) IsRevoked(_ context.Context, key string) (bool, error) {
+	frs.lock.Lock()
+	defer frs.lock.Unlock()
+
+	if !frs.now().Before(frs.nextCheck) {
+		if err := frs.reload(false); err != nil {
+			return false, err
+		}
+	}
+
+	return frs.keys[key], nil
+}
+
+// Reload unconditionally rereads the file backing this store.
+func (frs *FileRevocationStore) Reload() error {
+	frs.lock.Lock()
+	defer frs.lock.Unlock()
+	return frs.reload(true)
+}
+
+// reload rereads the file if it has changed or if force is set.  This method
+// must be executed under the lock.
+func (frs *FileRevocationStore) reload(force bool) error {
+	info, err := os.Stat(frs.path)
+	if err != nil {
+		frs.keys = nil
+		return err
+	}
+
+	if force || frs.keys == nil || !info.ModTime().Equal(frs.modTime) || info.Size() != frs.size {
+		data, err := os.ReadFile(frs.path) //nolint:gosec // G304: the path is trusted configuration
+		if err != nil {
+			frs.keys = nil
+			return err
+		}
+
+		frs.keys = parseRevocationFile(data)
+		frs.modTime = info.ModTime()
+		frs.size = info.Size()
+	}
+
+	frs.nextCheck = frs.now().Add(frs.reloadInterval)
+	return nil
+}
+
+// parseRevocationFile parses the contents of a revocation file into a set of keys.
+func parseRevocationFile(data []byte) map[string]bool {
+	keys := make(map[string]bool)
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && line[0] != '#' {
+			keys[line] = true
+		}
+	}
+
+	return keys
+}
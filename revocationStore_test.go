@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RevocationStoreTestSuite struct {
+	TestSuite
+
+	now time.Time
+}
+
+func (suite *RevocationStoreTestSuite) SetupTest() {
+	suite.now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *RevocationStoreTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *RevocationStoreTestSuite) newMemoryStore() *MemoryRevocationStore {
+	mrs, err := NewMemoryRevocationStore(WithRevocationClock(suite.clock))
+	suite.Require().NoError(err)
+	suite.Require().NotNil(mrs)
+	return mrs
+}
+
+func (suite *RevocationStoreTestSuite) assertRevoked(store RevocationStore, key string, expected bool) {
+	revoked, err := store.IsRevoked(suite.testContext(), key)
+	suite.Require().NoError(err)
+	suite.Equal(expected, revoked, "key: %s", key)
+}
+
+// writeFile writes a revocation file and returns its path.  The modification time
+// is set explicitly, since filesystem timestamps may be too coarse to detect changes.
+func (suite *RevocationStoreTestSuite) writeFile(path, contents string, modTime time.Time) string {
+	if len(path) == 0 {
+		path = filepath.Join(suite.T().TempDir(), "revoked.txt")
+	}
+
+	suite.Require().NoError(os.WriteFile(path, []byte(contents), 0600))
+	suite.Require().NoError(os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func (suite *RevocationStoreTestSuite) TestInvalidConfig() {
+	mrs, err := NewMemoryRevocationStore(WithRevocationReloadInterval(-1))
+	suite.ErrorIs(err, ErrInvalidRevocationConfig)
+	suite.Nil(mrs)
+
+	frs, err := NewFileRevocationStore("nosuch", WithRevocationReloadInterval(-1))
+	suite.ErrorIs(err, ErrInvalidRevocationConfig)
+	suite.Nil(frs)
+}
+
+func (suite *RevocationStoreTestSuite) TestMemoryRevoke() {
+	mrs := suite.newMemoryStore()
+	suite.ErrorIs(mrs.Revoke(suite.testContext(), "", 0), ErrInvalidRevocation)
+	suite.ErrorIs(mrs.Revoke(suite.testContext(), "key", -time.Second), ErrInvalidRevocation)
+	suite.Zero(mrs.Len())
+
+	suite.NoError(mrs.Revoke(suite.testContext(), "forever", 0))
+	suite.NoError(mrs.Revoke(suite.testContext(), "lapses", time.Minute))
+	suite.Equal(2, mrs.Len())
+
+	suite.assertRevoked(mrs, "forever", true)
+	suite.assertRevoked(mrs, "lapses", true)
+	suite.assertRevoked(mrs, "other", false)
+
+	suite.NoError(mrs.Unrevoke(suite.testContext(), "forever"))
+	suite.NoError(mrs.Unrevoke(suite.testContext(), "nosuch"))
+	suite.assertRevoked(mrs, "forever", false)
+	suite.Equal(1, mrs.Len())
+}
+
+func (suite *RevocationStoreTestSuite) TestMemoryTTL() {
+	mrs := suite.newMemoryStore()
+	suite.Require().NoError(mrs.Revoke(suite.testContext(), "forever", 0))
+	suite.Require().NoError(mrs.Revoke(suite.testContext(), "first", time.Minute))
+	suite.Require().NoError(mrs.Revoke(suite.testContext(), "second", time.Minute))
+
+	suite.now = suite.now.Add(59 * time.Second)
+	suite.assertRevoked(mrs, "first", true)
+
+	// lookups evict lapsed entries
+	suite.now = suite.now.Add(time.Second)
+	suite.assertRevoked(mrs, "first", false)
+	suite.Equal(2, mrs.Len())
+
+	// revoking sweeps all lapsed entries
+	suite.Require().NoError(mrs.Revoke(suite.testContext(), "third", time.Hour))
+	suite.Equal(2, mrs.Len())
+	suite.assertRevoked(mrs, "forever", true)
+	suite.assertRevoked(mrs, "second", false)
+	suite.assertRevoked(mrs, "third", true)
+
+	// revoking again replaces the TTL
+	suite.Require().NoError(mrs.Revoke(suite.testContext(), "third", 0))
+	suite.now = suite.now.Add(2 * time.Hour)
+	suite.assertRevoked(mrs, "third", true)
+}
+
+func (suite *RevocationStoreTestSuite) TestFileMissing() {
+	frs, err := NewFileRevocationStore(filepath.Join(suite.T().TempDir(), "nosuch"))
+	suite.ErrorIs(err, os.ErrNotExist)
+	suite.Nil(frs)
+}
+
+func (suite *RevocationStoreTestSuite) TestFile() {
+	path := suite.writeFile(
+		"",
+		"# revoked keys\n\n  first  \nsecond\r\n#third\n",
+		suite.now,
+	)
+
+	frs, err := NewFileRevocationStore(path, WithRevocationClock(suite.clock), WithRevocationReloadInterval(time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().NotNil(frs)
+
+	suite.assertRevoked(frs, "first", true)
+	suite.assertRevoked(frs, "second", true)
+	suite.assertRevoked(frs, "third", false)
+	suite.assertRevoked(frs, "#third", false)
+
+	// changes are not seen until the reload interval elapses
+	suite.writeFile(path, "third\n", suite.now.Add(time.Second))
+	suite.assertRevoked(frs, "first", true)
+	suite.assertRevoked(frs, "third", false)
+
+	suite.now = suite.now.Add(time.Minute)
+	suite.assertRevoked(frs, "first", false)
+	suite.assertRevoked(frs, "third", true)
+
+	// Reload forces a reread
+	suite.writeFile(path, "fourth\n", suite.now.Add(time.Second))
+	suite.Require().NoError(frs.Reload())
+	suite.assertRevoked(frs, "third", false)
+	suite.assertRevoked(frs, "fourth", true)
+}
+
+func (suite *RevocationStoreTestSuite) TestFileUnchanged() {
+	path := suite.writeFile("", "first\n", suite.now)
+	frs, err := NewFileRevocationStore(path, WithRevocationClock(suite.clock), WithRevocationReloadInterval(0))
+	suite.Require().NoError(err)
+
+	// same size and modification time, so the file is not reread
+	suite.writeFile(path, "other\n", suite.now)
+	suite.assertRevoked(frs, "first", true)
+	suite.assertRevoked(frs, "other", false)
+}
+
+func (suite *RevocationStoreTestSuite) TestFileFailsClosed() {
+	path := suite.writeFile("", "first\n", suite.now)
+	frs, err := NewFileRevocationStore(path, WithRevocationClock(suite.clock), WithRevocationReloadInterval(0))
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(os.Remove(path))
+	_, err = frs.IsRevoked(suite.testContext(), "other")
+	suite.ErrorIs(err, os.ErrNotExist)
+
+	// once the file is back, lookups succeed again
+	suite.writeFile(path, "second\n", suite.now)
+	suite.assertRevoked(frs, "first", false)
+	suite.assertRevoked(frs, "second", true)
+}
+
+func TestRevocationStore(t *testing.T) {
+	suite.Run(t, new(RevocationStoreTestSuite))
+}
@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2024 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package bascule
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// revocationStoreFunc is a closure type that implements RevocationStore.
+type revocationStoreFunc func(context.Context, string) (bool, error)
+
+func (rsf revocationStoreFunc) IsRevoked(ctx context.Context, key string) (bool, error) {
+	return rsf(ctx, key)
+}
+
+// revocationToken is a Token with an identifier distinct from its principal.
+type revocationToken struct {
+	principal string
+	id        string
+}
+
+func (rt revocationToken) Principal() string { return rt.principal }
+
+type RevocationTestSuite struct {
+	TestSuite
+
+	store *MemoryRevocationStore
+}
+
+func (suite *RevocationTestSuite) SetupTest() {
+	var err error
+	suite.store, err = NewMemoryRevocationStore()
+	suite.Require().NoError(err)
+}
+
+func (suite *RevocationTestSuite) newValidator(store RevocationStore, opts ...RevocationValidatorOption) Validator[string] {
+	v, err := NewRevocationValidator[string](store, opts...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(v)
+	return v
+}
+
+// idKey is a RevocationKeyFunc that uses the id of a revocationToken.
+func (suite *RevocationTestSuite) idKey(t Token) (string, bool) {
+	rt, ok := t.(revocationToken)
+	return RevocationKey("id", rt.id), ok
+}
+
+func (suite *RevocationTestSuite) TestPrincipalRevocationKey() {
+	key, ok := PrincipalRevocationKey(StubToken("joe"))
+	suite.True(ok)
+	suite.Equal("principal:joe", key)
+
+	_, ok = PrincipalRevocationKey(StubToken(""))
+	suite.False(ok)
+}
+
+func (suite *RevocationTestSuite) TestInvalidConfig() {
+	suite.Run("NilStore", func() {
+		v, err := NewRevocationValidator[string](nil)
+		suite.ErrorIs(err, ErrInvalidRevocationConfig)
+		suite.Nil(v)
+	})
+
+	suite.Run("NilKeyFunc", func() {
+		v, err := NewRevocationValidator[string](suite.store, WithRevocationKeys(nil))
+		suite.ErrorIs(err, ErrInvalidRevocationConfig)
+		suite.Nil(v)
+	})
+}
+
+func (suite *RevocationTestSuite) TestPrincipal() {
+	v := suite.newValidator(suite.store)
+	suite.Require().NoError(suite.store.Revoke(suite.testContext(), "principal:revoked", 0))
+
+	next, err := v.Validate(suite.testContext(), "source", StubToken("joe"))
+	suite.NoError(err)
+	suite.Nil(next)
+
+	next, err = v.Validate(suite.testContext(), "source", StubToken("revoked"))
+	suite.ErrorIs(err, ErrRevokedCredentials)
+	suite.Nil(next)
+}
+
+func (suite *RevocationTestSuite) TestCustomKeys() {
+	var (
+		v = suite.newValidator(
+			suite.store,
+			WithRevocationKeys(suite.idKey),
+			WithRevocationKeys(PrincipalRevocationKey),
+		)
+
+		revokedID        = revocationToken{principal: "joe", id: "revoked-id"}
+		revokedPrincipal = revocationToken{principal: "revoked-principal", id: "good-id"}
+		good             = revocationToken{principal: "joe", id: "good-id"}
+	)
+
+	suite.Require().NoError(suite.store.Revoke(suite.testContext(), "id:revoked-id", 0))
+	suite.Require().NoError(suite.store.Revoke(suite.testContext(), "principal:revoked-principal", 0))
+
+	_, err := v.Validate(suite.testContext(), "source", revokedID)
+	suite.ErrorIs(err, ErrRevokedCredentials)
+
+	_, err = v.Validate(suite.testContext(), "source", revokedPrincipal)
+	suite.ErrorIs(err, ErrRevokedCredentials)
+
+	_, err = v.Validate(suite.testContext(), "source", good)
+	suite.NoError(err)
+
+	// tokens without a key are not checked
+	_, err = v.Validate(suite.testContext(), "source", StubToken("joe"))
+	suite.NoError(err)
+}
+
+func (suite *RevocationTestSuite) TestKeyNamespaces() {
+	v := suite.newValidator(
+		suite.store,
+		WithRevocationKeys(suite.idKey),
+		WithRevocationKeys(PrincipalRevocationKey),
+	)
+
+	suite.Require().NoError(suite.store.Revoke(suite.testContext(), RevocationKey(PrincipalRevocationKind, "abc"), 0))
+
+	// a token id equal to a revoked principal is not revoked
+	_, err := v.Validate(suite.testContext(), "source", revocationToken{principal: "joe", id: "abc"})
+	suite.NoError(err)
+
+	_, err = v.Validate(suite.testContext(), "source", revocationToken{principal: "abc", id: "xyz"})
+	suite.ErrorIs(err, ErrRevokedCredentials)
+
+	suite.Require().NoError(suite.store.Unrevoke(suite.testContext(), RevocationKey(PrincipalRevocationKind, "abc")))
+	suite.Require().NoError(suite.store.Revoke(suite.testContext(), RevocationKey("id", "abc"), 0))
+
+	// a principal equal to a revoked token id is not revoked
+	_, err = v.Validate(suite.testContext(), "source", revocationToken{principal: "abc", id: "xyz"})
+	suite.NoError(err)
+
+	_, err = v.Validate(suite.testContext(), "source", revocationToken{principal: "joe", id: "abc"})
+	suite.ErrorIs(err, ErrRevokedCredentials)
+}
+
+func (suite *RevocationTestSuite) TestStoreError() {
+	expectedErr := errors.New("expected")
+	v := suite.newValidator(
+		revocationStoreFunc(func(ctx context.Context, key string) (bool, error) {
+			suite.Equal(suite.testContext(), ctx)
+			suite.Equal("principal:joe", key)
+			return true, expectedErr
+		}),
+	)
+
+	_, err := v.Validate(suite.testContext(), "source", StubToken("joe"))
+	suite.ErrorIs(err, expectedErr)
+	suite.NotErrorIs(err, ErrRevokedCredentials)
+}
+
+func (suite *RevocationTestSuite) TestAuthenticator() {
+	a, err := NewAuthenticator(
+		WithTokenParsers(
+			StubTokenParser[string]{Token: StubToken("revoked")},
+		),
+		WithValidators(suite.newValidator(suite.store)),
+	)
+
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.store.Revoke(suite.testContext(), "principal:revoked", 0))
+
+	_, err = a.Authenticate(suite.testContext(), "source")
+	suite.ErrorIs(err, ErrRevokedCredentials)
+	suite.Equal(CategoryRevokedCredentials, CategorizeError(err))
+	suite.Equal("revoked credentials", SafeMessage(err))
+
+	var ae *AuthError
+	suite.Require().ErrorAs(err, &ae)
+	suite.Equal(StageValidate, ae.Stage)
+	suite.Equal("*bascule.revocationValidator[string]", ae.Component)
+}
+
+func (suite *RevocationTestSuite) TestInvalidateOnRevoke() {
+	cache, err := NewAuthenticateCache(func(source string) ([]byte, bool) {
+		return []byte(source), len(source) > 0
+	})
+
+	suite.Require().NoError(err)
+
+	suite.Run("InvalidConfig", func() {
+		_, err := InvalidateOnRevoke[string](nil, cache)
+		suite.ErrorIs(err, ErrInvalidRevocationConfig)
+
+		_, err = InvalidateOnRevoke[string](suite.store, nil)
+		suite.ErrorIs(err, ErrInvalidRevocationConfig)
+
+		_, err = InvalidateOnRevoke(suite.store, cache, nil)
+		suite.ErrorIs(err, ErrInvalidRevocationConfig)
+	})
+
+	suite.Run("Authenticator", func() {
+		editor, err := InvalidateOnRevoke(suite.store, cache, suite.idKey)
+		suite.Require().NoError(err)
+
+		a, err := NewAuthenticator(
+			WithTokenParsers(
+				AsTokenParser[string](func(source string) (Token, error) {
+					return revocationToken{principal: "joe", id: source}, nil
+				}),
+			),
+			WithValidators(suite.newValidator(suite.store, WithRevocationKeys(suite.idKey))),
+			WithAuthenticateCache(cache),
+		)
+
+		suite.Require().NoError(err)
+
+		for _, source := range []string{"token1", "token2"} {
+			_, err = a.Authenticate(suite.testContext(), source)
+			suite.Require().NoError(err)
+		}
+
+		suite.Equal(2, cache.Len())
+		suite.Require().NoError(editor.Revoke(suite.testContext(), "id:token1", 0))
+		suite.Equal(1, cache.Len())
+
+		_, err = a.Authenticate(suite.testContext(), "token1")
+		suite.ErrorIs(err, ErrRevokedCredentials)
+
+		_, err = a.Authenticate(suite.testContext(), "token2")
+		suite.NoError(err)
+
+		// other methods are passed through
+		revoked, err := editor.IsRevoked(suite.testContext(), "id:token1")
+		suite.NoError(err)
+		suite.True(revoked)
+
+		suite.Require().NoError(editor.Unrevoke(suite.testContext(), "id:token1"))
+		suite.Zero(suite.store.Len())
+	})
+
+	suite.Run("RevokeError", func() {
+		editor, err := InvalidateOnRevoke[string](suite.store, cache)
+		suite.Require().NoError(err)
+		suite.ErrorIs(editor.Revoke(suite.testContext(), "", 0), ErrInvalidRevocation)
+	})
+}
+
+func TestRevocation(t *testing.T) {
+	suite.Run(t, new(RevocationTestSuite))
+}
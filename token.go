@@ -7,6 +7,10 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
 )
 
 var (
@@ -265,18 +269,282 @@ func (tps TokenParsers[S]) Append(more ...TokenParser[S]) TokenParsers[S] {
 //
 // Otherwise, the token returned from the first successful parse is returned by
 // this aggregate method.
+//
+// Any error from a parser is wrapped in a *ParseError that records every parser
+// attempted.  The *ParseError unwraps to the error described above, so errors.Is
+// and errors.As work as if that error had been returned directly.
 func (tps TokenParsers[S]) Parse(ctx context.Context, source S) (t Token, err error) {
+	t, _, err = tps.parse(ctx, source)
+	return
+}
+
+// parse implements Parse, also returning the record of each parser attempted.
+func (tps TokenParsers[S]) parse(ctx context.Context, source S) (t Token, attempts []ParseAttempt, err error) {
 	if len(tps) == 0 {
 		err = ErrNoTokenParsers
+		return
 	}
 
 	for i := 0; i < len(tps) && t == nil && (err == nil || errors.Is(err, ErrMissingCredentials)); i++ {
 		t, err = tps[i].Parse(ctx, source)
+		attempts = append(attempts, ParseAttempt{
+			Index: i,
+			Name:  ComponentName(tps[i]),
+			Token: t,
+			Err:   err,
+		})
+	}
+
+	if err != nil {
+		err = &ParseError{
+			Attempts: attempts,
+		}
 	}
 
 	return
 }
 
+// ParseOutcome describes the result of a single parser within a TokenParsers.
+type ParseOutcome int
+
+const (
+	// ParseMatched indicates that the parser produced a token.
+	ParseMatched ParseOutcome = iota
+
+	// ParseSkipped indicates that the parser did not recognize any credentials
+	// in the source, typically by returning ErrMissingCredentials.
+	ParseSkipped
+
+	// ParseFailed indicates that the parser returned an error other than
+	// ErrMissingCredentials.
+	ParseFailed
+)
+
+// String returns a human-readable label for this outcome.
+func (po ParseOutcome) String() string {
+	switch po {
+	case ParseMatched:
+		return "matched"
+
+	case ParseSkipped:
+		return "skipped"
+
+	case ParseFailed:
+		return "failed"
+
+	default:
+		return "ParseOutcome(" + strconv.Itoa(int(po)) + ")"
+	}
+}
+
+// ParseAttempt records the invocation of a single parser within a TokenParsers.
+type ParseAttempt struct {
+	// Index is the zero-based position of the parser within its TokenParsers.
+	Index int
+
+	// Name is the parser's name, as reported by ComponentName.
+	Name string
+
+	// Token is the token the parser returned, if any.
+	Token Token
+
+	// Err is the error the parser returned, if any.
+	Err error
+}
+
+// Outcome classifies this attempt.
+func (pa ParseAttempt) Outcome() ParseOutcome {
+	switch {
+	case pa.Err == nil && pa.Token != nil:
+		return ParseMatched
+
+	case pa.Err == nil || errors.Is(pa.Err, ErrMissingCredentials):
+		return ParseSkipped
+
+	default:
+		return ParseFailed
+	}
+}
+
+// ParseError is returned by TokenParsers.Parse when no parser produced a token.  It
+// records each parser that was attempted, in order.  Parsers after a failure are
+// never invoked, so they do not appear in Attempts.
+type ParseError struct {
+	// Attempts holds the parsers that were invoked.  This slice is never empty.
+	Attempts []ParseAttempt
+}
+
+// Unwrap returns the error from the last parser attempted, which decided the
+// outcome.  This is the error TokenParsers.Parse would return without diagnostics.
+func (pe *ParseError) Unwrap() error {
+	return pe.Attempts[len(pe.Attempts)-1].Err
+}
+
+// Error returns the decisive error's text when only one parser was attempted.
+// Otherwise, the text describes every attempt.
+func (pe *ParseError) Error() string {
+	if len(pe.Attempts) == 1 {
+		return pe.Unwrap().Error()
+	}
+
+	var o strings.Builder
+	o.WriteString("token parsing failed:")
+	for i, pa := range pe.Attempts {
+		if i > 0 {
+			o.WriteRune(';')
+		}
+
+		o.WriteString(" [")
+		o.WriteString(strconv.Itoa(pa.Index))
+		o.WriteString("] ")
+		o.WriteString(pa.Outcome().String())
+		if pa.Err != nil {
+			o.WriteString(": ")
+			o.WriteString(pa.Err.Error())
+		}
+	}
+
+	return o.String()
+}
+
+// All returns a TokenParser that requires every parser in this aggregate to
+// produce a token.  This is useful when a source must carry more than one
+// credential, e.g. both a client certificate and a JWT.  Parsers wrapped with
+// OptionalTokenParser may instead report ErrMissingCredentials, in which case
+// they contribute nothing to the result.
+//
+// The returned TokenParser invokes every parser, even after a failure, so that all
+// failures can be reported together.  Each failure is wrapped in a *TokenParserError,
+// and the failures are aggregated into a single error.
+//
+// If every parser succeeds, the tokens are combined via JoinTokens.  Thus, a single
+// token is returned as is, while multiple tokens produce a MultiToken.  If only
+// optional parsers were configured and none produced a token, ErrMissingCredentials
+// is returned.  If this aggregate is empty, parsing returns ErrNoTokenParsers.
+//
+// Because failures are aggregated, the returned error may match several sentinels
+// with errors.Is, e.g. both ErrMissingCredentials and ErrBadCredentials.  CategorizeError
+// gives ErrMissingCredentials the lowest precedence, so such an error is categorized
+// by the other failures.  Use OnlyMissingCredentials to test whether every failure
+// was due to missing credentials.
+//
+// The returned TokenParser uses a copy of this aggregate, so subsequent changes
+// to this aggregate have no effect on it.
+func (tps TokenParsers[S]) All() TokenParser[S] {
+	return allTokenParsers[S](append(make(TokenParsers[S], 0, len(tps)), tps...))
+}
+
+// TokenParserError reports the failure of a single parser within All.
+type TokenParserError struct {
+	// Index is the zero-based position of the failing parser.
+	Index int
+
+	// Err is the error returned by the parser.
+	Err error
+}
+
+// Unwrap returns the parser's error.
+func (tpe *TokenParserError) Unwrap() error {
+	return tpe.Err
+}
+
+func (tpe *TokenParserError) Error() string {
+	var o strings.Builder
+	o.WriteString("token parser [")
+	o.WriteString(strconv.Itoa(tpe.Index))
+	o.WriteString("] failed: ")
+	if tpe.Err != nil {
+		o.WriteString(tpe.Err.Error())
+	}
+
+	return o.String()
+}
+
+// optionalTokenParser marks a TokenParser as optional within All.
+type optionalTokenParser[S any] struct {
+	TokenParser[S]
+}
+
+// OptionalTokenParser marks a parser as optional when used with TokenParsers.All.
+// An optional parser that returns ErrMissingCredentials is skipped.  Any other
+// error from an optional parser still fails the parse, since that indicates
+// credentials were present but unusable.
+//
+// Outside of All, the returned parser behaves exactly like the one it wraps.
+//
+// Decorators, such as those in basculemetrics and basculeotel, preserve this marker
+// by providing an 'Unwrap() TokenParser[S]' method that returns the decorated parser.
+func OptionalTokenParser[S any](tp TokenParser[S]) TokenParser[S] {
+	return optionalTokenParser[S]{
+		TokenParser: tp,
+	}
+}
+
+// Unwrap returns the parser that was marked as optional.
+func (otp optionalTokenParser[S]) Unwrap() TokenParser[S] {
+	return otp.TokenParser
+}
+
+// IsOptionalTokenParser tests if tp was marked with OptionalTokenParser.  Any
+// decorators that provide an 'Unwrap() TokenParser[S]' method are examined as well.
+func IsOptionalTokenParser[S any](tp TokenParser[S]) bool {
+	for tp != nil {
+		if _, ok := tp.(optionalTokenParser[S]); ok {
+			return true
+		}
+
+		u, ok := tp.(interface{ Unwrap() TokenParser[S] })
+		if !ok {
+			break
+		}
+
+		tp = u.Unwrap()
+	}
+
+	return false
+}
+
+type allTokenParsers[S any] TokenParsers[S]
+
+func (atp allTokenParsers[S]) Parse(ctx context.Context, source S) (Token, error) {
+	if len(atp) == 0 {
+		return nil, ErrNoTokenParsers
+	}
+
+	var (
+		tokens []Token
+		err    error
+	)
+
+	for i, tp := range atp {
+		optional := IsOptionalTokenParser(tp)
+		t, parseErr := tp.Parse(ctx, source)
+		switch {
+		case parseErr == nil:
+			tokens = append(tokens, t)
+
+		case optional && errors.Is(parseErr, ErrMissingCredentials):
+			// skip
+
+		default:
+			err = multierr.Append(err, &TokenParserError{
+				Index: i,
+				Err:   parseErr,
+			})
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t := JoinTokens(tokens...); t != nil {
+		return t, nil
+	}
+
+	return nil, ErrMissingCredentials
+}
+
 // StubToken is a dummy token useful to configure a stubbed out workflow.  Useful
 // in testing and in development.
 type StubToken string
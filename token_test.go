@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/multierr"
 )
 
 type TokenSuite struct {
@@ -434,6 +435,239 @@ func (suite *TokenParserSuite) TestTokenParsers() {
 	suite.Run("Fail", suite.testTokenParsersFail)
 }
 
+func (suite *TokenParserSuite) TestParseError() {
+	suite.Run("AllMissing", func() {
+		tps := suite.appendMissing(nil, 2)
+		t, err := tps.Parse(suite.expectedCtx, suite.expectedSource)
+		suite.Nil(t)
+		suite.ErrorIs(err, ErrMissingCredentials)
+
+		var pe *ParseError
+		suite.Require().ErrorAs(err, &pe)
+		suite.Equal(
+			[]ParseAttempt{
+				{Index: 0, Name: "*bascule.mockTokenParser[int]", Err: ErrMissingCredentials},
+				{Index: 1, Name: "*bascule.mockTokenParser[int]", Err: ErrMissingCredentials},
+			},
+			pe.Attempts,
+		)
+
+		suite.Equal(
+			"token parsing failed: [0] skipped: missing credentials; [1] skipped: missing credentials",
+			pe.Error(),
+		)
+	})
+
+	suite.Run("Decisive", func() {
+		tps := suite.appendMissing(nil, 1)
+		tps = tps.Append(suite.newParser(nil, ErrInvalidCredentials))
+		tps = suite.appendNoCall(tps, 1)
+
+		t, err := tps.Parse(suite.expectedCtx, suite.expectedSource)
+		suite.Nil(t)
+		suite.ErrorIs(err, ErrInvalidCredentials)
+		suite.NotErrorIs(err, ErrMissingCredentials)
+
+		var pe *ParseError
+		suite.Require().ErrorAs(err, &pe)
+		suite.Require().Len(pe.Attempts, 2)
+		suite.Equal(ParseSkipped, pe.Attempts[0].Outcome())
+		suite.Equal(ParseFailed, pe.Attempts[1].Outcome())
+		suite.Equal(ErrInvalidCredentials, pe.Unwrap())
+		assertTokenParsers(suite.T(), tps...)
+	})
+
+	suite.Run("Single", func() {
+		t, err := TokenParsers[int]{suite.newParser(nil, suite.expectedErr)}.Parse(suite.expectedCtx, suite.expectedSource)
+		suite.Nil(t)
+		suite.ErrorIs(err, suite.expectedErr)
+		suite.Equal(suite.expectedErr.Error(), err.Error())
+	})
+}
+
+func (suite *TokenParserSuite) TestParseAttempt() {
+	suite.Equal(ParseMatched, ParseAttempt{Token: StubToken("test")}.Outcome())
+	suite.Equal(ParseSkipped, ParseAttempt{}.Outcome())
+	suite.Equal(ParseSkipped, ParseAttempt{Err: fmt.Errorf("wrapped: %w", ErrMissingCredentials)}.Outcome())
+	suite.Equal(ParseFailed, ParseAttempt{Err: ErrBadCredentials}.Outcome())
+
+	suite.Equal("matched", ParseMatched.String())
+	suite.Equal("skipped", ParseSkipped.String())
+	suite.Equal("failed", ParseFailed.String())
+	suite.Equal("ParseOutcome(99)", ParseOutcome(99).String())
+}
+
+// newParser creates a mocked TokenParser that expects this suite's input
+// and returns the given results.
+func (suite *TokenParserSuite) newParser(t Token, err error) *mockTokenParser[int] {
+	m := new(mockTokenParser[int])
+	m.ExpectParse(suite.expectedCtx, suite.expectedSource).
+		Return(t, err).Once()
+
+	return m
+}
+
+func (suite *TokenParserSuite) testAllEmpty() {
+	var tps TokenParsers[int]
+	t, err := tps.All().Parse(suite.expectedCtx, suite.expectedSource)
+	suite.Nil(t)
+	suite.ErrorIs(err, ErrNoTokenParsers)
+}
+
+func (suite *TokenParserSuite) testAllSingle() {
+	m := suite.newParser(StubToken("only"), nil)
+	t, err := TokenParsers[int]{m}.All().Parse(suite.expectedCtx, suite.expectedSource)
+	suite.NoError(err)
+	suite.Equal(StubToken("only"), t)
+	m.AssertExpectations(suite.T())
+}
+
+func (suite *TokenParserSuite) testAllSuccess() {
+	m1 := suite.newParser(StubToken("cert"), nil)
+	m2 := suite.newParser(StubToken("jwt"), nil)
+
+	tps := TokenParsers[int]{m1, m2}
+	p := tps.All()
+	tps[0] = nil // changes to the aggregate should not affect the parser
+
+	t, err := p.Parse(suite.expectedCtx, suite.expectedSource)
+	suite.NoError(err)
+	suite.Equal(MultiToken{StubToken("cert"), StubToken("jwt")}, t)
+	suite.Equal("cert", t.Principal())
+	m1.AssertExpectations(suite.T())
+	m2.AssertExpectations(suite.T())
+}
+
+func (suite *TokenParserSuite) testAllFail() {
+	m1 := suite.newParser(nil, ErrMissingCredentials)
+	m2 := suite.newParser(StubToken("jwt"), nil)
+	m3 := suite.newParser(nil, suite.expectedErr)
+
+	t, err := TokenParsers[int]{m1, m2, m3}.All().Parse(suite.expectedCtx, suite.expectedSource)
+	suite.Nil(t)
+	suite.ErrorIs(err, ErrMissingCredentials)
+	suite.ErrorIs(err, suite.expectedErr)
+
+	errs := multierr.Errors(err)
+	suite.Require().Len(errs, 2)
+
+	var tpe *TokenParserError
+	suite.Require().ErrorAs(errs[0], &tpe)
+	suite.Equal(0, tpe.Index)
+	suite.Equal(ErrMissingCredentials, tpe.Err)
+	suite.Contains(tpe.Error(), "[0]")
+
+	suite.Require().ErrorAs(errs[1], &tpe)
+	suite.Equal(2, tpe.Index)
+	suite.Equal(suite.expectedErr, tpe.Err)
+
+	m1.AssertExpectations(suite.T())
+	m2.AssertExpectations(suite.T())
+	m3.AssertExpectations(suite.T())
+}
+
+func (suite *TokenParserSuite) testAllMixed() {
+	m1 := suite.newParser(nil, ErrMissingCredentials)
+	m2 := suite.newParser(nil, ErrBadCredentials)
+
+	t, err := TokenParsers[int]{m1, m2}.All().Parse(suite.expectedCtx, suite.expectedSource)
+	suite.Nil(t)
+	suite.ErrorIs(err, ErrMissingCredentials)
+	suite.ErrorIs(err, ErrBadCredentials)
+	suite.Equal(CategoryBadCredentials, CategorizeError(err))
+	suite.False(OnlyMissingCredentials(err))
+	m1.AssertExpectations(suite.T())
+	m2.AssertExpectations(suite.T())
+}
+
+func (suite *TokenParserSuite) testAllOptional() {
+	suite.Run("Missing", func() {
+		m1 := suite.newParser(StubToken("jwt"), nil)
+		m2 := suite.newParser(nil, ErrMissingCredentials)
+
+		t, err := TokenParsers[int]{m1, OptionalTokenParser[int](m2)}.All().Parse(suite.expectedCtx, suite.expectedSource)
+		suite.NoError(err)
+		suite.Equal(StubToken("jwt"), t)
+		m1.AssertExpectations(suite.T())
+		m2.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Present", func() {
+		m1 := suite.newParser(StubToken("jwt"), nil)
+		m2 := suite.newParser(StubToken("cert"), nil)
+
+		t, err := TokenParsers[int]{m1, OptionalTokenParser[int](m2)}.All().Parse(suite.expectedCtx, suite.expectedSource)
+		suite.NoError(err)
+		suite.Equal(MultiToken{StubToken("jwt"), StubToken("cert")}, t)
+		m1.AssertExpectations(suite.T())
+		m2.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Invalid", func() {
+		m1 := suite.newParser(StubToken("jwt"), nil)
+		m2 := suite.newParser(nil, suite.expectedErr)
+
+		t, err := TokenParsers[int]{m1, OptionalTokenParser[int](m2)}.All().Parse(suite.expectedCtx, suite.expectedSource)
+		suite.Nil(t)
+
+		var tpe *TokenParserError
+		suite.Require().ErrorAs(err, &tpe)
+		suite.Equal(1, tpe.Index)
+		suite.ErrorIs(err, suite.expectedErr)
+		m1.AssertExpectations(suite.T())
+		m2.AssertExpectations(suite.T())
+	})
+
+	suite.Run("AllMissing", func() {
+		m := suite.newParser(nil, ErrMissingCredentials)
+
+		t, err := TokenParsers[int]{OptionalTokenParser[int](m)}.All().Parse(suite.expectedCtx, suite.expectedSource)
+		suite.Nil(t)
+		suite.Equal(ErrMissingCredentials, err)
+		m.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Decorated", func() {
+		m1 := suite.newParser(StubToken("jwt"), nil)
+		m2 := suite.newParser(nil, ErrMissingCredentials)
+
+		decorated := recoverTokenParser[int]{next: OptionalTokenParser[int](m2)}
+		suite.True(IsOptionalTokenParser[int](decorated))
+
+		t, err := TokenParsers[int]{m1, decorated}.All().Parse(suite.expectedCtx, suite.expectedSource)
+		suite.NoError(err)
+		suite.Equal(StubToken("jwt"), t)
+		m1.AssertExpectations(suite.T())
+		m2.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Standalone", func() {
+		m := suite.newParser(StubToken("jwt"), nil)
+		t, err := OptionalTokenParser[int](m).Parse(suite.expectedCtx, suite.expectedSource)
+		suite.NoError(err)
+		suite.Equal(StubToken("jwt"), t)
+		m.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *TokenParserSuite) TestAll() {
+	suite.Run("Empty", suite.testAllEmpty)
+	suite.Run("Single", suite.testAllSingle)
+	suite.Run("Success", suite.testAllSuccess)
+	suite.Run("Fail", suite.testAllFail)
+	suite.Run("Mixed", suite.testAllMixed)
+	suite.Run("Optional", suite.testAllOptional)
+}
+
+func (suite *TokenParserSuite) TestIsOptionalTokenParser() {
+	m := new(mockTokenParser[int])
+	suite.False(IsOptionalTokenParser[int](nil))
+	suite.False(IsOptionalTokenParser[int](m))
+	suite.False(IsOptionalTokenParser[int](recoverTokenParser[int]{next: m}))
+	suite.True(IsOptionalTokenParser(OptionalTokenParser[int](m)))
+	suite.True(IsOptionalTokenParser[int](recoverTokenParser[int]{next: OptionalTokenParser[int](m)}))
+}
+
 func (suite *TokenParserSuite) TestStubTokenParser() {
 	stp := StubTokenParser[int]{
 		Token: StubToken("test"),